@@ -4,12 +4,16 @@ import { gql, useQuery } from "@apollo/client";
 
 const ALL_USERS_QUERY = gql`
   query {
-    users {
-      id
-      firstName
-      lastName
-      email
-      role
+    users(first: 20) {
+      edges {
+        node {
+          id
+          firstName
+          lastName
+          email
+          role
+        }
+      }
     }
   }
 `;
@@ -36,7 +40,7 @@ export default function QueryTest() {
             </tr>
           </thead>
           <tbody>
-            {data.users.map((user: any) => (
+            {data.users.edges.map(({ node: user }: any) => (
               <tr key={user.id} className="border-t">
                 <td className="px-2 py-1">{user.id}</td>
                 <td className="px-2 py-1">{user.firstName}</td>
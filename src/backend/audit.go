@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"budsafe/backend/audit"
+)
+
+const auditUsage = `usage: budsafe audit <command>
+
+commands:
+  verify   recompute the audit log's hash chain and report the first broken link`
+
+// runAudit implements the `budsafe audit` subcommand
+func runAudit(args []string) error {
+	if len(args) != 1 {
+		return errors.New(auditUsage)
+	}
+
+	switch args[0] {
+	case "verify":
+		db := connectDB()
+		defer db.Close()
+		checked, err := audit.Verify(context.Background(), db)
+		if err != nil {
+			return fmt.Errorf("%w (%d event(s) verified before it)", err, checked)
+		}
+		log.Printf("Audit log intact: %d event(s) verified", checked)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown audit command %q\n\n%s", args[0], auditUsage)
+	}
+}
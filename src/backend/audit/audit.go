@@ -0,0 +1,84 @@
+// Package audit ties changes to the requests that made them and checks the
+// audit log for tampering.
+//
+// Database triggers append an event to audit_events for every change to the
+// records users manage (see migrations/0017_audit_events.up.sql), with the
+// actor and the request ID and client IP that Middleware attaches to the
+// request context. Seal then chains the new events in the background: each
+// event's hash covers the previous event's hash, so Verify detects any
+// sealed event that was edited, removed or reordered.
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// HeaderRequestID carries the request ID in both directions
+const HeaderRequestID = "X-Request-ID"
+
+type contextKey struct{}
+
+// Request identifies the HTTP request a change was made in
+type Request struct {
+	ID string
+	IP string
+}
+
+// NewContext returns ctx carrying req
+func NewContext(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, req)
+}
+
+// FromContext returns the request ctx carries, or nil
+func FromContext(ctx context.Context) *Request {
+	req, _ := ctx.Value(contextKey{}).(*Request)
+	return req
+}
+
+// validRequestID is what a caller's request ID must look like to be kept
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware attaches a Request to the request context. The ID is the
+// caller's X-Request-ID, when it is sensible, or a new random one, and is
+// echoed in the response. With trustProxy, the client IP is the last
+// address in X-Forwarded-For, the one the proxy in front appended;
+// otherwise it is the connection's remote address.
+func Middleware(trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(HeaderRequestID)
+			if !validRequestID.MatchString(id) {
+				id = newID()
+			}
+			w.Header().Set(HeaderRequestID, id)
+			req := &Request{ID: id, IP: clientIP(r, trustProxy)}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), req)))
+		})
+	}
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		forwarded := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if ip := net.ParseIP(strings.TrimSpace(forwarded[len(forwarded)-1])); ip != nil {
+			return ip.String()
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serve(t *testing.T, trustProxy bool, r *http.Request) (*Request, *httptest.ResponseRecorder) {
+	t.Helper()
+	var got *Request
+	handler := Middleware(trustProxy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	require.NotNil(t, got)
+	return got, w
+}
+
+func TestMiddleware_KeepsOrGeneratesRequestID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/query", nil)
+	r.Header.Set(HeaderRequestID, "req-123")
+	req, w := serve(t, false, r)
+	assert.Equal(t, "req-123", req.ID)
+	assert.Equal(t, "req-123", w.Header().Get(HeaderRequestID))
+
+	for _, id := range []string{"", "has spaces", "<script>"} {
+		r := httptest.NewRequest(http.MethodPost, "/query", nil)
+		r.Header.Set(HeaderRequestID, id)
+		req, w := serve(t, false, r)
+		assert.Len(t, req.ID, 32, id)
+		assert.NotEqual(t, id, req.ID)
+		assert.Equal(t, req.ID, w.Header().Get(HeaderRequestID))
+	}
+}
+
+func TestMiddleware_ClientIP(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/query", nil)
+	r.RemoteAddr = "10.0.0.1:5123"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, 203.0.113.7")
+
+	req, _ := serve(t, false, r)
+	assert.Equal(t, "10.0.0.1", req.IP, "X-Forwarded-For is ignored without a trusted proxy")
+
+	req, _ = serve(t, true, r)
+	assert.Equal(t, "203.0.113.7", req.IP, "the address the proxy appended")
+
+	r.Header.Set("X-Forwarded-For", "garbage")
+	req, _ = serve(t, true, r)
+	assert.Equal(t, "10.0.0.1", req.IP)
+}
+
+func TestFromContext_Missing(t *testing.T) {
+	assert.Nil(t, FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
+
+func TestHash(t *testing.T) {
+	text := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	// sha256("1:a,3:bé,0:,~,"), as audit_event_digest_input encodes the fields
+	assert.Equal(t, "08f671036829acd9b660ae13b65c7fe05c59b03f7aeb983994c28de344d2b3e9", Hash(text("a"), text("bé"), text(""), sql.NullString{}))
+	// Text cannot move between fields, and empty is not null
+	assert.NotEqual(t, Hash(text("a|b"), text("c")), Hash(text("a"), text("b|c")))
+	assert.NotEqual(t, Hash(text("a"), text("")), Hash(text("a"), sql.NullString{}))
+	assert.Len(t, Genesis, 64)
+}
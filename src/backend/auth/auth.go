@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/auth"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 )
 
 // A private key for the context to avoid collisions
@@ -25,6 +27,11 @@ type AuthClient struct {
 type User struct {
 	UID    string
 	Email	 string
+	// Name is the display name from the token's "name" claim, if any
+	Name   string
+	// EmailVerified is the token's "email_verified" claim: Firebase has
+	// confirmed the account owns Email
+	EmailVerified bool
 }
 
 func Init(ctx context.Context) (*AuthClient, error) {
@@ -57,12 +64,11 @@ func (ac *AuthClient) Middleware(next http.Handler) http.Handler {
 		}
 
 		// 2. Validate the token format ("Bearer <token>")
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || strings.ToLower(tokenParts[0]) != "bearer" {
+		idToken, ok := bearerToken(authHeader)
+		if !ok {
 			http.Error(w, "Authorization header format must be Bearer {token}", http.StatusUnauthorized)
 			return
 		}
-		idToken := tokenParts[1]
 
 		// 3. Verify the token using our reusable function
 		user, err := ac.VerifyToken(r.Context(), idToken)
@@ -81,17 +87,54 @@ func (ac *AuthClient) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// WebsocketInit authenticates a graphql-ws connection. Browsers cannot set
+// headers on websocket requests, so clients send the same "Bearer <token>"
+// value as the "Authorization" entry of the connection_init payload.
+// Connections without one proceed unauthenticated, like HTTP requests.
+func (ac *AuthClient) WebsocketInit(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	authHeader := payload.Authorization()
+	if authHeader == "" {
+		return ctx, nil, nil
+	}
+
+	idToken, ok := bearerToken(authHeader)
+	if !ok {
+		return nil, nil, errors.New("authorization format must be Bearer {token}")
+	}
+	user, err := ac.VerifyToken(ctx, idToken)
+	if err != nil {
+		log.Printf("Error verifying websocket token: %v", err)
+		return nil, nil, errors.New("invalid authentication token")
+	}
+	return NewContext(ctx, user), nil, nil
+}
+
+// bearerToken extracts the token from a "Bearer <token>" header value
+func bearerToken(header string) (string, bool) {
+	tokenParts := strings.Split(header, " ")
+	if len(tokenParts) != 2 || strings.ToLower(tokenParts[0]) != "bearer" {
+		return "", false
+	}
+	return tokenParts[1], true
+}
+
 // VerifyToken is the reusable function that verifies a Firebase ID token.
-// It returns a User struct with the UID and email on success.
+// It returns a User struct with the UID, email and name on success.
+// Email and name are empty when the account has none (e.g. phone sign-in).
 func (ac *AuthClient) VerifyToken(ctx context.Context, idToken string) (*User, error) {
 	token, err := ac.Client.VerifyIDToken(ctx, idToken)
 	if err != nil {
 		return nil, fmt.Errorf("could not verify token: %w", err)
 	}
 
+	email, _ := token.Claims["email"].(string)
+	name, _ := token.Claims["name"].(string)
+	emailVerified, _ := token.Claims["email_verified"].(bool)
 	return &User{
-		UID:   token.UID,
-		Email: token.Claims["email"].(string),
+		UID:           token.UID,
+		Email:         email,
+		Name:          name,
+		EmailVerified: emailVerified,
 	}, nil
 }
 
@@ -0,0 +1,157 @@
+// Package calendar publishes compliance deadlines as iCalendar (RFC 5545)
+// feeds that calendar apps subscribe to.
+//
+// A feed is addressed by a secret token in its URL, because calendar apps
+// cannot send the API's bearer tokens. Only the token's hash is stored.
+package calendar
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// prodID identifies the generator of the feeds
+const prodID = "-//BudSafe//Compliance Calendar//EN"
+
+// Calendar is a feed of all-day events
+type Calendar struct {
+	Name string
+	// Stamp is when the feed was generated, the DTSTAMP of its events
+	Stamp time.Time
+	// RefreshInterval is how often subscribers should fetch the feed again
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is an all-day event with reminders
+type Event struct {
+	// UID stays the same for the same deadline across fetches
+	UID         string
+	Date        time.Time
+	Summary     string
+	Description string
+	Category    string
+	// Alarms are reminders this many days before the date, 0 for the
+	// morning of the day itself
+	Alarms []int
+}
+
+// Encode writes the calendar in iCalendar format
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", prodID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escape(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		e.line("REFRESH-INTERVAL;VALUE=DURATION", duration(c.RefreshInterval))
+		e.line("X-PUBLISHED-TTL", duration(c.RefreshInterval))
+	}
+	stamp := c.Stamp.UTC().Format("20060102T150405Z")
+	for _, ev := range c.Events {
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", escape(ev.UID))
+		e.line("DTSTAMP", stamp)
+		e.line("DTSTART;VALUE=DATE", ev.Date.Format("20060102"))
+		e.line("DTEND;VALUE=DATE", ev.Date.AddDate(0, 0, 1).Format("20060102"))
+		e.line("SUMMARY", escape(ev.Summary))
+		if ev.Description != "" {
+			e.line("DESCRIPTION", escape(ev.Description))
+		}
+		if ev.Category != "" {
+			e.line("CATEGORIES", escape(ev.Category))
+		}
+		// Deadlines do not block time in the subscriber's calendar
+		e.line("TRANSP", "TRANSPARENT")
+		for _, days := range ev.Alarms {
+			e.line("BEGIN", "VALARM")
+			e.line("ACTION", "DISPLAY")
+			e.line("DESCRIPTION", escape(ev.Summary))
+			if days == 0 {
+				// 9:00 on the day, relative to the start at midnight
+				e.line("TRIGGER", "PT9H")
+			} else {
+				e.line("TRIGGER", fmt.Sprintf("-P%dD", days))
+			}
+			e.line("END", "VALARM")
+		}
+		e.line("END", "VEVENT")
+	}
+	e.line("END", "VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// encoder writes content lines, folded at 75 octets and ended by CRLF
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	s := name + ":" + value
+	// Continuation lines start with a space, which counts towards their 75
+	limit := 75
+	for len(s) > limit {
+		// Fold on a rune boundary
+		cut := limit
+		for !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s, limit = s[cut:], 74
+	}
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+// escape escapes a TEXT value
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// duration formats a duration of whole minutes as an iCalendar DURATION
+func duration(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes%(24*60) == 0 {
+		return fmt.Sprintf("P%dD", minutes/(24*60))
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("PT%dH", minutes/60)
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
+
+// NewToken returns a new feed token and the hash to store for it
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the stored hash of a feed token
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
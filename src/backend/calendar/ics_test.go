@@ -0,0 +1,95 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	c := &Calendar{
+		Name:            "BudSafe - Green Leaf",
+		Stamp:           time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC),
+		RefreshInterval: time.Hour,
+		Events: []Event{{
+			UID:         "license-1-expiration@budsafe",
+			Date:        time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC),
+			Summary:     "License C10-0000123 expires",
+			Description: "Retail license; renew before it lapses.\nSee the dashboard",
+			Category:    "License expiration",
+			Alarms:      []int{30, 0},
+		}},
+	}
+	var b strings.Builder
+	require.NoError(t, c.Encode(&b))
+
+	assert.Equal(t, strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//BudSafe//Compliance Calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:BudSafe - Green Leaf",
+		"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+		"X-PUBLISHED-TTL:PT1H",
+		"BEGIN:VEVENT",
+		"UID:license-1-expiration@budsafe",
+		"DTSTAMP:20261018T093000Z",
+		"DTSTART;VALUE=DATE:20270131",
+		"DTEND;VALUE=DATE:20270201",
+		"SUMMARY:License C10-0000123 expires",
+		`DESCRIPTION:Retail license\; renew before it lapses.\nSee the dashboard`,
+		"CATEGORIES:License expiration",
+		"TRANSP:TRANSPARENT",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:License C10-0000123 expires",
+		"TRIGGER:-P30D",
+		"END:VALARM",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"DESCRIPTION:License C10-0000123 expires",
+		"TRIGGER:PT9H",
+		"END:VALARM",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), b.String())
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	c := &Calendar{Events: []Event{{
+		UID:     "x",
+		Date:    time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		Summary: strings.Repeat("é", 100),
+	}}}
+	var b strings.Builder
+	require.NoError(t, c.Encode(&b))
+
+	var summary string
+	for i, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "line %d", i)
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary = line
+		} else if summary != "" && strings.HasPrefix(line, " ") {
+			summary += line[1:]
+		} else if summary != "" {
+			break
+		}
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 100), summary)
+}
+
+func TestNewToken(t *testing.T) {
+	token, hash, err := NewToken()
+	require.NoError(t, err)
+	assert.Len(t, token, 43)
+	assert.Equal(t, HashToken(token), hash)
+
+	other, _, err := NewToken()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
@@ -0,0 +1,372 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"budsafe/backend/notify"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Delivery statuses, matching the DeliveryStatus enum
+const (
+	StatusPending  = "PENDING"
+	StatusRetrying = "RETRYING"
+	StatusSent     = "SENT"
+	StatusFailed   = "FAILED"
+	StatusBounced  = "BOUNCED"
+)
+
+// Defaults of the Dispatcher settings
+const (
+	DefaultMaxAttempts = 8
+	DefaultMaxAge      = 24 * time.Hour
+	DefaultBatchSize   = 100
+	backoffBase        = time.Minute
+	backoffMax         = 6 * time.Hour
+)
+
+// Backoff is the wait after the given number of failed attempts: a minute
+// after the first, doubling up to six hours
+func Backoff(attempts int) time.Duration {
+	wait := backoffBase
+	for i := 1; i < attempts && wait < backoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, backoffMax)
+}
+
+// Dispatcher emails notifications. Each run queues a delivery for the
+// notifications created since the last one and attempts the deliveries that
+// are due. It follows the notify preferences of each recipient: it skips the
+// types they do not want by email, holds emails back during their quiet
+// hours and batches the notifications of digest users into one email per
+// digest. It relies on the scheduler to run on one replica at a time.
+type Dispatcher struct {
+	DB        *sqlx.DB
+	Transport Transport
+	Templates *Templates
+	// From is the sender address, e.g. "BudSafe <no-reply@example.com>"
+	From string
+	// AppURL is linked from the messages if set
+	AppURL string
+	// MaxAttempts is how often a delivery is tried before it fails
+	MaxAttempts int
+	// MaxAge is how old an unread notification may be and still be queued,
+	// so that turning email on does not send the whole history
+	MaxAge    time.Duration
+	BatchSize int
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// errUnrenderable fails a delivery at once: rendering again will not help
+var errUnrenderable = errors.New("cannot render message")
+
+// dueDelivery is a queued delivery with its notification and recipient
+type dueDelivery struct {
+	ID                string    `db:"id"`
+	Recipient         string    `db:"recipient"`
+	Attempts          int       `db:"attempts"`
+	Digest            bool      `db:"digest"`
+	UserID            string    `db:"user_id"`
+	Name              string    `db:"first_name"`
+	Title             string    `db:"title"`
+	Message           string    `db:"message"`
+	Type              string    `db:"type"`
+	RelatedEntityID   *string   `db:"related_entity_id"`
+	RelatedEntityType *string   `db:"related_entity_type"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+// Run queues and sends deliveries. It is a scheduler job.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	queued, err := d.Enqueue(ctx)
+	if err != nil {
+		return err
+	}
+	sent, failed, err := d.Send(ctx)
+	if err != nil {
+		return err
+	}
+	if queued > 0 || sent+failed > 0 {
+		log.Printf("email: queued %d, sent %d, failed %d delivery attempt(s)", queued, sent, failed)
+	}
+	return nil
+}
+
+// candidate is a notification that may need an email delivery
+type candidate struct {
+	ID     string `db:"id"`
+	UserID string `db:"user_id"`
+	Type   string `db:"type"`
+	IsRead bool   `db:"is_read"`
+	Email  string `db:"email"`
+}
+
+// Enqueue queues an email delivery for each recent notification without
+// one that its recipient wants emailed, and returns how many it queued.
+// Notifications already read in the app are not emailed. Digest deliveries
+// are due at the recipient's next digest.
+func (d *Dispatcher) Enqueue(ctx context.Context) (int64, error) {
+	maxAge := d.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultMaxAge
+	}
+	now := d.now()
+	var candidates []candidate
+	err := d.DB.SelectContext(ctx, &candidates, `
+		SELECT n.id, n.user_id, n.type, n.is_read, u.email
+		FROM notifications n
+		JOIN users u ON u.id = n.user_id
+		WHERE n.created_at >= $1
+		  AND NOT EXISTS (
+		    SELECT 1 FROM notification_deliveries d
+		    WHERE d.notification_id = n.id AND d.channel = 'EMAIL'
+		  )
+		ORDER BY n.created_at
+	`, now.Add(-maxAge))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load notifications to email: %w", err)
+	}
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	prefs, err := notify.Load(ctx, d.DB, userIDs(candidates, func(c candidate) string { return c.UserID })...)
+	if err != nil {
+		return 0, err
+	}
+
+	var queued int64
+	for _, c := range candidates {
+		p := prefs[c.UserID]
+		// A notification muted in the app is stored as read, but was not seen
+		if !p.Wants(c.Type, notify.Email) || (c.IsRead && p.Wants(c.Type, notify.InApp)) {
+			continue
+		}
+		result, err := d.DB.ExecContext(ctx, `
+			INSERT INTO notification_deliveries (notification_id, channel, recipient, digest, next_attempt_at)
+			VALUES ($1, 'EMAIL', $2, $3, $4)
+			ON CONFLICT (notification_id, channel) DO NOTHING
+		`, c.ID, c.Email, p.Digest != notify.Immediate, p.NextDigest(now))
+		if err != nil {
+			return queued, fmt.Errorf("failed to queue email delivery of notification %s: %w", c.ID, err)
+		}
+		n, _ := result.RowsAffected()
+		queued += n
+	}
+	return queued, nil
+}
+
+// Send attempts the deliveries that are due and returns how many were sent
+// and how many attempts failed. The due digest deliveries of a user go out
+// as one email and count as one.
+func (d *Dispatcher) Send(ctx context.Context) (sent, failed int, err error) {
+	batch := d.BatchSize
+	if batch == 0 {
+		batch = DefaultBatchSize
+	}
+	var due []dueDelivery
+	err = d.DB.SelectContext(ctx, &due, `
+		SELECT d.id, d.recipient, d.attempts, d.digest, n.user_id, u.first_name, n.title, n.message, n.type,
+		       n.related_entity_id, n.related_entity_type, n.created_at
+		FROM notification_deliveries d
+		JOIN notifications n ON n.id = d.notification_id
+		JOIN users u ON u.id = n.user_id
+		WHERE d.channel = 'EMAIL'
+		  AND d.status IN ('PENDING', 'RETRYING')
+		  AND d.next_attempt_at <= $1
+		ORDER BY d.next_attempt_at, n.created_at
+		LIMIT $2
+	`, d.now(), batch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load due email deliveries: %w", err)
+	}
+	if len(due) == 0 {
+		return 0, 0, nil
+	}
+	prefs, err := notify.Load(ctx, d.DB, userIDs(due, func(delivery dueDelivery) string { return delivery.UserID })...)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, group := range groupDigests(due) {
+		if ctx.Err() != nil {
+			return sent, failed, ctx.Err()
+		}
+		if until, quiet := prefs[group[0].UserID].QuietUntil(d.now()); quiet {
+			if err := d.postpone(ctx, group, until); err != nil {
+				return sent, failed, err
+			}
+			continue
+		}
+		sendErr := d.deliver(ctx, group, prefs[group[0].UserID])
+		if err := d.record(ctx, group, sendErr); err != nil {
+			return sent, failed, err
+		}
+		if sendErr != nil {
+			failed++
+		} else {
+			sent++
+		}
+	}
+	return sent, failed, nil
+}
+
+// groupDigests splits deliveries into the emails they are sent in: each
+// immediate delivery on its own, and the digest deliveries of a user
+// together
+func groupDigests(due []dueDelivery) [][]dueDelivery {
+	var groups [][]dueDelivery
+	digests := map[string]int{}
+	for _, delivery := range due {
+		if !delivery.Digest {
+			groups = append(groups, []dueDelivery{delivery})
+			continue
+		}
+		if i, ok := digests[delivery.UserID]; ok {
+			groups[i] = append(groups[i], delivery)
+			continue
+		}
+		digests[delivery.UserID] = len(groups)
+		groups = append(groups, []dueDelivery{delivery})
+	}
+	return groups
+}
+
+// deliver renders and sends the email of a group of deliveries
+func (d *Dispatcher) deliver(ctx context.Context, group []dueDelivery, prefs *notify.Preferences) error {
+	first := group[0]
+	var subject, html, text string
+	var err error
+	if first.Digest {
+		digest := DigestData{Name: first.Name, Period: "daily", CreatedAt: d.now(), Link: d.AppURL}
+		if prefs.Digest == notify.Weekly {
+			digest.Period = "weekly"
+		}
+		for _, delivery := range group {
+			digest.Items = append(digest.Items, d.data(delivery))
+		}
+		subject, html, text, err = d.Templates.RenderDigest(digest)
+	} else {
+		subject, html, text, err = d.Templates.Render(d.data(first))
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnrenderable, err)
+	}
+	return d.Transport.Send(ctx, &Message{
+		From:      d.From,
+		To:        first.Recipient,
+		Subject:   subject,
+		HTML:      html,
+		Text:      text,
+		MessageID: first.ID + "@budsafe",
+		Date:      d.now(),
+	})
+}
+
+// data is what the templates render for a delivery
+func (d *Dispatcher) data(delivery dueDelivery) Data {
+	return Data{
+		Name:              delivery.Name,
+		Title:             delivery.Title,
+		Message:           delivery.Message,
+		Type:              delivery.Type,
+		RelatedEntityType: deref(delivery.RelatedEntityType),
+		RelatedEntityID:   deref(delivery.RelatedEntityID),
+		CreatedAt:         delivery.CreatedAt,
+		Link:              d.AppURL,
+	}
+}
+
+// postpone holds a group of deliveries back until the end of quiet hours,
+// without counting an attempt
+func (d *Dispatcher) postpone(ctx context.Context, group []dueDelivery, until time.Time) error {
+	_, err := d.DB.ExecContext(ctx, `
+		UPDATE notification_deliveries SET next_attempt_at = $2, updated_at = NOW()
+		WHERE id = ANY($1)
+	`, pq.Array(deliveryIDs(group)), until)
+	if err != nil {
+		return fmt.Errorf("failed to postpone email delivery %s: %w", group[0].ID, err)
+	}
+	return nil
+}
+
+// record stores the outcome of an attempt on each delivery of a group:
+// sent, bounced on a permanent error, failed once out of attempts, or else
+// retried after a backoff
+func (d *Dispatcher) record(ctx context.Context, group []dueDelivery, sendErr error) error {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	attempts := 0
+	for _, delivery := range group {
+		attempts = max(attempts, delivery.Attempts+1)
+	}
+	status, next, lastError := StatusSent, d.now(), (*string)(nil)
+	if sendErr != nil {
+		msg := sendErr.Error()
+		lastError = &msg
+		switch {
+		case IsPermanent(sendErr):
+			status = StatusBounced
+		case errors.Is(sendErr, errUnrenderable), attempts >= maxAttempts:
+			status = StatusFailed
+		default:
+			status, next = StatusRetrying, d.now().Add(Backoff(attempts))
+		}
+		log.Printf("email: delivery %s to %s: attempt %d: %s: %v", group[0].ID, group[0].Recipient, attempts, status, sendErr)
+	}
+
+	_, err := d.DB.ExecContext(ctx, `
+		UPDATE notification_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5,
+		    sent_at = CASE WHEN $2 = 'SENT' THEN $4 END,
+		    updated_at = NOW()
+		WHERE id = ANY($1)
+	`, pq.Array(deliveryIDs(group)), status, attempts, next, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to record email delivery %s: %w", group[0].ID, err)
+	}
+	return nil
+}
+
+func deliveryIDs(group []dueDelivery) []string {
+	ids := make([]string, len(group))
+	for i, delivery := range group {
+		ids[i] = delivery.ID
+	}
+	return ids
+}
+
+// userIDs returns the distinct user IDs of rows
+func userIDs[T any](rows []T, userID func(T) string) []string {
+	var ids []string
+	for _, row := range rows {
+		if id := userID(row); !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (d *Dispatcher) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,247 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplates(t *testing.T) {
+	templates, err := LoadTemplates()
+	require.NoError(t, err)
+
+	for _, typ := range NotificationTypes {
+		subject, html, text, err := templates.Render(Data{
+			Name:      "Jane",
+			Title:     "License C10-1 & friends",
+			Message:   "<b>Renew</b> soon.",
+			Type:      typ,
+			CreatedAt: time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			Link:      "https://app.example.com",
+		})
+		require.NoError(t, err, typ)
+		assert.Contains(t, subject, "License C10-1 & friends", typ)
+		assert.Contains(t, html, "Hi Jane,", typ)
+		assert.Contains(t, html, "&lt;b&gt;Renew&lt;/b&gt; soon.", typ)
+		assert.Contains(t, html, `href="https://app.example.com"`, typ)
+		assert.Contains(t, text, "<b>Renew</b> soon.", typ)
+	}
+
+	_, _, _, err = templates.Render(Data{Type: "UNKNOWN"})
+	assert.Error(t, err)
+}
+
+func TestRenderDigest(t *testing.T) {
+	templates, err := LoadTemplates()
+	require.NoError(t, err)
+
+	created := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	subject, html, text, err := templates.RenderDigest(DigestData{
+		Name:   "Jane",
+		Period: "daily",
+		Items: []Data{
+			{Title: "License C10-1 expires", Message: "In 30 days.", Type: "LICENSE_EXPIRING", CreatedAt: created},
+			{Title: "License C10-2 expires", Message: "In 7 days.", Type: "LICENSE_EXPIRING", CreatedAt: created},
+		},
+		CreatedAt: created,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Your daily BudSafe digest: 2 notifications", subject)
+	assert.Contains(t, html, "License C10-1 expires")
+	assert.Contains(t, html, "In 7 days.")
+	assert.Contains(t, text, "License C10-2 expires\nIn 7 days.")
+
+	_, _, _, err = templates.RenderDigest(DigestData{Period: "daily"})
+	assert.Error(t, err)
+}
+
+func TestGroupDigests(t *testing.T) {
+	groups := groupDigests([]dueDelivery{
+		{ID: "1", UserID: "a", Digest: true},
+		{ID: "2", UserID: "b"},
+		{ID: "3", UserID: "b", Digest: true},
+		{ID: "4", UserID: "a", Digest: true},
+		{ID: "5", UserID: "b"},
+	})
+	var ids [][]string
+	for _, group := range groups {
+		ids = append(ids, deliveryIDs(group))
+	}
+	assert.Equal(t, [][]string{{"1", "4"}, {"2"}, {"3"}, {"5"}}, ids)
+}
+
+func TestMessageBytes(t *testing.T) {
+	msg := &Message{
+		From:      "BudSafe <no-reply@example.com>",
+		To:        "jane@example.com",
+		Subject:   "Lizenz läuft ab",
+		HTML:      "<p>Hallo</p>",
+		Text:      "Hallo",
+		MessageID: "1@budsafe",
+		Date:      time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+	}
+	raw, err := msg.Bytes()
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Lizenz läuft ab", subject)
+	assert.Equal(t, "<1@budsafe>", parsed.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+	assert.Equal(t, []string{"Hallo", "<p>Hallo</p>"}, bodies)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, Backoff(1))
+	assert.Equal(t, 2*time.Minute, Backoff(2))
+	assert.Equal(t, 64*time.Minute, Backoff(7))
+	assert.Equal(t, 6*time.Hour, Backoff(20))
+}
+
+// smtpCatcher is a minimal SMTP server that records the messages it
+// accepts. It answers AUTH, MAIL FROM, RCPT TO, the end of DATA and QUIT
+// with replies, keyed by command, or else positively.
+type smtpCatcher struct {
+	addr     string
+	replies  map[string]string
+	messages chan string
+}
+
+func newSMTPCatcher(t *testing.T, replies map[string]string) *smtpCatcher {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	c := &smtpCatcher{addr: ln.Addr().String(), replies: replies, messages: make(chan string, 1)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go c.serve(conn)
+		}
+	}()
+	return c
+}
+
+func (c *smtpCatcher) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+	replyTo := func(cmd, ok string) {
+		if s, set := c.replies[cmd]; set {
+			ok = s
+		}
+		reply(ok)
+	}
+	reply("220 catcher ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-catcher")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH"):
+			replyTo("AUTH", "235 authenticated")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			replyTo("MAIL", "250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			replyTo("RCPT", "250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			if _, rejected := c.replies["DATA"]; !rejected {
+				c.messages <- data.String()
+			}
+			replyTo("DATA", "250 queued")
+		case cmd == "QUIT":
+			replyTo("QUIT", "221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	msg := &Message{From: "BudSafe <no-reply@example.com>", To: "jane@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>", Date: time.Now()}
+
+	catcher := newSMTPCatcher(t, nil)
+	require.NoError(t, (&SMTP{Addr: catcher.addr, Username: "budsafe", Password: "secret"}).Send(context.Background(), msg))
+	assert.Contains(t, <-catcher.messages, "Subject: Hi\r\n")
+
+	rejecting := newSMTPCatcher(t, map[string]string{"RCPT": "550 no such user"})
+	err := (&SMTP{Addr: rejecting.addr}).Send(context.Background(), msg)
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err))
+
+	busy := newSMTPCatcher(t, map[string]string{"RCPT": "451 try again later"})
+	err = (&SMTP{Addr: busy.addr}).Send(context.Background(), msg)
+	require.Error(t, err)
+	assert.False(t, IsPermanent(err))
+}
+
+func TestSMTP_RelayRejectionsAreRetried(t *testing.T) {
+	msg := &Message{From: "BudSafe <no-reply@example.com>", To: "jane@example.com", Subject: "Hi", Text: "Hi", HTML: "<p>Hi</p>", Date: time.Now()}
+
+	// A 5xx reply to anything but RCPT TO is about the relay, not the
+	// recipient, so the delivery must not bounce
+	for stage, rejection := range map[string]string{
+		"AUTH": "535 authentication credentials invalid",
+		"MAIL": "550 sender not allowed to relay",
+		"DATA": "554 transaction failed",
+	} {
+		catcher := newSMTPCatcher(t, map[string]string{stage: rejection})
+		err := (&SMTP{Addr: catcher.addr, Username: "budsafe", Password: "wrong"}).Send(context.Background(), msg)
+		require.Error(t, err, stage)
+		assert.False(t, IsPermanent(err), stage)
+		assert.Contains(t, err.Error(), rejection[4:], stage)
+	}
+
+	// Once the message is queued, a failed QUIT does not fail the delivery
+	catcher := newSMTPCatcher(t, map[string]string{"QUIT": "554 goodbye anyway"})
+	require.NoError(t, (&SMTP{Addr: catcher.addr}).Send(context.Background(), msg))
+	assert.Contains(t, <-catcher.messages, "Subject: Hi\r\n")
+}
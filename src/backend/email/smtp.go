@@ -0,0 +1,138 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// Transport sends rendered messages
+type Transport interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// PermanentError is a failure that retrying will not fix: the server
+// rejected the recipient. The delivery is recorded as bounced.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// IsPermanent reports whether err is a PermanentError
+func IsPermanent(err error) bool {
+	var permanent *PermanentError
+	return errors.As(err, &permanent)
+}
+
+// SMTP sends messages through an SMTP server. It upgrades the connection
+// with STARTTLS when the server offers it, so a local catcher without TLS
+// works as well as a relay.
+type SMTP struct {
+	// Addr is the server's host:port
+	Addr string
+	// Username and Password authenticate with PLAIN if set, which net/smtp
+	// only allows over TLS or to localhost
+	Username string
+	Password string
+	// ImplicitTLS connects with TLS from the start (port 465) instead of
+	// STARTTLS
+	ImplicitTLS bool
+	// Timeout bounds the whole exchange; 30 seconds if zero
+	Timeout time.Duration
+}
+
+// Send delivers msg to msg.To. Only a 5xx reply to RCPT TO, the server
+// refusing the recipient, is returned as a PermanentError. Every other
+// failure is temporary, even a 5xx reply to AUTH, MAIL FROM or DATA: those
+// reject the relay's credentials or policy rather than the message, and
+// bouncing would lose every email queued until the configuration is fixed.
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return fmt.Errorf("smtp: invalid address %q: %w", s.Addr, err)
+	}
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var conn net.Conn
+	if s.ImplicitTLS {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: host}}
+		conn, err = dialer.DialContext(ctx, "tcp", s.Addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", s.Addr)
+	}
+	if err != nil {
+		return fmt.Errorf("smtp: connect: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp: %w", err)
+	}
+	defer c.Close()
+
+	if !s.ImplicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+				return fmt.Errorf("smtp: starttls: %w", err)
+			}
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, host)); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+	if err := c.Mail(address(msg.From)); err != nil {
+		return fmt.Errorf("smtp: MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(address(msg.To)); err != nil {
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return &PermanentError{Err: fmt.Errorf("smtp: RCPT TO: %w", err)}
+		}
+		return fmt.Errorf("smtp: RCPT TO: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: DATA: %w", err)
+	}
+	// The server has taken the message; failing now would send it twice
+	c.Quit()
+	return nil
+}
+
+// address returns the bare address of "Name <user@example.com>"
+func address(s string) string {
+	if a, err := mail.ParseAddress(s); err == nil {
+		return a.Address
+	}
+	return s
+}
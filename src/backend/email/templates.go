@@ -0,0 +1,141 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// NotificationTypes are the notification types with a template, matching
+// the NotificationType enum
+var NotificationTypes = []string{
+	"LICENSE_EXPIRING",
+	"RENEWAL_DUE",
+	"COMPLIANCE_ISSUE",
+	"DOCUMENT_REQUIRED",
+	"REGULATION_UPDATE",
+}
+
+// Data is what the templates render
+type Data struct {
+	// Name is the recipient's first name
+	Name              string
+	Title             string
+	Message           string
+	Type              string
+	RelatedEntityType string
+	RelatedEntityID   string
+	CreatedAt         time.Time
+	// Link opens the app; templates leave out the button when it is empty
+	Link string
+}
+
+// DigestData is what the digest template renders
+type DigestData struct {
+	Name string
+	// Period is "daily" or "weekly"
+	Period string
+	Items  []Data
+	// CreatedAt is when the digest was put together
+	CreatedAt time.Time
+	Link      string
+}
+
+// Templates render notifications of each type into emails. Each type's
+// template defines a "subject" and the "content" of the shared layout, and
+// so does the digest template that lists several notifications.
+type Templates struct {
+	byType map[string]*template.Template
+	digest *template.Template
+}
+
+// LoadTemplates parses the embedded templates
+func LoadTemplates() (*Templates, error) {
+	t := &Templates{byType: map[string]*template.Template{}}
+	for _, typ := range NotificationTypes {
+		tmpl, err := template.ParseFS(templateFS, "templates/layout.html", "templates/"+typ+".html")
+		if err != nil {
+			return nil, fmt.Errorf("email: parse %s template: %w", typ, err)
+		}
+		t.byType[typ] = tmpl
+	}
+	digest, err := template.ParseFS(templateFS, "templates/layout.html", "templates/digest.html")
+	if err != nil {
+		return nil, fmt.Errorf("email: parse digest template: %w", err)
+	}
+	t.digest = digest
+	return t, nil
+}
+
+// Render renders the subject and bodies of a message for data
+func (t *Templates) Render(data Data) (subject, htmlBody, textBody string, err error) {
+	tmpl, ok := t.byType[data.Type]
+	if !ok {
+		return "", "", "", fmt.Errorf("email: no template for notification type %q", data.Type)
+	}
+	subject, htmlBody, err = render(tmpl, data)
+	if err != nil {
+		return "", "", "", fmt.Errorf("email: render %s: %w", data.Type, err)
+	}
+	return subject, htmlBody, plainText(data), nil
+}
+
+// RenderDigest renders a digest of several notifications
+func (t *Templates) RenderDigest(data DigestData) (subject, htmlBody, textBody string, err error) {
+	if len(data.Items) == 0 {
+		return "", "", "", fmt.Errorf("email: digest without notifications")
+	}
+	subject, htmlBody, err = render(t.digest, data)
+	if err != nil {
+		return "", "", "", fmt.Errorf("email: render digest: %w", err)
+	}
+
+	var b strings.Builder
+	if data.Name != "" {
+		fmt.Fprintf(&b, "Hi %s,\n\n", data.Name)
+	}
+	b.WriteString("Here is what happened since your last digest.\n")
+	for _, item := range data.Items {
+		fmt.Fprintf(&b, "\n%s\n%s\n", item.Title, item.Message)
+	}
+	if data.Link != "" {
+		fmt.Fprintf(&b, "\nOpen BudSafe: %s\n", data.Link)
+	}
+	return subject, htmlBody, b.String(), nil
+}
+
+// render executes the subject and layout of tmpl
+func render(tmpl *template.Template, data any) (subject, htmlBody string, err error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	// The subject is a header, not HTML
+	subject = strings.Join(strings.Fields(html.UnescapeString(buf.String())), " ")
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return "", "", err
+	}
+	return subject, buf.String(), nil
+}
+
+// plainText is the text alternative of a rendered message
+func plainText(data Data) string {
+	var b strings.Builder
+	if data.Name != "" {
+		fmt.Fprintf(&b, "Hi %s,\n\n", data.Name)
+	}
+	fmt.Fprintf(&b, "%s\n\n%s\n", data.Title, data.Message)
+	if data.Link != "" {
+		fmt.Fprintf(&b, "\nOpen BudSafe: %s\n", data.Link)
+	}
+	return b.String()
+}
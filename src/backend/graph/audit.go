@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"budsafe/backend/audit"
+	"budsafe/backend/auth"
+	"budsafe/backend/graph/model"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jmoiron/sqlx"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// auditEntityTypes are the entity types the audit triggers record (see
+// migrations/0017_audit_events.up.sql)
+var auditEntityTypes = []string{
+	"Business", "BusinessMember", "CalendarFeed", "ComplianceCheck", "ComplianceSchedule", "Document",
+	"Jurisdiction", "License", "Location", "NotificationPreferences", "Regulation", "Renewal",
+	"RenewalRequirement", "RenewalTemplate", "RenewalTemplateItem", "ReportJob", "User", "WebhookSubscription",
+}
+
+// tagChanges sets the transaction-local settings the audit triggers record
+// with each change: the request ID and client IP, the signed-in account's
+// email and the mutation being resolved
+func tagChanges(ctx context.Context, tx *sqlx.Tx) error {
+	var requestID, ip, email, mutation string
+	if req := audit.FromContext(ctx); req != nil {
+		requestID, ip = req.ID, req.IP
+	}
+	if authUser := auth.ForContext(ctx); authUser != nil {
+		email = authUser.Email
+	}
+	mutation = mutationName(ctx)
+	if requestID == "" && email == "" && mutation == "" {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		SELECT set_config('app.request_id', $1, true), set_config('app.client_ip', $2, true),
+		       set_config('app.actor_email', $3, true), set_config('app.mutation', $4, true)
+	`, requestID, ip, email, mutation)
+	return dbError(err, "tag transaction")
+}
+
+// mutationName is the root field of the mutation being resolved, if any
+func mutationName(ctx context.Context) string {
+	if !graphql.HasOperationContext(ctx) || graphql.GetOperationContext(ctx).Operation == nil ||
+		graphql.GetOperationContext(ctx).Operation.Operation != ast.Mutation {
+		return ""
+	}
+	if field := graphql.GetRootFieldContext(ctx); field != nil {
+		return field.Field.Name
+	}
+	return ""
+}
+
+// auditEntityBusinesses are the businesses the events belong to, each once;
+// global reports whether any event belongs to none
+func auditEntityBusinesses(events []*model.AuditEvent) (businessIDs []string, global bool) {
+	for _, event := range events {
+		switch {
+		case event.BusinessID == nil:
+			global = true
+		case !slices.Contains(businessIDs, *event.BusinessID):
+			businessIDs = append(businessIDs, *event.BusinessID)
+		}
+	}
+	return businessIDs, global
+}
+
+// auditValues decodes the stored before or after object of an event
+func auditValues(raw *string) (map[string]any, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	var values map[string]any
+	if err := json.Unmarshal([]byte(*raw), &values); err != nil {
+		return nil, fmt.Errorf("invalid audit event values: %w", err)
+	}
+	return values, nil
+}
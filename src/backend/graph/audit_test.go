@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestAuditEntityBusinesses(t *testing.T) {
+	a, b := "business-a", "business-b"
+	businessIDs, global := auditEntityBusinesses([]*model.AuditEvent{{BusinessID: &a}, {BusinessID: &b}, {BusinessID: &a}})
+	assert.Equal(t, []string{a, b}, businessIDs)
+	assert.False(t, global)
+
+	businessIDs, global = auditEntityBusinesses([]*model.AuditEvent{{BusinessID: &a}, {}})
+	assert.Equal(t, []string{a}, businessIDs)
+	assert.True(t, global)
+
+	businessIDs, global = auditEntityBusinesses(nil)
+	assert.Empty(t, businessIDs)
+	assert.False(t, global)
+}
+
+func TestAuditValues(t *testing.T) {
+	values, err := auditValues(nil)
+	require.NoError(t, err)
+	assert.Nil(t, values)
+
+	raw := `{"notes": null, "status": "SUSPENDED"}`
+	values, err = auditValues(&raw)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"notes": nil, "status": "SUSPENDED"}, values)
+
+	raw = "not json"
+	_, err = auditValues(&raw)
+	assert.Error(t, err)
+}
+
+func TestMutationName(t *testing.T) {
+	assert.Empty(t, mutationName(context.Background()))
+
+	operation := func(op ast.Operation) context.Context {
+		ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+			Operation: &ast.OperationDefinition{Operation: op},
+		})
+		return graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{
+			Field: graphql.CollectedField{Field: &ast.Field{Name: "updateLicense"}},
+		})
+	}
+	assert.Equal(t, "updateLicense", mutationName(operation(ast.Mutation)))
+	assert.Empty(t, mutationName(operation(ast.Query)))
+}
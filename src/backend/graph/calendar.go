@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"budsafe/backend/calendar"
+	"budsafe/backend/graph/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// calendarRefresh is how often subscribed calendar apps should refetch
+	// a feed
+	calendarRefresh = time.Hour
+	// calendarHistory is how long past deadlines stay in a feed, so missed
+	// ones remain visible
+	calendarHistory = 90
+	// calendarHorizon bounds the schedule occurrences projected into a feed
+	calendarHorizon = 365 * 24 * time.Hour
+	// calendarOccurrences caps the projected schedule occurrences
+	calendarOccurrences = 500
+)
+
+// Reminders in days before each kind of deadline
+var (
+	expirationAlarms = []int{90, 30, 7}
+	renewalAlarms    = []int{7, 1}
+	checkAlarms      = []int{1, 0}
+)
+
+// errFeedNotFound covers unknown and revoked tokens and feeds whose user
+// lost access to the business, which are indistinguishable to subscribers
+var errFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarHandler serves calendar feeds at /{token}.ics; mount it with
+// http.StripPrefix. The token is the only credential.
+func (r *Resolver) CalendarHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		token, ok := strings.CutSuffix(strings.TrimPrefix(req.URL.Path, "/"), ".ics")
+		if !ok || token == "" || strings.Contains(token, "/") {
+			http.NotFound(w, req)
+			return
+		}
+
+		cal, err := r.calendarFeed(req.Context(), token, time.Now())
+		if errors.Is(err, errFeedNotFound) {
+			http.NotFound(w, req)
+			return
+		}
+		if err != nil {
+			log.Printf("calendar: failed to build feed: %v", err)
+			http.Error(w, "failed to build calendar", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Cache-Control", "private, max-age=300")
+		if req.Method == http.MethodHead {
+			return
+		}
+		if err := cal.Encode(w); err != nil {
+			log.Printf("calendar: failed to write feed: %v", err)
+		}
+	})
+}
+
+// calendarFeed builds the calendar of the feed with the token. The deadlines
+// are read as the feed's user, so the feed shows what they could see in the
+// app.
+func (r *Resolver) calendarFeed(ctx context.Context, token string, now time.Time) (*calendar.Calendar, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var feed struct {
+		model.CalendarFeed
+		Role model.UserRole `db:"role"`
+	}
+	err = tx.GetContext(ctx, &feed, `
+		SELECT f.id, f.user_id, f.business_id, f.name, u.role
+		FROM calendar_feeds f
+		JOIN users u ON u.id = f.user_id
+		WHERE f.token_hash = $1
+		  AND f.revoked_at IS NULL
+		  AND (u.role = 'ADMIN' OR EXISTS (
+		    SELECT 1 FROM business_members m
+		    WHERE m.business_id = f.business_id AND m.user_id = f.user_id
+		  ))
+	`, calendar.HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errFeedNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE calendar_feeds SET last_used_at = NOW() WHERE id = $1", feed.ID); err != nil {
+		return nil, err
+	}
+	if err := scopeTo(ctx, tx, feed.UserID, string(feed.Role)); err != nil {
+		return nil, err
+	}
+
+	events, err := calendarEvents(ctx, tx, feed.BusinessID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &calendar.Calendar{
+		Name:            feed.Name,
+		Stamp:           now,
+		RefreshInterval: calendarRefresh,
+		Events:          events,
+	}, nil
+}
+
+// calendarEvents lists the business's deadlines from calendarHistory days
+// ago: license expirations, renewal requirement deadlines, open compliance
+// checks and the occurrences of compliance schedules yet to be created
+func calendarEvents(ctx context.Context, tx *sqlx.Tx, businessID string, now time.Time) ([]calendar.Event, error) {
+	var events []calendar.Event
+	since := now.AddDate(0, 0, -calendarHistory)
+
+	// Licenses are loaded regardless of date to name those of schedules.
+	// Expired licenses stay in the feed with their missed expiration.
+	var licenses []*model.License
+	err := tx.SelectContext(ctx, &licenses, `
+		SELECT `+licenseColumns+`
+		FROM licenses
+		WHERE business_id = $1
+		  AND status <> 'REVOKED'
+		ORDER BY expiration_date
+	`, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load licenses: %w", err)
+	}
+	for _, l := range licenses {
+		day := l.ExpirationDate[:min(len(l.ExpirationDate), len(time.DateOnly))]
+		date, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return nil, fmt.Errorf("license %s has an invalid expiration date: %w", l.ID, err)
+		}
+		if date.Before(since) {
+			continue
+		}
+		events = append(events, calendar.Event{
+			UID:         fmt.Sprintf("license-%s-expiration@budsafe", l.ID),
+			Date:        date,
+			Summary:     fmt.Sprintf("License %s expires", l.LicenseNumber),
+			Description: fmt.Sprintf("The %s license %s expires on %s.", l.LicenseType, l.LicenseNumber, day),
+			Category:    "License expiration",
+			Alarms:      expirationAlarms,
+		})
+	}
+
+	var requirements []struct {
+		ID            string `db:"id"`
+		Description   string `db:"description"`
+		DueDate       string `db:"due_date"`
+		LicenseNumber string `db:"license_number"`
+	}
+	err = tx.SelectContext(ctx, &requirements, `
+		SELECT rr.id, rr.description, rr.due_date::text, l.license_number
+		FROM renewal_requirements rr
+		JOIN licenses l ON l.id = rr.license_id
+		WHERE l.business_id = $1
+		  AND rr.completed_at IS NULL
+		  AND rr.due_date >= $2::date
+		ORDER BY rr.due_date
+	`, businessID, since.Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load renewal requirements: %w", err)
+	}
+	for _, rr := range requirements {
+		date, err := time.Parse(time.DateOnly, rr.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, calendar.Event{
+			UID:         fmt.Sprintf("renewal-requirement-%s@budsafe", rr.ID),
+			Date:        date,
+			Summary:     fmt.Sprintf("Renewal due: %s (license %s)", rr.Description, rr.LicenseNumber),
+			Description: fmt.Sprintf("Renewal requirement of license %s: %s", rr.LicenseNumber, rr.Description),
+			Category:    "Renewal deadline",
+			Alarms:      renewalAlarms,
+		})
+	}
+
+	var checks []struct {
+		ID            string    `db:"id"`
+		Title         string    `db:"check_type"`
+		Notes         *string   `db:"notes"`
+		DueAt         time.Time `db:"next_check_date"`
+		LicenseNumber string    `db:"license_number"`
+	}
+	err = tx.SelectContext(ctx, &checks, `
+		SELECT c.id, c.check_type, c.notes, c.next_check_date, l.license_number
+		FROM compliance_checks c
+		JOIN licenses l ON l.id = c.license_id
+		WHERE l.business_id = $1
+		  AND c.checked_at IS NULL
+		  AND c.next_check_date >= $2
+		ORDER BY c.next_check_date
+	`, businessID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load compliance checks: %w", err)
+	}
+	for _, c := range checks {
+		events = append(events, calendar.Event{
+			UID:         fmt.Sprintf("compliance-check-%s@budsafe", c.ID),
+			Date:        c.DueAt.UTC(),
+			Summary:     fmt.Sprintf("Compliance check due: %s (license %s)", c.Title, c.LicenseNumber),
+			Description: deref(c.Notes),
+			Category:    "Compliance check",
+			Alarms:      checkAlarms,
+		})
+	}
+
+	// Open scheduled checks are among the checks above
+	upcoming, err := upcomingComplianceChecks(ctx, tx, businessID, now, now.Add(calendarHorizon), calendarOccurrences)
+	if err != nil {
+		return nil, fmt.Errorf("failed to project compliance schedules: %w", err)
+	}
+	licenseNumbers := map[string]string{}
+	for _, l := range licenses {
+		licenseNumbers[l.ID] = l.LicenseNumber
+	}
+	for _, u := range upcoming {
+		if u.ComplianceCheck != nil {
+			continue
+		}
+		due, err := time.Parse(time.RFC3339, u.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, calendar.Event{
+			UID:         fmt.Sprintf("compliance-schedule-%s-%s@budsafe", u.Schedule.ID, due.UTC().Format("20060102")),
+			Date:        due.UTC(),
+			Summary:     fmt.Sprintf("Compliance check due: %s (license %s)", u.Schedule.Title, licenseNumbers[u.Schedule.LicenseID]),
+			Description: deref(u.Schedule.Notes),
+			Category:    "Compliance check",
+			Alarms:      checkAlarms,
+		})
+	}
+	return events, nil
+}
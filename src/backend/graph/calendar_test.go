@@ -0,0 +1,27 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarHandler_RejectsBadRequests(t *testing.T) {
+	// None of these reach the database
+	h := (&Resolver{}).CalendarHandler()
+	for _, tc := range []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodPost, "/token.ics", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/token", http.StatusNotFound},
+		{http.MethodGet, "/.ics", http.StatusNotFound},
+		{http.MethodGet, "/a/b.ics", http.StatusNotFound},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		assert.Equal(t, tc.status, rec.Code, "%s %s", tc.method, tc.path)
+	}
+}
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Sort orders of the connection fields. Nullable columns are coalesced so
+// that keyset comparisons never see NULL.
+
+var userOrderKeys = map[model.UserOrderField]orderKey[model.User]{
+	model.UserOrderFieldCreatedAt: {expr: "created_at", cast: "timestamptz", value: func(u *model.User) string { return u.CreatedAt }},
+	model.UserOrderFieldEmail:     {expr: "email", cast: "text", value: func(u *model.User) string { return u.Email }},
+	model.UserOrderFieldLastName:  {expr: "COALESCE(last_name, '')", cast: "text", value: func(u *model.User) string { return deref(u.LastName) }},
+}
+
+var businessOrderKeys = map[model.BusinessOrderField]orderKey[model.Business]{
+	model.BusinessOrderFieldCreatedAt: {expr: "created_at", cast: "timestamptz", value: func(b *model.Business) string { return b.CreatedAt }},
+	model.BusinessOrderFieldName:      {expr: "name", cast: "text", value: func(b *model.Business) string { return b.Name }},
+}
+
+var licenseOrderKeys = map[model.LicenseOrderField]orderKey[model.License]{
+	model.LicenseOrderFieldCreatedAt:      {expr: "created_at", cast: "timestamptz", value: func(l *model.License) string { return l.CreatedAt }},
+	model.LicenseOrderFieldExpirationDate: {expr: "expiration_date", cast: "date", value: func(l *model.License) string { return l.ExpirationDate }},
+	model.LicenseOrderFieldLicenseNumber:  {expr: "license_number", cast: "text", value: func(l *model.License) string { return l.LicenseNumber }},
+	model.LicenseOrderFieldStatus:         {expr: "status", cast: "text", value: func(l *model.License) string { return string(l.Status) }},
+}
+
+var jurisdictionOrderKeys = map[model.JurisdictionOrderField]orderKey[model.Jurisdiction]{
+	model.JurisdictionOrderFieldCreatedAt: {expr: "created_at", cast: "timestamptz", value: func(j *model.Jurisdiction) string { return j.CreatedAt }},
+	model.JurisdictionOrderFieldName:      {expr: "name", cast: "text", value: func(j *model.Jurisdiction) string { return j.Name }},
+}
+
+var complianceCheckOrderKeys = map[model.ComplianceCheckOrderField]orderKey[model.ComplianceCheck]{
+	model.ComplianceCheckOrderFieldCreatedAt: {expr: "created_at", cast: "timestamptz", value: func(c *model.ComplianceCheck) string { return c.CreatedAt }},
+	model.ComplianceCheckOrderFieldDueDate:   {expr: "next_check_date", cast: "timestamptz", value: func(c *model.ComplianceCheck) string { return c.DueDate }},
+	model.ComplianceCheckOrderFieldStatus:    {expr: "status", cast: "text", value: func(c *model.ComplianceCheck) string { return string(c.Status) }},
+}
+
+var notificationOrderKey = orderKey[model.Notification]{
+	expr: "created_at", cast: "timestamptz", value: func(n *model.Notification) string { return n.CreatedAt },
+}
+
+var webhookDeliveryOrderKey = orderKey[model.WebhookDelivery]{
+	expr: "created_at", cast: "timestamptz", value: func(d *model.WebhookDelivery) string { return d.CreatedAt },
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func scanJurisdictionRow(rows *sqlx.Rows) (*model.Jurisdiction, error) {
+	return scanJurisdiction(rows)
+}
@@ -1,11 +1,68 @@
 package graph
 
 import (
+	"budsafe/backend/auth"
 	"budsafe/backend/graph/model"
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Column lists used with sqlx Get/Select and RETURNING clauses.
+// Timestamps are cast to text so they scan into the string fields on the models.
+const (
+	userColumns = `id, email, first_name, last_name, role, firebase_uid,
+		created_at::text, updated_at::text`
+	businessColumns = `id, name, type, description, owner_id,
+		created_at::text, updated_at::text`
+	locationColumns = `id, business_id, address, city, state, zip_code, is_primary,
+		created_at::text, updated_at::text`
+	licenseColumns = `id, business_id, jurisdiction_id, location_id,
+		license_number, type, status, issued_date::text,
+		expiration_date::text, renewal_date::text, fee_amount,
+		notes, created_at::text, updated_at::text`
+	complianceCheckColumns = `id, license_id, check_type, status, checked_at::text,
+		next_check_date::text, notes, checked_by_id, regulation_id, rule_clause,
+		schedule_id, created_at::text, updated_at::text`
+	// starts_at is selected as RFC 3339 so that the recurrence can be rebuilt from it
+	complianceScheduleColumns = `id, license_id, title, notes, rrule,
+		to_char(starts_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"') AS starts_at, lead_days,
+		assigned_to_id, active, created_at::text, updated_at::text`
+	renewalRequirementColumns = `id, license_id, description, due_date::text,
+		completed_at IS NOT NULL AS is_completed, renewal_id, document_category,
+		created_at::text, updated_at::text`
+	renewalColumns = `id, license_id, template_id, status, previous_expiration_date::text,
+		new_license_number, new_issued_date::text, new_expiration_date::text,
+		permit_recorded_at::text, completed_at::text, created_at::text, updated_at::text`
+	renewalTemplateColumns = `id, jurisdiction_id, license_type, name, created_at::text, updated_at::text`
+	documentColumns        = `id, name, description, file_url, file_type, category, storage_key,
+		file_size, checksum_sha256, series_id, version, valid_from::text, valid_until::text,
+		superseded_by_id, uploaded_by_id, business_id, license_id, renewal_requirement_id,
+		created_at::text, updated_at::text`
+	jurisdictionColumns = `id, name, type, country, regulatory_body, regulatory_website,
+		license_types, created_at::text, updated_at::text, license_number_formats`
+	notificationDeliveryColumns = `id, notification_id, channel, recipient, status, attempts, digest,
+		CASE WHEN status IN ('PENDING', 'RETRYING') THEN next_attempt_at::text END AS next_attempt_at,
+		last_error, sent_at::text, created_at::text, updated_at::text`
+	calendarFeedColumns = `id, user_id, business_id, name, last_used_at::text, revoked_at::text,
+		created_at::text, updated_at::text`
+	webhookSubscriptionColumns = `id, business_id, url, event_types, description, active, created_by_id,
+		created_at::text, updated_at::text`
+	webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload::text, status, attempts,
+		CASE WHEN status IN ('PENDING', 'RETRYING') THEN next_attempt_at::text END AS next_attempt_at,
+		response_status, last_error, delivered_at::text, created_at::text, updated_at::text`
+	notificationColumns = `id, user_id, title, message, type, is_read,
+		related_entity_id, related_entity_type, created_at::text, updated_at::text`
+	reportJobColumns = `id, business_id, location_id, format, status, error, document_id, requested_by_id,
+		created_at::text, completed_at::text`
+	auditEventColumns = `id, occurred_at::text, actor_id, actor_email, entity_type, entity_id, business_id,
+		operation, mutation, before::text, after::text, request_id, ip, prev_hash, hash`
 )
 
 // Helper function to scan a user row from database
@@ -13,7 +70,7 @@ func scanUser(rows *sql.Rows) (*model.User, error) {
 	var user model.User
 	var createdAt, updatedAt time.Time
 	var firstName, lastName sql.NullString
-	
+
 	err := rows.Scan(
 		&user.ID,
 		&user.Email,
@@ -26,20 +83,20 @@ func scanUser(rows *sql.Rows) (*model.User, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if firstName.Valid {
 		user.FirstName = &firstName.String
 	}
 	if lastName.Valid {
 		user.LastName = &lastName.String
 	}
-	
+
 	user.CreatedAt = createdAt.Format(time.RFC3339)
 	if !updatedAt.IsZero() {
 		updatedAtStr := updatedAt.Format(time.RFC3339)
 		user.UpdatedAt = &updatedAtStr
 	}
-	
+
 	return &user, nil
 }
 
@@ -48,7 +105,7 @@ func scanBusiness(rows *sql.Rows) (*model.Business, error) {
 	var business model.Business
 	var createdAt, updatedAt time.Time
 	var description sql.NullString
-	
+
 	err := rows.Scan(
 		&business.ID,
 		&business.Name,
@@ -60,17 +117,17 @@ func scanBusiness(rows *sql.Rows) (*model.Business, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if description.Valid {
 		business.Description = &description.String
 	}
-	
+
 	business.CreatedAt = createdAt.Format(time.RFC3339)
 	if !updatedAt.IsZero() {
 		updatedAtStr := updatedAt.Format(time.RFC3339)
 		business.UpdatedAt = &updatedAtStr
 	}
-	
+
 	return &business, nil
 }
 
@@ -137,7 +194,7 @@ func scanComplianceCheck(rows *sql.Rows) (*model.ComplianceCheck, error) {
 	var createdAt, updatedAt, checkedAt, dueDate time.Time
 	var notes sql.NullString
 	var checkedByID sql.NullString
-	
+
 	err := rows.Scan(
 		&check.ID,
 		&check.LicenseID,
@@ -153,18 +210,18 @@ func scanComplianceCheck(rows *sql.Rows) (*model.ComplianceCheck, error) {
 	if err != nil {
 		return nil, err
 	}
-	
+
 	if notes.Valid {
 		check.Notes = &notes.String
 	}
-	
+
 	check.DueDate = dueDate.Format(time.RFC3339)
 	check.CreatedAt = createdAt.Format(time.RFC3339)
 	if !updatedAt.IsZero() {
 		updatedAtStr := updatedAt.Format(time.RFC3339)
 		check.UpdatedAt = &updatedAtStr
 	}
-	
+
 	return &check, nil
 }
 
@@ -193,6 +250,7 @@ func scanJurisdiction(s scanner) (*model.Jurisdiction, error) {
 	var jurisdiction model.Jurisdiction
 	var licenseTypesStr sql.NullString
 	var createdAt, updatedAt sql.NullString
+	var formats []byte
 
 	err := s.Scan(
 		&jurisdiction.ID,
@@ -204,6 +262,7 @@ func scanJurisdiction(s scanner) (*model.Jurisdiction, error) {
 		&licenseTypesStr,
 		&createdAt,
 		&updatedAt,
+		&formats,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -211,6 +270,9 @@ func scanJurisdiction(s scanner) (*model.Jurisdiction, error) {
 		}
 		return nil, fmt.Errorf("failed to get jurisdiction: %v", err)
 	}
+	if err := json.Unmarshal(formats, &jurisdiction.LicenseNumberFormats); err != nil {
+		return nil, fmt.Errorf("failed to decode license number formats: %v", err)
+	}
 
 	if licenseTypesStr.Valid {
 		jurisdiction.LicenseTypes = parsePostgresArray(licenseTypesStr.String)
@@ -225,11 +287,11 @@ func scanJurisdiction(s scanner) (*model.Jurisdiction, error) {
 	return &jurisdiction, nil
 }
 
-
 // buildUpdateQuery dynamically constructs an SQL UPDATE statement.
 // It takes a map of column names to their new values.
 // It only includes non-nil values in the SET clause.
-func buildUpdateQuery(table string, id string, updates map[string]interface{}) (string, []interface{}) {
+// The updated row is returned with the given column list.
+func buildUpdateQuery(table string, id string, updates map[string]interface{}, returning string) (string, []interface{}) {
 	var setClauses []string
 	args := []interface{}{}
 	argIndex := 1
@@ -255,6 +317,12 @@ func buildUpdateQuery(table string, id string, updates map[string]interface{}) (
 				args = append(args, *v)
 				argIndex++
 			}
+		case *int:
+			if v != nil {
+				setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, argIndex))
+				args = append(args, *v)
+				argIndex++
+			}
 		case *float64:
 			if v != nil {
 				setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, argIndex))
@@ -274,6 +342,30 @@ func buildUpdateQuery(table string, id string, updates map[string]interface{}) (
 				args = append(args, *v)
 				argIndex++
 			}
+		case *model.LicenseType:
+			if v != nil {
+				setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, argIndex))
+				args = append(args, *v)
+				argIndex++
+			}
+		case *model.BusinessType:
+			if v != nil {
+				setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, argIndex))
+				args = append(args, *v)
+				argIndex++
+			}
+		case *model.UserRole:
+			if v != nil {
+				setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, argIndex))
+				args = append(args, *v)
+				argIndex++
+			}
+		case pq.StringArray:
+			if v != nil {
+				setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, argIndex))
+				args = append(args, v)
+				argIndex++
+			}
 		}
 	}
 
@@ -286,8 +378,181 @@ func buildUpdateQuery(table string, id string, updates map[string]interface{}) (
 	args = append(args, time.Now())
 	argIndex++
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d RETURNING *", table, strings.Join(setClauses, ", "), argIndex)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = $%d RETURNING %s", table, strings.Join(setClauses, ", "), argIndex, returning)
 	args = append(args, id)
 
 	return query, args
-}
\ No newline at end of file
+}
+
+// updateRow applies the non-nil updates to the row with the given id and
+// scans the result into dest. With no updates the current row is re-read.
+func updateRow(ctx context.Context, tx *sqlx.Tx, dest interface{}, table, entity, id string, updates map[string]interface{}, columns string) error {
+	query, args := buildUpdateQuery(table, id, updates, columns)
+	if query == "" {
+		query = fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", columns, table)
+		args = []interface{}{id}
+	}
+	return getOrNotFound(tx.GetContext(ctx, dest, query, args...), entity, id)
+}
+
+// deleteRow deletes the row with the given id, reporting NOT_FOUND if it did not exist
+func deleteRow(ctx context.Context, tx *sqlx.Tx, table, entity, id string) error {
+	result, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", table), id)
+	if err != nil {
+		return dbError(err, "delete "+entity)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return dbError(err, "delete "+entity)
+	}
+	if affected == 0 {
+		return notFoundError(entity, id)
+	}
+	return nil
+}
+
+// requireExists reports NOT_FOUND unless a row with the given id exists in table
+func requireExists(ctx context.Context, q sqlx.QueryerContext, table, entity, id string) error {
+	var found bool
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE id = $1)", table)
+	if err := sqlx.GetContext(ctx, q, &found, query, id); err != nil {
+		return dbError(err, "look up "+entity)
+	}
+	if !found {
+		return notFoundError(entity, id)
+	}
+	return nil
+}
+
+// withTx runs fn inside a single transaction scoped to the viewer and
+// tagged with the request for the audit log, committing only if fn succeeds
+func (r *Resolver) withTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := r.applyScope(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tagChanges(ctx, tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return dbError(tx.Commit(), "commit transaction")
+}
+
+// currentUserID returns the users.id of the authenticated caller
+func currentUserID(ctx context.Context, q sqlx.QueryerContext) (string, error) {
+	authUser := auth.ForContext(ctx)
+	if authUser == nil {
+		return "", fmt.Errorf("access denied: user not authenticated")
+	}
+	var id string
+	err := sqlx.GetContext(ctx, q, &id, "SELECT id FROM users WHERE firebase_uid = $1", authUser.UID)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("access denied: no user profile for the authenticated account")
+	}
+	if err != nil {
+		return "", dbError(err, "look up current user")
+	}
+	return id, nil
+}
+
+// parseDate validates a DateTime input that is stored in a DATE column and
+// normalises it to YYYY-MM-DD. Both plain dates and RFC 3339 timestamps are accepted.
+func parseDate(field, value string) (string, error) {
+	t, err := parseDateTime(field, value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(time.DateOnly), nil
+}
+
+// requireDateOrder fails validation of expirationDate if it is before
+// issuedDate, both as returned by parseDate
+func requireDateOrder(issuedDate, expirationDate string) error {
+	if expirationDate < issuedDate {
+		return validationError("expirationDate", "expirationDate must not be before issuedDate")
+	}
+	return nil
+}
+
+// parseDateTime validates a DateTime input. Both plain dates and RFC 3339
+// timestamps are accepted.
+func parseDateTime(field, value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, validationError(field, "%s must be a date (YYYY-MM-DD) or an RFC 3339 timestamp", field)
+}
+
+// requireNonBlank reports a validation error if value is empty or whitespace
+func requireNonBlank(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return validationError(field, "%s must not be blank", field)
+	}
+	return nil
+}
+
+// requireLocationOfBusiness reports an error unless the location exists and belongs to the business
+func requireLocationOfBusiness(ctx context.Context, q sqlx.QueryerContext, locationID, businessID string) error {
+	var owner string
+	err := sqlx.GetContext(ctx, q, &owner, "SELECT business_id FROM locations WHERE id = $1", locationID)
+	if err != nil {
+		return getOrNotFound(err, "location", locationID)
+	}
+	if owner != businessID {
+		return validationError("locationId", "location %s does not belong to business %s", locationID, businessID)
+	}
+	return nil
+}
+
+// clearPrimaryLocation unsets the primary flag on every location of the
+// business except exceptID, so a new primary location can be set.
+func clearPrimaryLocation(ctx context.Context, tx *sqlx.Tx, businessID, exceptID string) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE locations
+		SET is_primary = FALSE, updated_at = NOW()
+		WHERE business_id = $1 AND is_primary AND id::text <> $2
+	`, businessID, exceptID)
+	return err
+}
+
+// isCheckOutcome reports whether a compliance status records the result of a
+// performed check, as opposed to one still waiting for review.
+func isCheckOutcome(status model.ComplianceStatus) bool {
+	return status != model.ComplianceStatusPendingReview
+}
+
+// Helper function to get a user by id, batched through the operation's loaders
+func (r *Resolver) getUser(ctx context.Context, id string) (*model.User, error) {
+	return r.loaders(ctx).users.Load(ctx, id)()
+}
+
+// Helper function to get a business by id
+func (r *Resolver) getBusiness(ctx context.Context, id string) (*model.Business, error) {
+	return r.loaders(ctx).businesses.Load(ctx, id)()
+}
+
+// Helper function to get a license by id
+func (r *Resolver) getLicense(ctx context.Context, id string) (*model.License, error) {
+	return r.loaders(ctx).licenses.Load(ctx, id)()
+}
+
+// Helper function to get a location by id
+func (r *Resolver) getLocation(ctx context.Context, id string) (*model.Location, error) {
+	return r.loaders(ctx).locations.Load(ctx, id)()
+}
+
+// Helper function to get a jurisdiction by id
+func (r *Resolver) getJurisdiction(ctx context.Context, id string) (*model.Jurisdiction, error) {
+	return r.loaders(ctx).jurisdictions.Load(ctx, id)()
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestBuildUpdateQuery_SkipsNilValues(t *testing.T) {
+	name := "Green Leaf"
+	var description *string
+	status := model.LicenseStatusSuspended
+
+	query, args := buildUpdateQuery("licenses", "license-1", map[string]interface{}{
+		"name":        &name,
+		"description": description,
+		"status":      &status,
+	}, "id, name")
+
+	require.NotEmpty(t, query)
+	assert.True(t, strings.HasPrefix(query, "UPDATE licenses SET "))
+	assert.Contains(t, query, "name = $")
+	assert.Contains(t, query, "status = $")
+	assert.NotContains(t, query, "description")
+	assert.Contains(t, query, "updated_at = $3")
+	assert.True(t, strings.HasSuffix(query, "WHERE id = $4 RETURNING id, name"))
+
+	require.Len(t, args, 4)
+	assert.Equal(t, "license-1", args[3])
+}
+
+func TestBuildUpdateQuery_Ints(t *testing.T) {
+	leadDays := 7
+	query, args := buildUpdateQuery("compliance_schedules", "s-1", map[string]interface{}{"lead_days": &leadDays}, "id")
+	assert.True(t, strings.HasPrefix(query, "UPDATE compliance_schedules SET lead_days = $1, "))
+	require.Len(t, args, 3)
+	assert.Equal(t, 7, args[0])
+}
+
+func TestBuildUpdateQuery_NoUpdates(t *testing.T) {
+	var name *string
+	query, args := buildUpdateQuery("businesses", "b-1", map[string]interface{}{"name": name}, "id")
+	assert.Empty(t, query)
+	assert.Nil(t, args)
+}
+
+func TestDBError_TranslatesPostgresErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"unique violation", &pq.Error{Code: pgUniqueViolation, Detail: "Key (email)=(a@b.c) already exists."}, ErrCodeConflict},
+		{"foreign key violation", &pq.Error{Code: pgForeignKeyViolation}, ErrCodeConflict},
+		{"check violation", &pq.Error{Code: pgCheckViolation, Column: "status"}, ErrCodeValidation},
+		{"invalid uuid", &pq.Error{Code: pgInvalidText}, ErrCodeValidation},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gqlErr *gqlerror.Error
+			require.ErrorAs(t, dbError(tc.err, "save"), &gqlErr)
+			assert.Equal(t, tc.code, gqlErr.Extensions["code"])
+		})
+	}
+
+	assert.Contains(t, dbError(&pq.Error{Code: pgUniqueViolation, Detail: "Key (email)=(a@b.c) already exists."}, "create user").Error(), "same email")
+	assert.NoError(t, dbError(nil, "save"))
+}
+
+func TestParseDate(t *testing.T) {
+	date, err := parseDate("issuedDate", "2025-03-01T10:00:00Z")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01", date)
+
+	date, err = parseDate("issuedDate", "2025-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-01", date)
+
+	_, err = parseDate("issuedDate", "03/01/2025")
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, "issuedDate", gqlErr.Extensions["field"])
+}
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// loaderWait is how long a loader collects keys before running its batch.
+// Sibling fields of a list resolve concurrently, so a short window is enough.
+const loaderWait = 2 * time.Millisecond
+
+type loadersKey struct{}
+
+// fetchers load the rows behind the dataloaders. Every call is one query.
+type fetchers struct {
+	users         func(ctx context.Context, ids []string) ([]*model.User, error)
+	businesses    func(ctx context.Context, ids []string) ([]*model.Business, error)
+	licenses      func(ctx context.Context, ids []string) ([]*model.License, error)
+	locations     func(ctx context.Context, ids []string) ([]*model.Location, error)
+	jurisdictions func(ctx context.Context, ids []string) ([]*model.Jurisdiction, error)
+
+	// licensesByBusiness, licensesByLocation and locationsByBusiness take
+	// parent ids and return the children of all of them
+	licensesByBusiness  func(ctx context.Context, businessIDs []string) ([]*model.License, error)
+	licensesByLocation  func(ctx context.Context, locationIDs []string) ([]*model.License, error)
+	locationsByBusiness func(ctx context.Context, businessIDs []string) ([]*model.Location, error)
+}
+
+// loaders batch the lookups that nested field resolvers make per parent
+// object, so that a list of N parents costs one query per field instead of N
+type loaders struct {
+	users         *dataloader.Loader[string, *model.User]
+	businesses    *dataloader.Loader[string, *model.Business]
+	licenses      *dataloader.Loader[string, *model.License]
+	locations     *dataloader.Loader[string, *model.Location]
+	jurisdictions *dataloader.Loader[string, *model.Jurisdiction]
+
+	licensesByBusiness  *dataloader.Loader[string, []*model.License]
+	licensesByLocation  *dataloader.Loader[string, []*model.License]
+	locationsByBusiness *dataloader.Loader[string, []*model.Location]
+}
+
+// newLoaders creates loaders that collect keys for wait before each batch
+func newLoaders(f fetchers, wait time.Duration) *loaders {
+	return &loaders{
+		users:         newLoader(wait, byID("user", f.users, func(u *model.User) string { return u.ID })),
+		businesses:    newLoader(wait, byID("business", f.businesses, func(b *model.Business) string { return b.ID })),
+		licenses:      newLoader(wait, byID("license", f.licenses, func(l *model.License) string { return l.ID })),
+		locations:     newLoader(wait, byID("location", f.locations, func(l *model.Location) string { return l.ID })),
+		jurisdictions: newLoader(wait, byID("jurisdiction", f.jurisdictions, func(j *model.Jurisdiction) string { return j.ID })),
+
+		licensesByBusiness:  newLoader(wait, groupedBy(f.licensesByBusiness, func(l *model.License) string { return l.BusinessID })),
+		licensesByLocation:  newLoader(wait, groupedBy(f.licensesByLocation, func(l *model.License) string { return deref(l.LocationID) })),
+		locationsByBusiness: newLoader(wait, groupedBy(f.locationsByBusiness, func(l *model.Location) string { return l.BusinessID })),
+	}
+}
+
+func newLoader[V any](wait time.Duration, batch dataloader.BatchFunc[string, V]) *dataloader.Loader[string, V] {
+	return dataloader.NewBatchedLoader(batch, dataloader.WithWait[string, V](wait))
+}
+
+// byID adapts fetch to a batch function that returns the row of each key,
+// or a NOT_FOUND error for keys without one
+func byID[T any](entity string, fetch func(context.Context, []string) ([]*T, error), id func(*T) string) dataloader.BatchFunc[string, *T] {
+	return func(ctx context.Context, keys []string) []*dataloader.Result[*T] {
+		rows, err := fetch(ctx, keys)
+		results := make([]*dataloader.Result[*T], len(keys))
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[*T]{Error: err}
+			}
+			return results
+		}
+
+		byKey := make(map[string]*T, len(rows))
+		for _, row := range rows {
+			byKey[id(row)] = row
+		}
+		for i, key := range keys {
+			if row, ok := byKey[key]; ok {
+				results[i] = &dataloader.Result[*T]{Data: row}
+			} else {
+				results[i] = &dataloader.Result[*T]{Error: notFoundError(entity, key)}
+			}
+		}
+		return results
+	}
+}
+
+// groupedBy adapts fetch to a batch function that returns the rows whose
+// parent is each key, in the order fetch returned them
+func groupedBy[T any](fetch func(context.Context, []string) ([]*T, error), parent func(*T) string) dataloader.BatchFunc[string, []*T] {
+	return func(ctx context.Context, keys []string) []*dataloader.Result[[]*T] {
+		rows, err := fetch(ctx, keys)
+		results := make([]*dataloader.Result[[]*T], len(keys))
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[[]*T]{Error: err}
+			}
+			return results
+		}
+
+		byParent := make(map[string][]*T, len(keys))
+		for _, row := range rows {
+			byParent[parent(row)] = append(byParent[parent(row)], row)
+		}
+		for i, key := range keys {
+			results[i] = &dataloader.Result[[]*T]{Data: byParent[key]}
+		}
+		return results
+	}
+}
+
+// dbFetchers loads the dataloader rows from Postgres. Each batch runs in a
+// read transaction scoped to the viewer of the Load call that started it,
+// so the row-level security policies apply as they do to the resolvers.
+func (r *Resolver) dbFetchers() fetchers {
+	return fetchers{
+		users: selectByKeys[model.User](r, "get users",
+			`SELECT `+userColumns+` FROM users WHERE id = ANY($1::uuid[])`),
+		businesses: selectByKeys[model.Business](r, "get businesses",
+			`SELECT `+businessColumns+` FROM businesses WHERE id = ANY($1::uuid[])`),
+		licenses: selectByKeys[model.License](r, "get licenses",
+			`SELECT `+licenseColumns+` FROM licenses WHERE id = ANY($1::uuid[])`),
+		locations: selectByKeys[model.Location](r, "get locations",
+			`SELECT `+locationColumns+` FROM locations WHERE id = ANY($1::uuid[])`),
+		jurisdictions: func(ctx context.Context, ids []string) ([]*model.Jurisdiction, error) {
+			var jurisdictions []*model.Jurisdiction
+			err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+				rows, err := tx.QueryxContext(ctx,
+					`SELECT `+jurisdictionColumns+` FROM jurisdictions WHERE id = ANY($1::uuid[])`, pq.Array(ids))
+				if err != nil {
+					return dbError(err, "get jurisdictions")
+				}
+				defer rows.Close()
+
+				for rows.Next() {
+					jurisdiction, err := scanJurisdiction(rows)
+					if err != nil {
+						return dbError(err, "get jurisdictions")
+					}
+					jurisdictions = append(jurisdictions, jurisdiction)
+				}
+				return dbError(rows.Err(), "get jurisdictions")
+			})
+			return jurisdictions, err
+		},
+
+		licensesByBusiness: selectByKeys[model.License](r, "get business licenses", `
+			SELECT `+licenseColumns+` FROM licenses
+			WHERE business_id = ANY($1::uuid[])
+			ORDER BY expiration_date ASC, id ASC`),
+		licensesByLocation: selectByKeys[model.License](r, "get location licenses", `
+			SELECT `+licenseColumns+` FROM licenses
+			WHERE location_id = ANY($1::uuid[])
+			ORDER BY expiration_date ASC, id ASC`),
+		locationsByBusiness: selectByKeys[model.Location](r, "get business locations", `
+			SELECT `+locationColumns+` FROM locations
+			WHERE business_id = ANY($1::uuid[])
+			ORDER BY is_primary DESC, created_at ASC, id ASC`),
+	}
+}
+
+// selectByKeys returns a fetcher running query with the keys as its only
+// argument, in a read transaction scoped to the viewer
+func selectByKeys[T any](r *Resolver, action, query string) func(context.Context, []string) ([]*T, error) {
+	return func(ctx context.Context, keys []string) ([]*T, error) {
+		var rows []*T
+		err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+			return dbError(tx.SelectContext(ctx, &rows, query, pq.Array(keys)), action)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return rows, nil
+	}
+}
+
+// DataLoaders returns a gqlgen operation middleware (handler.AroundOperations)
+// that gives every query and mutation its own set of dataloaders. Loaders
+// cache what they load, so subscriptions, which live on for many events,
+// are left without and load fresh rows for each event.
+func DataLoaders(r *Resolver) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if op := graphql.GetOperationContext(ctx).Operation; op != nil && op.Operation == ast.Subscription {
+			return next(ctx)
+		}
+		return next(withLoaders(ctx, newLoaders(r.dbFetchers(), loaderWait)))
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loaders returns the operation's dataloaders. Outside an operation every
+// call gets fresh loaders, which still work but batch nothing.
+func (r *Resolver) loaders(ctx context.Context) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+	return newLoaders(r.dbFetchers(), loaderWait)
+}
@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// testLoaderWait is long enough for every goroutine of a test to queue its
+// key before the batch runs, even under the race detector
+const testLoaderWait = 100 * time.Millisecond
+
+// queryCounter stands in for the database, counting one query per fetch
+type queryCounter struct {
+	queries atomic.Int32
+}
+
+func countingFetch[T any](c *queryCounter, row func(key string) *T) func(context.Context, []string) ([]*T, error) {
+	return func(_ context.Context, keys []string) ([]*T, error) {
+		c.queries.Add(1)
+		rows := make([]*T, 0, len(keys))
+		for _, key := range keys {
+			if r := row(key); r != nil {
+				rows = append(rows, r)
+			}
+		}
+		return rows, nil
+	}
+}
+
+func fakeFetchers(c *queryCounter) fetchers {
+	return fetchers{
+		users:         countingFetch(c, func(id string) *model.User { return &model.User{ID: id} }),
+		businesses:    countingFetch(c, func(id string) *model.Business { return &model.Business{ID: id} }),
+		licenses:      countingFetch(c, func(id string) *model.License { return &model.License{ID: id} }),
+		locations:     countingFetch(c, func(id string) *model.Location { return &model.Location{ID: id} }),
+		jurisdictions: countingFetch(c, func(id string) *model.Jurisdiction { return &model.Jurisdiction{ID: id} }),
+		licensesByBusiness: countingFetch(c, func(id string) *model.License {
+			return &model.License{ID: "license-of-" + id, BusinessID: id}
+		}),
+		licensesByLocation: countingFetch(c, func(id string) *model.License {
+			return &model.License{ID: "license-at-" + id, LocationID: &id}
+		}),
+		locationsByBusiness: countingFetch(c, func(id string) *model.Location {
+			return &model.Location{ID: "location-of-" + id, BusinessID: id}
+		}),
+	}
+}
+
+// resolveAll runs resolve for every parent concurrently, as gqlgen does for
+// the items of a list field
+func resolveAll[P any](t *testing.T, parents []*P, resolve func(*P) error) {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make([]error, len(parents))
+	for i, parent := range parents {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = resolve(parent)
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, errors.Join(errs...))
+}
+
+func TestDataLoadersBatchLicenseFields(t *testing.T) {
+	var counter queryCounter
+	ctx := withLoaders(context.Background(), newLoaders(fakeFetchers(&counter), testLoaderWait))
+	r := &Resolver{}
+
+	// A page of licenses spread over a handful of businesses
+	var licenses []*model.License
+	for i := range maxPageSize {
+		location := fmt.Sprintf("location-%d", i%7)
+		licenses = append(licenses, &model.License{
+			ID:             fmt.Sprintf("license-%d", i),
+			BusinessID:     fmt.Sprintf("business-%d", i%5),
+			LocationID:     &location,
+			JurisdictionID: fmt.Sprintf("jurisdiction-%d", i%3),
+		})
+	}
+
+	resolveAll(t, licenses, func(l *model.License) error {
+		business, err := r.License().Business(ctx, l)
+		if err == nil {
+			assert.Equal(t, l.BusinessID, business.ID)
+		}
+		return err
+	})
+	assert.EqualValues(t, 1, counter.queries.Load(), "business of every license in one query")
+
+	resolveAll(t, licenses, func(l *model.License) error {
+		if _, err := r.License().Location(ctx, l); err != nil {
+			return err
+		}
+		_, err := r.License().Jurisdiction(ctx, l)
+		return err
+	})
+	assert.EqualValues(t, 3, counter.queries.Load(), "one query each for locations and jurisdictions")
+
+	// Loaders cache for the rest of the operation
+	resolveAll(t, licenses, func(l *model.License) error {
+		_, err := r.License().Business(ctx, l)
+		return err
+	})
+	assert.EqualValues(t, 3, counter.queries.Load())
+}
+
+func TestDataLoadersBatchUserFields(t *testing.T) {
+	var counter queryCounter
+	ctx := withLoaders(context.Background(), newLoaders(fakeFetchers(&counter), testLoaderWait))
+	r := &Resolver{}
+
+	var notifications []*model.Notification
+	var checks []*model.ComplianceCheck
+	for i := range maxPageSize {
+		userID := fmt.Sprintf("user-%d", i%10)
+		notifications = append(notifications, &model.Notification{ID: fmt.Sprintf("n-%d", i), UserID: userID})
+		checks = append(checks, &model.ComplianceCheck{
+			ID:        fmt.Sprintf("c-%d", i),
+			LicenseID: fmt.Sprintf("license-%d", i),
+			UserID:    &userID,
+		})
+	}
+
+	resolveAll(t, notifications, func(n *model.Notification) error {
+		_, err := r.Notification().NotificationUser(ctx, n)
+		return err
+	})
+	resolveAll(t, checks, func(c *model.ComplianceCheck) error {
+		if _, err := r.ComplianceCheck().ComplianceCheckUser(ctx, c); err != nil {
+			return err
+		}
+		_, err := r.ComplianceCheck().ComplianceCheckLicense(ctx, c)
+		return err
+	})
+	assert.EqualValues(t, 2, counter.queries.Load(), "one query for users, one for licenses")
+}
+
+func TestDataLoadersGroupChildren(t *testing.T) {
+	var counter queryCounter
+	ctx := withLoaders(context.Background(), newLoaders(fakeFetchers(&counter), testLoaderWait))
+	r := &Resolver{}
+
+	var businesses []*model.Business
+	for i := range 50 {
+		businesses = append(businesses, &model.Business{ID: fmt.Sprintf("business-%d", i)})
+	}
+	resolveAll(t, businesses, func(b *model.Business) error {
+		licenses, err := r.Business().Licenses(ctx, b)
+		if err != nil {
+			return err
+		}
+		locations, err := r.Business().Locations(ctx, b)
+		if err != nil {
+			return err
+		}
+		if assert.Len(t, licenses, 1) && assert.Len(t, locations, 1) {
+			assert.Equal(t, b.ID, licenses[0].BusinessID)
+			assert.Equal(t, b.ID, locations[0].BusinessID)
+		}
+		return nil
+	})
+	assert.EqualValues(t, 2, counter.queries.Load())
+}
+
+func TestDataLoadersMissingAndFailedRows(t *testing.T) {
+	f := fakeFetchers(new(queryCounter))
+	f.users = func(context.Context, []string) ([]*model.User, error) { return nil, nil }
+	f.businesses = func(context.Context, []string) ([]*model.Business, error) {
+		return nil, errors.New("connection reset")
+	}
+	ctx := withLoaders(context.Background(), newLoaders(f, testLoaderWait))
+	r := &Resolver{}
+
+	_, err := r.getUser(ctx, "missing")
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, ErrCodeNotFound, gqlErr.Extensions["code"])
+
+	_, err = r.getBusiness(ctx, "any")
+	assert.EqualError(t, err, "connection reset")
+
+	location, err := r.License().Location(ctx, &model.License{ID: "no-location"})
+	require.NoError(t, err)
+	assert.Nil(t, location)
+}
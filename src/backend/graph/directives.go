@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"budsafe/backend/graph/generated"
+	"budsafe/backend/graph/model"
+	"context"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Directives returns the implementations of the schema directives, to be
+// passed as generated.Config.Directives.
+func (r *Resolver) Directives() generated.DirectiveRoot {
+	return generated.DirectiveRoot{
+		Auth:    r.authDirective,
+		HasRole: r.hasRoleDirective,
+	}
+}
+
+// authDirective implements @auth: the caller must present a verified Firebase
+// token that maps to a users row.
+func (r *Resolver) authDirective(ctx context.Context, obj interface{}, next graphql.Resolver) (interface{}, error) {
+	if _, err := r.requireViewer(ctx); err != nil {
+		return nil, err
+	}
+	return next(ctx)
+}
+
+// hasRoleDirective implements @hasRole: the caller's role must be one of
+// roles. ADMIN is allowed everywhere.
+func (r *Resolver) hasRoleDirective(ctx context.Context, obj interface{}, next graphql.Resolver, roles []model.UserRole) (interface{}, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRole(viewer, roles...) {
+		return nil, forbiddenError("requires one of the roles %v", roles)
+	}
+	return next(ctx)
+}
+
+// hasRole reports whether the user has one of roles. ADMIN always passes.
+func hasRole(user *model.User, roles ...model.UserRole) bool {
+	return user.Role == model.UserRoleAdmin || slices.Contains(roles, user.Role)
+}
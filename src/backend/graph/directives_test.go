@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestHasRole(t *testing.T) {
+	owner := &model.User{Role: model.UserRoleBusinessOwner}
+	admin := &model.User{Role: model.UserRoleAdmin}
+	employee := &model.User{Role: model.UserRoleEmployee}
+
+	assert.True(t, hasRole(owner, model.UserRoleBusinessOwner, model.UserRoleComplianceManager))
+	assert.False(t, hasRole(employee, model.UserRoleBusinessOwner, model.UserRoleComplianceManager))
+	assert.True(t, hasRole(admin, model.UserRoleBusinessOwner), "ADMIN passes every role check")
+	assert.True(t, hasRole(admin))
+}
+
+func TestDirectives_RejectUnauthenticated(t *testing.T) {
+	r := &Resolver{}
+	called := false
+	next := func(ctx context.Context) (interface{}, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err := r.authDirective(context.Background(), nil, next)
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, ErrCodeUnauthenticated, gqlErr.Extensions["code"])
+
+	_, err = r.hasRoleDirective(context.Background(), nil, next, []model.UserRole{model.UserRoleBusinessOwner})
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, ErrCodeUnauthenticated, gqlErr.Extensions["code"])
+
+	assert.False(t, called, "the field resolver must not run for unauthenticated callers")
+}
+
+// Roles in a business come from its memberships, which the resolvers check;
+// a global role gate would turn away members whose users.role differs
+func TestHasRole_OnlyGuardsGlobalActions(t *testing.T) {
+	source, err := os.ReadFile("schema.graphqls")
+	require.NoError(t, err)
+	schema, gqlErr := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphqls", Input: string(source)})
+	require.Nil(t, gqlErr)
+
+	for _, typ := range []*ast.Definition{schema.Query, schema.Mutation, schema.Subscription} {
+		for _, field := range typ.Fields {
+			directive := field.Directives.ForName("hasRole")
+			if directive == nil {
+				continue
+			}
+			roles := directive.Arguments.ForName("roles").Value.Children
+			require.Len(t, roles, 1, field.Name)
+			assert.Equal(t, string(model.UserRoleAdmin), roles[0].Value.Raw, field.Name)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"budsafe/backend/graph/model"
+	"budsafe/backend/storage"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jmoiron/sqlx"
+)
+
+// defaultDownloadURLTTL is how long a signed document URL stays valid
+const defaultDownloadURLTTL = 15 * time.Minute
+
+// DocumentsPrefix is the storage key prefix of document files, uploaded or
+// generated
+const DocumentsPrefix = "documents"
+
+// documentFile is the validated file source of a CreateDocumentInput
+type documentFile struct {
+	upload   *graphql.Upload
+	url      string
+	fileType string
+}
+
+// checkDocumentFile validates that a document input carries exactly one of
+// an upload or an external URL with its type
+func (r *Resolver) checkDocumentFile(upload *graphql.Upload, fileURL, fileType *string) (*documentFile, error) {
+	switch {
+	case upload != nil && fileURL != nil:
+		return nil, validationError("file", "provide either file or fileUrl, not both")
+	case upload != nil:
+		if r.Storage == nil {
+			return nil, validationError("file", "file uploads are not enabled on this server")
+		}
+		if limit := r.UploadPolicy.Limit(); upload.Size > limit {
+			return nil, validationError("file", "file is %d bytes; the limit is %d bytes", upload.Size, limit)
+		}
+		return &documentFile{upload: upload}, nil
+	case fileURL != nil:
+		if u, err := url.Parse(*fileURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, validationError("fileUrl", "fileUrl must be an absolute URL")
+		}
+		if fileType == nil {
+			return nil, validationError("fileType", "fileType is required with fileUrl")
+		}
+		if err := requireNonBlank("fileType", *fileType); err != nil {
+			return nil, err
+		}
+		return &documentFile{url: *fileURL, fileType: *fileType}, nil
+	default:
+		return nil, validationError("file", "a document needs a file or a fileUrl")
+	}
+}
+
+// validityPeriod is the parsed validFrom/validUntil of a document input
+type validityPeriod struct {
+	from, until *string
+}
+
+// parseValidity validates a document's validity dates
+func parseValidity(validFrom, validUntil *string) (validityPeriod, error) {
+	var period validityPeriod
+	if validFrom != nil {
+		from, err := parseDate("validFrom", *validFrom)
+		if err != nil {
+			return period, err
+		}
+		period.from = &from
+	}
+	if validUntil != nil {
+		until, err := parseDate("validUntil", *validUntil)
+		if err != nil {
+			return period, err
+		}
+		period.until = &until
+	}
+	// Dates are formatted as YYYY-MM-DD, so they compare as strings
+	if period.from != nil && period.until != nil && *period.until < *period.from {
+		return period, validationError("validUntil", "validUntil must not be before validFrom")
+	}
+	return period, nil
+}
+
+// documentRow holds the columns of a new document besides its file
+type documentRow struct {
+	name                 string
+	description          *string
+	uploadedByID         string
+	licenseID            *string
+	renewalRequirementID *string
+	category             *model.DocumentCategory
+	validity             validityPeriod
+	// seriesID and version place the row in an existing series; a nil
+	// seriesID starts a new one
+	seriesID *string
+	version  int
+}
+
+// insertDocument stores the file, if it is an upload, and inserts the
+// document. The returned object must be discarded if the transaction does
+// not commit.
+func (r *Resolver) insertDocument(ctx context.Context, tx *sqlx.Tx, row documentRow, file *documentFile) (*model.Document, *storage.Object, error) {
+	fileURL, fileType := &file.url, file.fileType
+	var stored *storage.Object
+	var storageKey, checksum *string
+	var fileSize *int64
+	if file.upload != nil {
+		var err error
+		if stored, err = r.storeUpload(ctx, file.upload); err != nil {
+			return nil, nil, err
+		}
+		fileURL, fileType = nil, stored.ContentType
+		storageKey, fileSize, checksum = &stored.Key, &stored.Size, &stored.SHA256
+	}
+	if row.version == 0 {
+		row.version = 1
+	}
+
+	var document model.Document
+	err := tx.GetContext(ctx, &document, `
+		INSERT INTO documents (name, description, file_url, file_type, category, storage_key, file_size,
+		                       checksum_sha256, series_id, version, valid_from, valid_until,
+		                       uploaded_by_id, license_id, renewal_requirement_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING `+documentColumns,
+		row.name, row.description, fileURL, fileType, row.category, storageKey, fileSize, checksum,
+		row.seriesID, row.version, row.validity.from, row.validity.until,
+		row.uploadedByID, row.licenseID, row.renewalRequirementID)
+	return &document, stored, err
+}
+
+// storeUpload saves an uploaded document file, translating policy
+// violations into validation errors on the file field
+func (r *Resolver) storeUpload(ctx context.Context, upload *graphql.Upload) (*storage.Object, error) {
+	obj, err := storage.Save(ctx, r.Storage, r.UploadPolicy, DocumentsPrefix, upload.Filename, upload.File)
+	if errors.Is(err, storage.ErrTooLarge) || errors.Is(err, storage.ErrTypeNotAllowed) {
+		return nil, validationError("file", "%s", err.Error())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store document file: %w", err)
+	}
+	return obj, nil
+}
+
+// discardStoredFile removes a stored file whose document row was not
+// written or has been deleted. Failures only leave an orphaned file, so
+// they are logged rather than returned.
+func (r *Resolver) discardStoredFile(key string) {
+	if err := r.Storage.Delete(context.Background(), key); err != nil {
+		log.Printf("Failed to delete stored document file %s: %v", key, err)
+	}
+}
+
+// documentURL returns the download URL of a document: a fresh signed URL
+// for uploaded files, the stored URL for external ones
+func (r *Resolver) documentURL(ctx context.Context, document *model.Document) (string, error) {
+	if document.StorageKey == nil {
+		return deref(document.FileURL), nil
+	}
+	if r.Storage == nil {
+		return "", fmt.Errorf("document storage is not configured")
+	}
+	ttl := r.DownloadURLTTL
+	if ttl <= 0 {
+		ttl = defaultDownloadURLTTL
+	}
+	return r.Storage.SignedURL(ctx, *document.StorageKey, ttl)
+}
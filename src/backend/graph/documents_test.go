@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"budsafe/backend/graph/model"
+	"budsafe/backend/storage"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func assertValidationField(t *testing.T, err error, field string) {
+	t.Helper()
+	var gqlErr *gqlerror.Error
+	if assert.ErrorAs(t, err, &gqlErr) {
+		assert.Equal(t, ErrCodeValidation, gqlErr.Extensions["code"])
+		assert.Equal(t, field, gqlErr.Extensions["field"])
+	}
+}
+
+func TestCheckDocumentFile(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/files", []byte("secret"))
+	require.NoError(t, err)
+	r := &Resolver{Storage: local, UploadPolicy: storage.Policy{MaxSize: 100}}
+
+	link, pdf := "https://example.com/permit.pdf", "application/pdf"
+	upload := &graphql.Upload{File: strings.NewReader("%PDF-"), Filename: "permit.pdf", Size: 5}
+
+	file, err := r.checkDocumentFile(upload, nil, nil)
+	require.NoError(t, err)
+	assert.Same(t, upload, file.upload)
+
+	file, err = r.checkDocumentFile(nil, &link, &pdf)
+	require.NoError(t, err)
+	assert.Equal(t, link, file.url)
+
+	_, err = r.checkDocumentFile(nil, nil, nil)
+	assertValidationField(t, err, "file")
+	_, err = r.checkDocumentFile(upload, &link, nil)
+	assertValidationField(t, err, "file")
+	_, err = r.checkDocumentFile(&graphql.Upload{Size: 101}, nil, nil)
+	assertValidationField(t, err, "file")
+	_, err = r.checkDocumentFile(nil, &link, nil)
+	assertValidationField(t, err, "fileType")
+	relative := "/permit.pdf"
+	_, err = r.checkDocumentFile(nil, &relative, &pdf)
+	assertValidationField(t, err, "fileUrl")
+
+	_, err = (&Resolver{}).checkDocumentFile(upload, nil, nil)
+	assertValidationField(t, err, "file")
+}
+
+func TestParseValidity(t *testing.T) {
+	from, until := "2026-01-01", "2026-12-31T00:00:00Z"
+	period, err := parseValidity(&from, &until)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-01", *period.from)
+	assert.Equal(t, "2026-12-31", *period.until)
+
+	period, err = parseValidity(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, period.from)
+	assert.Nil(t, period.until)
+
+	_, err = parseValidity(&until, &from)
+	assertValidationField(t, err, "validUntil")
+
+	bad := "next year"
+	_, err = parseValidity(&bad, nil)
+	assertValidationField(t, err, "validFrom")
+}
+
+func TestStoreUploadRejectsDisallowedType(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/files", []byte("secret"))
+	require.NoError(t, err)
+	r := &Resolver{Storage: local}
+
+	_, err = r.storeUpload(context.Background(), &graphql.Upload{
+		File:     strings.NewReader("<html><script>alert(1)</script></html>"),
+		Filename: "permit.pdf",
+	})
+	assertValidationField(t, err, "file")
+}
+
+func TestDocumentURL(t *testing.T) {
+	local, err := storage.NewLocal(t.TempDir(), "http://localhost/files", []byte("secret"))
+	require.NoError(t, err)
+	r := &Resolver{Storage: local, DownloadURLTTL: time.Minute}
+	ctx := context.Background()
+
+	external := "https://example.com/permit.pdf"
+	u, err := r.documentURL(ctx, &model.Document{FileURL: &external})
+	require.NoError(t, err)
+	assert.Equal(t, external, u)
+
+	key := "documents/0123.pdf"
+	u, err = r.documentURL(ctx, &model.Document{StorageKey: &key})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(u, "http://localhost/files/documents/0123.pdf?expires="), u)
+	assert.Contains(t, u, "signature=")
+}
@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/lib/pq"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes returned in the "code" extension of GraphQL errors
+const (
+	ErrCodeNotFound   = "NOT_FOUND"
+	ErrCodeValidation = "VALIDATION_FAILED"
+	ErrCodeConflict   = "CONFLICT"
+
+	ErrCodeUnauthenticated = "UNAUTHENTICATED"
+	ErrCodeForbidden       = "FORBIDDEN"
+)
+
+// Postgres error codes we translate into typed GraphQL errors
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgNotNullViolation    = "23502"
+	pgInvalidText         = "22P02"
+	pgInvalidDatetime     = "22007"
+)
+
+// notFoundError reports that the entity with the given id does not exist
+func notFoundError(entity, id string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    fmt.Sprintf("%s with id %s not found", entity, id),
+		Extensions: map[string]any{"code": ErrCodeNotFound, "entity": entity, "id": id},
+	}
+}
+
+// validationError reports an invalid input field
+func validationError(field, format string, args ...any) *gqlerror.Error {
+	err := &gqlerror.Error{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": ErrCodeValidation},
+	}
+	if field != "" {
+		err.Extensions["field"] = field
+	}
+	return err
+}
+
+// conflictError reports a write that clashes with existing data
+func conflictError(format string, args ...any) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": ErrCodeConflict},
+	}
+}
+
+// unauthenticatedError reports a request without a usable identity
+func unauthenticatedError(message string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    "access denied: " + message,
+		Extensions: map[string]any{"code": ErrCodeUnauthenticated},
+	}
+}
+
+// forbiddenError reports an authenticated caller lacking permission
+func forbiddenError(format string, args ...any) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    "access denied: " + fmt.Sprintf(format, args...),
+		Extensions: map[string]any{"code": ErrCodeForbidden},
+	}
+}
+
+// dbError translates a database error into a typed GraphQL error where
+// possible. Unrecognised errors are wrapped with the attempted action.
+func dbError(err error, action string) error {
+	if err == nil {
+		return nil
+	}
+
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) {
+		return err
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case pgUniqueViolation:
+			return conflictError("failed to %s: a record with the same %s already exists", action, uniqueColumns(pqErr))
+		case pgForeignKeyViolation:
+			return conflictError("failed to %s: %s", action, pqErr.Detail)
+		case pgCheckViolation, pgNotNullViolation:
+			return validationError(pqErr.Column, "failed to %s: %s", action, pqErr.Message)
+		case pgInvalidText, pgInvalidDatetime:
+			return validationError("", "failed to %s: %s", action, pqErr.Message)
+		}
+	}
+
+	return fmt.Errorf("failed to %s: %w", action, err)
+}
+
+var uniqueDetailPattern = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// uniqueColumns extracts the column list from a unique violation detail,
+// e.g. `Key (email)=(a@b.c) already exists.` -> "email"
+func uniqueColumns(pqErr *pq.Error) string {
+	if match := uniqueDetailPattern.FindStringSubmatch(pqErr.Detail); match != nil {
+		return match[1]
+	}
+	if pqErr.Constraint != "" {
+		return pqErr.Constraint
+	}
+	return "key"
+}
+
+// getOrNotFound maps sql.ErrNoRows from a single-row lookup to a NOT_FOUND error
+func getOrNotFound(err error, entity, id string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFoundError(entity, id)
+	}
+	return dbError(err, "get "+entity)
+}
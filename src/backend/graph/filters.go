@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"strings"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/lib/pq"
+)
+
+// addDateRange restricts the DATE column to the inclusive range r.
+// field names the input for validation errors.
+func (w *whereClause) addDateRange(column, field string, r *model.DateRange) error {
+	if r == nil {
+		return nil
+	}
+	if r.From != nil {
+		from, err := parseDate(field+".from", *r.From)
+		if err != nil {
+			return err
+		}
+		w.add(column+" >= ?::date", from)
+	}
+	if r.To != nil {
+		to, err := parseDate(field+".to", *r.To)
+		if err != nil {
+			return err
+		}
+		w.add(column+" <= ?::date", to)
+	}
+	return nil
+}
+
+// addTimeRange restricts the TIMESTAMPTZ column to the days of the
+// inclusive range r
+func (w *whereClause) addTimeRange(column, field string, r *model.DateRange) error {
+	if r == nil {
+		return nil
+	}
+	if r.From != nil {
+		from, err := parseDate(field+".from", *r.From)
+		if err != nil {
+			return err
+		}
+		w.add(column+" >= ?::date", from)
+	}
+	if r.To != nil {
+		to, err := parseDate(field+".to", *r.To)
+		if err != nil {
+			return err
+		}
+		w.add(column+" < ?::date + 1", to)
+	}
+	return nil
+}
+
+// searchTerm returns the trimmed search input, or "" when there is none
+func searchTerm(search *string) string {
+	if search == nil {
+		return ""
+	}
+	return strings.TrimSpace(*search)
+}
+
+// businessFilterWhere builds the conditions for a BusinessFilter on the
+// businesses table
+func businessFilterWhere(filter *model.BusinessFilter) (whereClause, error) {
+	var where whereClause
+	where.add("app_can_access_business(id)")
+	if filter == nil {
+		return where, nil
+	}
+
+	if filter.Type != nil {
+		where.add("type = ?", *filter.Type)
+	}
+	if len(filter.Types) > 0 {
+		where.add("type = ANY(?::text[])", enumArray(filter.Types))
+	}
+	if filter.State != nil {
+		where.add(`EXISTS (
+			SELECT 1 FROM locations loc
+			WHERE loc.business_id = businesses.id AND upper(loc.state) = upper(?)
+		)`, strings.TrimSpace(*filter.State))
+	}
+	if term := searchTerm(filter.Search); term != "" {
+		where.add("search_vector @@ websearch_to_tsquery('english', ?)", term)
+	}
+	if err := where.addTimeRange("created_at", "createdAt", filter.CreatedAt); err != nil {
+		return where, err
+	}
+	return where, nil
+}
+
+// licenseFilterWhere builds the conditions for a LicenseFilter on the
+// licenses table
+func licenseFilterWhere(filter *model.LicenseFilter) (whereClause, error) {
+	var where whereClause
+	where.add("app_can_access_business(business_id)")
+	if filter == nil {
+		return where, nil
+	}
+
+	if filter.BusinessID != nil {
+		where.add("business_id = ?", *filter.BusinessID)
+	}
+	if filter.JurisdictionID != nil {
+		where.add("jurisdiction_id = ?", *filter.JurisdictionID)
+	}
+	if filter.LicenseType != nil {
+		where.add("type = ?", *filter.LicenseType)
+	}
+	if filter.Status != nil {
+		where.add("status = ?", *filter.Status)
+	}
+	if len(filter.Statuses) > 0 {
+		where.add("status = ANY(?::text[])", enumArray(filter.Statuses))
+	}
+	if filter.State != nil {
+		where.add(`EXISTS (
+			SELECT 1 FROM locations loc
+			WHERE loc.id = licenses.location_id AND upper(loc.state) = upper(?)
+		)`, strings.TrimSpace(*filter.State))
+	}
+	if term := searchTerm(filter.Search); term != "" {
+		// License numbers are matched by substring as well, since the
+		// parser splits identifiers like C11-0000123 into several tokens
+		where.add(`(
+			licenses.search_vector @@ websearch_to_tsquery('simple', ?)
+			OR license_number ILIKE '%' || ? || '%'
+			OR EXISTS (
+				SELECT 1 FROM businesses b
+				WHERE b.id = licenses.business_id AND b.search_vector @@ websearch_to_tsquery('english', ?)
+			)
+		)`, term, escapeLike(term), term)
+	}
+	if filter.ExpiringBefore != nil {
+		before, err := parseDate("expiringBefore", *filter.ExpiringBefore)
+		if err != nil {
+			return where, err
+		}
+		where.add("expiration_date < ?::date", before)
+	}
+	if err := where.addDateRange("expiration_date", "expirationDate", filter.ExpirationDate); err != nil {
+		return where, err
+	}
+	if err := where.addDateRange("issued_date", "issuedDate", filter.IssuedDate); err != nil {
+		return where, err
+	}
+	return where, nil
+}
+
+// enumArray converts a list of GraphQL enum values into a Postgres text
+// array for use with = ANY(?::text[])
+func enumArray[E ~string](values []E) pq.StringArray {
+	strs := make(pq.StringArray, len(values))
+	for i, v := range values {
+		strs[i] = string(v)
+	}
+	return strs
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicenseFilterWhere(t *testing.T) {
+	businessID, state, search, before := "b-1", " ca ", "C11-00", "2025-12-31T00:00:00Z"
+	from := "2025-01-01"
+
+	where, err := licenseFilterWhere(&model.LicenseFilter{
+		BusinessID:     &businessID,
+		Statuses:       []model.LicenseStatus{model.LicenseStatusActive, model.LicenseStatusRenewalInProgress},
+		State:          &state,
+		Search:         &search,
+		ExpiringBefore: &before,
+		IssuedDate:     &model.DateRange{From: &from},
+	})
+	require.NoError(t, err)
+
+	sql := where.sql()
+	assert.Contains(t, sql, "app_can_access_business(business_id)")
+	assert.Contains(t, sql, "business_id = $1")
+	assert.Contains(t, sql, "status = ANY($2::text[])")
+	assert.Contains(t, sql, "upper(loc.state) = upper($3)")
+	assert.Contains(t, sql, "websearch_to_tsquery('simple', $4)")
+	assert.Contains(t, sql, "expiration_date < $7::date")
+	assert.Contains(t, sql, "issued_date >= $8::date")
+
+	require.Len(t, where.args, 8)
+	assert.Equal(t, pq.StringArray{"ACTIVE", "RENEWAL_IN_PROGRESS"}, where.args[1])
+	assert.Equal(t, "ca", where.args[2])
+	assert.Equal(t, "2025-12-31", where.args[6])
+}
+
+func TestLicenseFilterWhere_RejectsBadDates(t *testing.T) {
+	bad := "next week"
+	_, err := licenseFilterWhere(&model.LicenseFilter{ExpirationDate: &model.DateRange{To: &bad}})
+	assert.Error(t, err)
+}
+
+func TestBusinessFilterWhere(t *testing.T) {
+	where, err := businessFilterWhere(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "WHERE app_can_access_business(id)", where.sql())
+
+	search, to := "green leaf", "2025-06-30"
+	where, err = businessFilterWhere(&model.BusinessFilter{
+		Types:     []model.BusinessType{model.BusinessTypeRetailer},
+		Search:    &search,
+		CreatedAt: &model.DateRange{To: &to},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, where.sql(), "type = ANY($1::text[])")
+	assert.Contains(t, where.sql(), "search_vector @@ websearch_to_tsquery('english', $2)")
+	assert.Contains(t, where.sql(), "created_at < $3::date + 1")
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_a\\b`, escapeLike(`100%_a\b`))
+}
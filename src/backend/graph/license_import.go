@@ -0,0 +1,312 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"budsafe/backend/numbering"
+	"budsafe/backend/tabular"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// errImportRolledBack ends the transaction of an import that is a dry run
+// or has errors, so that nothing is kept
+var errImportRolledBack = errors.New("license import rolled back")
+
+// importColumns are the header keys (see tabular.Key) read for each
+// CreateLicenseInput field, in order of preference
+var importColumns = map[string][]string{
+	"businessId":     {"business", "businessname", "businessid"},
+	"licenseNumber":  {"licensenumber", "number", "license"},
+	"licenseType":    {"licensetype", "type"},
+	"jurisdictionId": {"jurisdiction", "jurisdictionname", "jurisdictionid", "state"},
+	"locationId":     {"location", "locationaddress", "address", "locationid"},
+	"issuedDate":     {"issueddate", "issued", "issuedate"},
+	"expirationDate": {"expirationdate", "expiration", "expires", "expirydate"},
+	"status":         {"status"},
+	"statusReason":   {"statusreason", "reason"},
+	"notes":          {"notes", "note"},
+}
+
+// importDateLayouts are the date formats accepted besides spreadsheet
+// serial numbers; XLSX cells with a date format read as the last two
+var importDateLayouts = []string{time.DateOnly, "1/2/2006", "1/2/06", "01-02-06"}
+
+// namedRow is a business or jurisdiction an import may name
+type namedRow struct {
+	ID   string `db:"id"`
+	Name string `db:"name"`
+}
+
+type importLocation struct {
+	ID         string `db:"id"`
+	BusinessID string `db:"business_id"`
+	Address    string `db:"address"`
+	City       string `db:"city"`
+}
+
+// importNames resolves the names in an import file to ids, among the rows
+// the viewer can see
+type importNames struct {
+	businesses    []namedRow
+	jurisdictions []namedRow
+	locations     []importLocation
+	// formats are the license number formats by jurisdiction id
+	formats map[string]numbering.Formats
+}
+
+func loadImportNames(ctx context.Context, tx *sqlx.Tx) (*importNames, error) {
+	var n importNames
+	if err := tx.SelectContext(ctx, &n.businesses, "SELECT id, name FROM businesses ORDER BY name"); err != nil {
+		return nil, dbError(err, "query businesses")
+	}
+	var jurisdictions []struct {
+		namedRow
+		Formats []byte `db:"license_number_formats"`
+	}
+	if err := tx.SelectContext(ctx, &jurisdictions, "SELECT id, name, license_number_formats FROM jurisdictions ORDER BY name"); err != nil {
+		return nil, dbError(err, "query jurisdictions")
+	}
+	n.formats = make(map[string]numbering.Formats, len(jurisdictions))
+	for _, j := range jurisdictions {
+		formats, err := numbering.Parse(j.Formats)
+		if err != nil {
+			return nil, fmt.Errorf("jurisdiction %s: %w", j.ID, err)
+		}
+		n.jurisdictions = append(n.jurisdictions, j.namedRow)
+		n.formats[j.ID] = formats
+	}
+	if err := tx.SelectContext(ctx, &n.locations, "SELECT id, business_id, address, city FROM locations"); err != nil {
+		return nil, dbError(err, "query locations")
+	}
+	return &n, nil
+}
+
+// byName finds the row with the id or, ignoring case, the name
+func byName(rows []namedRow, entity, value string) (string, error) {
+	var matches []string
+	for _, row := range rows {
+		if row.ID == value {
+			return row.ID, nil
+		}
+		if strings.EqualFold(row.Name, value) {
+			matches = append(matches, row.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no %s named %q", entity, value)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("%d %ss are named %q; use the id", len(matches), entity, value)
+}
+
+func (n *importNames) business(value string) (string, error) {
+	if value == "" {
+		if len(n.businesses) == 1 {
+			return n.businesses[0].ID, nil
+		}
+		return "", errors.New("business is required")
+	}
+	return byName(n.businesses, "business", value)
+}
+
+func (n *importNames) jurisdiction(value string) (string, error) {
+	if value == "" {
+		return "", errors.New("jurisdiction is required")
+	}
+	return byName(n.jurisdictions, "jurisdiction", value)
+}
+
+// location finds the business's location with the id or the address,
+// optionally followed by ", city"
+func (n *importNames) location(businessID, value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	want := strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	for _, loc := range n.locations {
+		if loc.BusinessID != businessID {
+			continue
+		}
+		address := strings.Join(strings.Fields(strings.ToLower(loc.Address)), " ")
+		if loc.ID == value || want == address || want == address+", "+strings.ToLower(strings.TrimSpace(loc.City)) {
+			return &loc.ID, nil
+		}
+	}
+	return nil, fmt.Errorf("the business has no location at %q", value)
+}
+
+// parseImportDate reads a date in one of importDateLayouts or as a
+// spreadsheet serial number, returning it as YYYY-MM-DD
+func parseImportDate(value string) (string, error) {
+	if value == "" {
+		return "", errors.New("date is required")
+	}
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(time.DateOnly), nil
+		}
+	}
+	// Days since 1899-12-30, the epoch spreadsheets count from
+	if days, err := strconv.Atoi(value); err == nil && days > 0 && days < 2958466 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days).Format(time.DateOnly), nil
+	}
+	return "", fmt.Errorf("%q is not a date; use YYYY-MM-DD", value)
+}
+
+// importEnum reads an enum value written in any case, with spaces or
+// hyphens for underscores
+func importEnum(value string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToUpper(strings.TrimSpace(value)))
+}
+
+// importLicenseInput maps a record to the input of createLicense, with an
+// error for each field that cannot be read and for each check of
+// createLicense that needs nothing but the input, so that every problem
+// with the row is reported at once
+func importLicenseInput(rec tabular.Record, names *importNames) (model.CreateLicenseInput, []*model.LicenseImportError) {
+	var input model.CreateLicenseInput
+	var errs []*model.LicenseImportError
+	fail := func(field string, err error) {
+		errs = append(errs, &model.LicenseImportError{Row: rec.Row, Field: &field, Message: err.Error()})
+	}
+	failCheck := func(err error) {
+		if rowErr, ok := rowError(rec.Row, err); ok {
+			errs = append(errs, rowErr)
+		}
+	}
+	get := func(field string) string { return rec.Get(importColumns[field]...) }
+
+	var err error
+	if input.BusinessID, err = names.business(get("businessId")); err != nil {
+		fail("businessId", err)
+	}
+	input.LicenseNumber = get("licenseNumber")
+	input.LicenseType = model.LicenseType(importEnum(get("licenseType")))
+	if !input.LicenseType.IsValid() {
+		fail("licenseType", fmt.Errorf("unknown license type %q", get("licenseType")))
+	}
+	if input.JurisdictionID, err = names.jurisdiction(get("jurisdictionId")); err != nil {
+		fail("jurisdictionId", err)
+	}
+	if input.BusinessID != "" {
+		if input.LocationID, err = names.location(input.BusinessID, get("locationId")); err != nil {
+			fail("locationId", err)
+		}
+	}
+	if input.IssuedDate, err = parseImportDate(get("issuedDate")); err != nil {
+		fail("issuedDate", err)
+	}
+	if input.ExpirationDate, err = parseImportDate(get("expirationDate")); err != nil {
+		fail("expirationDate", err)
+	}
+	input.Status = model.LicenseStatusActive
+	if status := get("status"); status != "" {
+		input.Status = model.LicenseStatus(importEnum(status))
+		if !input.Status.IsValid() {
+			fail("status", fmt.Errorf("unknown license status %q", status))
+		}
+	}
+	if reason := get("statusReason"); reason != "" {
+		input.StatusReason = &reason
+	}
+	if notes := get("notes"); notes != "" {
+		input.Notes = &notes
+	}
+
+	numberErr := requireNonBlank("licenseNumber", input.LicenseNumber)
+	if numberErr == nil && input.LicenseType.IsValid() && input.JurisdictionID != "" {
+		numberErr = checkNumberFormat(names.formats[input.JurisdictionID], input.LicenseType, strings.TrimSpace(input.LicenseNumber))
+	}
+	failCheck(numberErr)
+	if input.IssuedDate != "" && input.ExpirationDate != "" {
+		failCheck(requireDateOrder(input.IssuedDate, input.ExpirationDate))
+	}
+	if input.Status.IsValid() {
+		_, err := checkStatusReason(input.Status, input.StatusReason)
+		failCheck(err)
+	}
+	return input, errs
+}
+
+// rowError turns a typed error of createLicense into an error of the row;
+// other errors end the import
+func rowError(row int, err error) (*model.LicenseImportError, bool) {
+	var gqlErr *gqlerror.Error
+	if !errors.As(err, &gqlErr) {
+		return nil, false
+	}
+	rowErr := &model.LicenseImportError{Row: row, Message: gqlErr.Message}
+	if field, ok := gqlErr.Extensions["field"].(string); ok && field != "" {
+		rowErr.Field = &field
+	}
+	return rowErr, true
+}
+
+// importLicenses creates a license for each record in one transaction,
+// each row under a savepoint so that every row is checked. The transaction
+// is only committed if every row succeeded and this is not a dry run.
+func (r *Resolver) importLicenses(ctx context.Context, records []tabular.Record, dryRun bool) (*model.LicenseImportResult, error) {
+	result := &model.LicenseImportResult{
+		DryRun:   dryRun,
+		RowCount: len(records),
+		Licenses: []*model.License{},
+		Errors:   []*model.LicenseImportError{},
+	}
+	var licenses []*model.License
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		names, err := loadImportNames(ctx, tx)
+		if err != nil {
+			return err
+		}
+		for _, rec := range records {
+			input, errs := importLicenseInput(rec, names)
+			if len(errs) > 0 {
+				result.Errors = append(result.Errors, errs...)
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx, "SAVEPOINT import_row"); err != nil {
+				return dbError(err, "import licenses")
+			}
+			license, err := r.createLicense(ctx, tx, input)
+			if err != nil {
+				rowErr, ok := rowError(rec.Row, dbError(err, "create license"))
+				if !ok {
+					return dbError(err, "import licenses")
+				}
+				result.Errors = append(result.Errors, rowErr)
+				if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row"); err != nil {
+					return dbError(err, "import licenses")
+				}
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
+				return dbError(err, "import licenses")
+			}
+			licenses = append(licenses, license)
+		}
+
+		if dryRun || len(result.Errors) > 0 {
+			return errImportRolledBack
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRolledBack) {
+		return nil, err
+	}
+
+	result.ValidCount = len(licenses)
+	if err == nil {
+		result.Licenses = licenses
+	}
+	return result, nil
+}
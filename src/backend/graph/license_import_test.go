@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"budsafe/backend/graph/model"
+	"budsafe/backend/numbering"
+	"budsafe/backend/tabular"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportDate(t *testing.T) {
+	for value, want := range map[string]string{
+		"2026-01-31": "2026-01-31",
+		"1/31/2026":  "2026-01-31",
+		"01/31/2026": "2026-01-31",
+		"1/31/26":    "2026-01-31",
+		"01-31-26":   "2026-01-31",
+		"46053":      "2026-01-31",
+	} {
+		got, err := parseImportDate(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"", "31/01/2026", "Jan 31", "-5"} {
+		_, err := parseImportDate(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestImportEnum(t *testing.T) {
+	assert.Equal(t, "RENEWAL_IN_PROGRESS", importEnum(" renewal in-progress "))
+	assert.Equal(t, "RETAIL", importEnum("Retail"))
+}
+
+func testImportNames() *importNames {
+	return &importNames{
+		businesses: []namedRow{{ID: "b1", Name: "Green Leaf"}, {ID: "b2", Name: "Blue Sky"}, {ID: "b3", Name: "blue sky"}},
+		jurisdictions: []namedRow{
+			{ID: "j1", Name: "California"},
+			{ID: "j2", Name: "Oregon"},
+		},
+		locations: []importLocation{
+			{ID: "l1", BusinessID: "b1", Address: "1 Main St", City: "Sacramento"},
+			{ID: "l2", BusinessID: "b2", Address: "1 Main St", City: "Portland"},
+		},
+		formats: map[string]numbering.Formats{"j1": {{Prefix: "C10-"}}},
+	}
+}
+
+func TestImportNames(t *testing.T) {
+	names := testImportNames()
+
+	id, err := names.business("GREEN LEAF")
+	require.NoError(t, err)
+	assert.Equal(t, "b1", id)
+	id, err = names.business("b3")
+	require.NoError(t, err)
+	assert.Equal(t, "b3", id)
+	_, err = names.business("Blue Sky")
+	assert.ErrorContains(t, err, "use the id")
+	_, err = names.business("")
+	assert.ErrorContains(t, err, "required")
+
+	// A member of one business may leave it out
+	single := &importNames{businesses: names.businesses[:1]}
+	id, err = single.business("")
+	require.NoError(t, err)
+	assert.Equal(t, "b1", id)
+
+	loc, err := names.location("b1", "1  main st, SACRAMENTO")
+	require.NoError(t, err)
+	assert.Equal(t, "l1", *loc)
+	loc, err = names.location("b2", "1 Main St")
+	require.NoError(t, err)
+	assert.Equal(t, "l2", *loc)
+	_, err = names.location("b1", "l2")
+	assert.Error(t, err, "another business's location")
+	loc, err = names.location("b1", "")
+	require.NoError(t, err)
+	assert.Nil(t, loc)
+}
+
+func TestImportLicenseInput(t *testing.T) {
+	rec := tabular.Record{Row: 2, Fields: map[string]string{
+		"business":       "Green Leaf",
+		"licensenumber":  "C10-0000001-LIC",
+		"type":           "retail",
+		"jurisdiction":   "california",
+		"address":        "1 Main St",
+		"issued":         "1/1/2025",
+		"expirationdate": "2026-01-01",
+		"notes":          "Imported",
+	}}
+	input, errs := importLicenseInput(rec, testImportNames())
+	require.Empty(t, errs)
+	notes, location := "Imported", "l1"
+	assert.Equal(t, model.CreateLicenseInput{
+		BusinessID:     "b1",
+		LocationID:     &location,
+		LicenseNumber:  "C10-0000001-LIC",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: "j1",
+		IssuedDate:     "2025-01-01",
+		ExpirationDate: "2026-01-01",
+		Status:         model.LicenseStatusActive,
+		Notes:          &notes,
+	}, input)
+
+	rec = tabular.Record{Row: 7, Fields: map[string]string{
+		"business":     "Nobody",
+		"type":         "bakery",
+		"jurisdiction": "Atlantis",
+		"issueddate":   "yesterday",
+		"status":       "dormant",
+	}}
+	_, errs = importLicenseInput(rec, testImportNames())
+	fields := []string{}
+	for _, e := range errs {
+		assert.Equal(t, 7, e.Row)
+		fields = append(fields, *e.Field)
+	}
+	assert.Equal(t, []string{"businessId", "licenseType", "jurisdictionId", "issuedDate", "expirationDate", "status", "licenseNumber"}, fields)
+
+	// The checks of createLicense that need only the row run as well
+	rec = tabular.Record{Row: 8, Fields: map[string]string{
+		"business":       "Green Leaf",
+		"licensenumber":  "C11-0000001-LIC",
+		"type":           "retail",
+		"jurisdiction":   "california",
+		"issued":         "2026-01-01",
+		"expirationdate": "2025-01-01",
+		"status":         "revoked",
+	}}
+	_, errs = importLicenseInput(rec, testImportNames())
+	fields = []string{}
+	for _, e := range errs {
+		fields = append(fields, *e.Field)
+	}
+	assert.Equal(t, []string{"licenseNumber", "expirationDate", "statusReason"}, fields)
+	assert.Contains(t, errs[0].Message, `"C10-"`)
+}
+
+func TestRowError(t *testing.T) {
+	rowErr, ok := rowError(3, validationError("licenseNumber", "bad number"))
+	require.True(t, ok)
+	assert.Equal(t, 3, rowErr.Row)
+	assert.Equal(t, "licenseNumber", *rowErr.Field)
+	assert.Equal(t, "bad number", rowErr.Message)
+
+	rowErr, ok = rowError(3, conflictError("duplicate"))
+	require.True(t, ok)
+	assert.Nil(t, rowErr.Field)
+
+	_, ok = rowError(3, errors.New("connection reset"))
+	assert.False(t, ok)
+}
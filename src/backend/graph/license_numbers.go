@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"budsafe/backend/numbering"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// jurisdictionFormats loads the license number formats of a jurisdiction
+func jurisdictionFormats(ctx context.Context, q sqlx.QueryerContext, jurisdictionID string) (numbering.Formats, error) {
+	var raw []byte
+	err := sqlx.GetContext(ctx, q, &raw, "SELECT license_number_formats FROM jurisdictions WHERE id = $1", jurisdictionID)
+	if err != nil {
+		return nil, getOrNotFound(err, "jurisdiction", jurisdictionID)
+	}
+	formats, err := numbering.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("jurisdiction %s: %w", jurisdictionID, err)
+	}
+	return formats, nil
+}
+
+// checkLicenseNumber fails validation of licenseNumber unless number has the
+// format the jurisdiction issues for the license type
+func checkLicenseNumber(ctx context.Context, tx *sqlx.Tx, jurisdictionID string, licenseType model.LicenseType, number string) error {
+	formats, err := jurisdictionFormats(ctx, tx, jurisdictionID)
+	if err != nil {
+		return err
+	}
+	return checkNumberFormat(formats, licenseType, number)
+}
+
+// checkNumberFormat fails validation of licenseNumber unless number has the
+// format of formats for the license type
+func checkNumberFormat(formats numbering.Formats, licenseType model.LicenseType, number string) error {
+	if problems := formats.Validate(string(licenseType), number); len(problems) > 0 {
+		return validationError("licenseNumber", "%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// parseFormatInputs checks license number formats given to a mutation and
+// returns them as stored
+func parseFormatInputs(inputs []*model.LicenseNumberFormatInput) ([]byte, error) {
+	raw, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, err
+	}
+	formats, err := numbering.Parse(raw)
+	if err != nil {
+		return nil, validationError("formats", "%s", err.Error())
+	}
+	if formats == nil {
+		formats = numbering.Formats{}
+	}
+	return json.Marshal(formats)
+}
+
+// licenseNumberFormat converts a parsed format to its GraphQL model
+func licenseNumberFormat(f *numbering.Format) (*model.LicenseNumberFormat, error) {
+	if f == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	var format model.LicenseNumberFormat
+	if err := json.Unmarshal(raw, &format); err != nil {
+		return nil, err
+	}
+	return &format, nil
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// licenseTransitions lists the statuses each license status may move to.
+// REVOKED is terminal; a lapsed license is renewed via RENEWAL_IN_PROGRESS.
+var licenseTransitions = map[model.LicenseStatus][]model.LicenseStatus{
+	model.LicenseStatusPending: {
+		model.LicenseStatusActive,
+		model.LicenseStatusRevoked,
+	},
+	model.LicenseStatusActive: {
+		model.LicenseStatusRenewalInProgress,
+		model.LicenseStatusExpired,
+		model.LicenseStatusSuspended,
+		model.LicenseStatusRevoked,
+	},
+	model.LicenseStatusRenewalInProgress: {
+		model.LicenseStatusActive,
+		model.LicenseStatusExpired,
+		model.LicenseStatusSuspended,
+		model.LicenseStatusRevoked,
+	},
+	model.LicenseStatusExpired: {
+		model.LicenseStatusRenewalInProgress,
+		model.LicenseStatusRevoked,
+	},
+	model.LicenseStatusSuspended: {
+		model.LicenseStatusActive,
+		model.LicenseStatusExpired,
+		model.LicenseStatusRevoked,
+	},
+	model.LicenseStatusRevoked: {},
+}
+
+// statusRequiresReason reports whether moving to status must be explained
+func statusRequiresReason(status model.LicenseStatus) bool {
+	return status == model.LicenseStatusSuspended || status == model.LicenseStatusRevoked
+}
+
+// checkStatusReason validates the reason given for entering status and
+// returns it trimmed, or nil when none was given
+func checkStatusReason(status model.LicenseStatus, reason *string) (*string, error) {
+	if reason != nil {
+		trimmed := strings.TrimSpace(*reason)
+		if trimmed != "" {
+			return &trimmed, nil
+		}
+	}
+	if statusRequiresReason(status) {
+		return nil, validationError("statusReason", "a reason is required to set status %s", status)
+	}
+	return nil, nil
+}
+
+// checkLicenseTransition fails unless a license may move from one status to
+// another. Setting the current status again is not a transition and passes.
+func checkLicenseTransition(from, to model.LicenseStatus) error {
+	if from == to {
+		return nil
+	}
+	if !slices.Contains(licenseTransitions[from], to) {
+		return validationError("status", "a license cannot move from %s to %s", from, to)
+	}
+	return nil
+}
+
+// recordStatusChange appends a license status history entry attributed to
+// the viewer. from is nil for a newly created license.
+func (r *Resolver) recordStatusChange(ctx context.Context, tx *sqlx.Tx, licenseID string, from *model.LicenseStatus, to model.LicenseStatus, reason *string) error {
+	viewer, err := r.viewer(ctx)
+	if err != nil {
+		return err
+	}
+	var changedBy *string
+	if viewer != nil {
+		changedBy = &viewer.ID
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO license_status_history (license_id, from_status, to_status, reason, changed_by_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`, licenseID, from, to, reason, changedBy)
+	return err
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestCheckLicenseTransition(t *testing.T) {
+	assert.NoError(t, checkLicenseTransition(model.LicenseStatusPending, model.LicenseStatusActive))
+	assert.NoError(t, checkLicenseTransition(model.LicenseStatusActive, model.LicenseStatusRenewalInProgress))
+	assert.NoError(t, checkLicenseTransition(model.LicenseStatusSuspended, model.LicenseStatusActive))
+	assert.NoError(t, checkLicenseTransition(model.LicenseStatusRevoked, model.LicenseStatusRevoked), "same status is not a transition")
+
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, checkLicenseTransition(model.LicenseStatusRevoked, model.LicenseStatusActive), &gqlErr)
+	assert.Equal(t, ErrCodeValidation, gqlErr.Extensions["code"])
+	assert.Error(t, checkLicenseTransition(model.LicenseStatusExpired, model.LicenseStatusActive))
+}
+
+func TestLicenseTransitions_CoverEveryStatus(t *testing.T) {
+	for _, status := range model.AllLicenseStatus {
+		targets, ok := licenseTransitions[status]
+		require.True(t, ok, "no transitions defined for %s", status)
+		for _, target := range targets {
+			assert.True(t, target.IsValid())
+		}
+	}
+}
+
+func TestCheckStatusReason(t *testing.T) {
+	_, err := checkStatusReason(model.LicenseStatusSuspended, nil)
+	assert.Error(t, err)
+
+	blank := "   "
+	_, err = checkStatusReason(model.LicenseStatusRevoked, &blank)
+	assert.Error(t, err)
+
+	reason := "  Failed inspection "
+	got, err := checkStatusReason(model.LicenseStatusSuspended, &reason)
+	require.NoError(t, err)
+	assert.Equal(t, "Failed inspection", *got)
+
+	got, err = checkStatusReason(model.LicenseStatusActive, nil)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
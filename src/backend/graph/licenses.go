@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// createLicense validates input and creates the license in tx, with its
+// first status change and renewal. It serves createLicense and
+// importLicenses.
+func (r *Resolver) createLicense(ctx context.Context, tx *sqlx.Tx, input model.CreateLicenseInput) (*model.License, error) {
+	if err := requireNonBlank("licenseNumber", input.LicenseNumber); err != nil {
+		return nil, err
+	}
+	issuedDate, err := parseDate("issuedDate", input.IssuedDate)
+	if err != nil {
+		return nil, err
+	}
+	expirationDate, err := parseDate("expirationDate", input.ExpirationDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireDateOrder(issuedDate, expirationDate); err != nil {
+		return nil, err
+	}
+	statusReason, err := checkStatusReason(input.Status, input.StatusReason)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.requireBusinessRole(ctx, tx, input.BusinessID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+		return nil, err
+	}
+	if err := requireExists(ctx, tx, "jurisdictions", "jurisdiction", input.JurisdictionID); err != nil {
+		return nil, err
+	}
+	if err := checkLicenseNumber(ctx, tx, input.JurisdictionID, input.LicenseType, strings.TrimSpace(input.LicenseNumber)); err != nil {
+		return nil, err
+	}
+	if input.LocationID != nil {
+		if err := requireLocationOfBusiness(ctx, tx, *input.LocationID, input.BusinessID); err != nil {
+			return nil, err
+		}
+	}
+
+	var license model.License
+	err = tx.GetContext(ctx, &license, `
+		INSERT INTO licenses (business_id, location_id, license_number, type, jurisdiction_id,
+		                      issued_date, expiration_date, status, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+licenseColumns,
+		input.BusinessID, input.LocationID, strings.TrimSpace(input.LicenseNumber), input.LicenseType,
+		input.JurisdictionID, issuedDate, expirationDate, input.Status, input.Notes)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.recordStatusChange(ctx, tx, license.ID, nil, license.Status, statusReason); err != nil {
+		return nil, err
+	}
+	if err := r.syncRenewal(ctx, tx, &license, nil); err != nil {
+		return nil, err
+	}
+	return &license, nil
+}
@@ -0,0 +1,21 @@
+package model
+
+// One entry in the audit log
+type AuditEvent struct {
+	ID         string         `json:"id"`
+	OccurredAt string         `json:"occurredAt" db:"occurred_at"`
+	ActorID    *string        `json:"actorId,omitempty" db:"actor_id"`
+	ActorEmail *string        `json:"actorEmail,omitempty" db:"actor_email"`
+	EntityType string         `json:"entityType" db:"entity_type"`
+	EntityID   string         `json:"entityId" db:"entity_id"`
+	BusinessID *string        `json:"businessId,omitempty" db:"business_id"`
+	Operation  AuditOperation `json:"operation"`
+	Mutation   *string        `json:"mutation,omitempty"`
+	// Before and After are the JSON objects as stored
+	Before       *string `json:"-"`
+	After        *string `json:"-"`
+	RequestID    *string `json:"requestId,omitempty" db:"request_id"`
+	IP           *string `json:"ip,omitempty"`
+	PreviousHash *string `json:"previousHash,omitempty" db:"prev_hash"`
+	Hash         *string `json:"hash,omitempty"`
+}
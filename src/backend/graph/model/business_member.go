@@ -0,0 +1,10 @@
+package model
+
+// A user's membership in a business, with a per-business role
+type BusinessMember struct {
+	BusinessID string   `json:"businessId" db:"business_id"`
+	UserID     string   `json:"userId" db:"user_id"`
+	Role       UserRole `json:"role"`
+	CreatedAt  string   `json:"createdAt" db:"created_at"`
+	UpdatedAt  *string  `json:"updatedAt,omitempty" db:"updated_at"`
+}
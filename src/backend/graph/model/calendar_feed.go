@@ -0,0 +1,13 @@
+package model
+
+// Calendar app subscription of a user to a business's compliance deadlines
+type CalendarFeed struct {
+	ID         string  `json:"id"`
+	UserID     string  `json:"userId" db:"user_id"`
+	BusinessID string  `json:"businessId" db:"business_id"`
+	Name       string  `json:"name"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty" db:"last_used_at"`
+	RevokedAt  *string `json:"revokedAt,omitempty" db:"revoked_at"`
+	CreatedAt  string  `json:"createdAt" db:"created_at"`
+	UpdatedAt  *string `json:"updatedAt,omitempty" db:"updated_at"`
+}
@@ -12,6 +12,9 @@ type ComplianceCheck struct {
 	UserID											*string 					`json:"userId" db:"checked_by_id"`
 	ComplianceCheckUser 				*User 						`json:"assignedTo,omitempty"`
 	Notes     									*string 					`json:"notes,omitempty"`
+	RegulationID								*string						`json:"regulationId,omitempty" db:"regulation_id"`
+	RuleClause									*string						`json:"ruleClause,omitempty" db:"rule_clause"`
+	ScheduleID									*string						`json:"scheduleId,omitempty" db:"schedule_id"`
 	CreatedAt 									string  					`json:"createdAt" db:"created_at"`
 	UpdatedAt 									*string 					`json:"updatedAt,omitempty" db:"updated_at"`
 }
\ No newline at end of file
@@ -0,0 +1,16 @@
+package model
+
+// Recurring compliance check on a license
+type ComplianceSchedule struct {
+	ID           string  `json:"id"`
+	LicenseID    string  `json:"licenseId" db:"license_id"`
+	Title        string  `json:"title"`
+	Notes        *string `json:"notes,omitempty"`
+	RRule        string  `json:"rrule" db:"rrule"`
+	StartsAt     string  `json:"startsAt" db:"starts_at"`
+	LeadDays     int     `json:"leadDays" db:"lead_days"`
+	AssignedToID *string `json:"assignedToId,omitempty" db:"assigned_to_id"`
+	Active       bool    `json:"active"`
+	CreatedAt    string  `json:"createdAt" db:"created_at"`
+	UpdatedAt    *string `json:"updatedAt,omitempty" db:"updated_at"`
+}
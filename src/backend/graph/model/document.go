@@ -0,0 +1,28 @@
+package model
+
+// Document attached to a license or renewal requirement, or kept for the
+// business as a whole
+type Document struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description *string           `json:"description,omitempty"`
+	FileURL     *string           `json:"fileUrl,omitempty" db:"file_url"`
+	FileType    string            `json:"fileType" db:"file_type"`
+	Category    *DocumentCategory `json:"category,omitempty" db:"category"`
+	// StorageKey locates an uploaded file in the storage backend; documents
+	// linking to an external file have a FileURL instead
+	StorageKey           *string `json:"-" db:"storage_key"`
+	FileSize             *int64  `json:"fileSize,omitempty" db:"file_size"`
+	Checksum             *string `json:"checksum,omitempty" db:"checksum_sha256"`
+	SeriesID             string  `json:"seriesId" db:"series_id"`
+	Version              int     `json:"version"`
+	ValidFrom            *string `json:"validFrom,omitempty" db:"valid_from"`
+	ValidUntil           *string `json:"validUntil,omitempty" db:"valid_until"`
+	SupersededByID       *string `json:"supersededById,omitempty" db:"superseded_by_id"`
+	UploadedByID         string  `json:"uploadedById" db:"uploaded_by_id"`
+	BusinessID           *string `json:"businessId,omitempty" db:"business_id"`
+	LicenseID            *string `json:"licenseId,omitempty" db:"license_id"`
+	RenewalRequirementID *string `json:"renewalRequirementId,omitempty" db:"renewal_requirement_id"`
+	CreatedAt            string  `json:"createdAt" db:"created_at"`
+	UpdatedAt            *string `json:"updatedAt,omitempty" db:"updated_at"`
+}
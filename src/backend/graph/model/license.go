@@ -25,9 +25,14 @@ type License struct {
 }
 
 type LicenseFilter struct {
-	BusinessID     *string        `json:"businessId,omitempty"`
-	JurisdictionID *string        `json:"jurisdictionId,omitempty"`
-	LicenseType    *LicenseType   `json:"licenseType,omitempty"`
-	Status         *LicenseStatus `json:"status,omitempty"`
-	ExpiringBefore *string        `json:"expiringBefore,omitempty"`
+	BusinessID     *string         `json:"businessId,omitempty"`
+	JurisdictionID *string         `json:"jurisdictionId,omitempty"`
+	LicenseType    *LicenseType    `json:"licenseType,omitempty"`
+	Status         *LicenseStatus  `json:"status,omitempty"`
+	Statuses       []LicenseStatus `json:"statuses,omitempty"`
+	State          *string         `json:"state,omitempty"`
+	Search         *string         `json:"search,omitempty"`
+	ExpiringBefore *string         `json:"expiringBefore,omitempty"`
+	ExpirationDate *DateRange      `json:"expirationDate,omitempty"`
+	IssuedDate     *DateRange      `json:"issuedDate,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,12 @@
+package model
+
+// One entry in a license's status history
+type LicenseStatusChange struct {
+	ID          string         `json:"id"`
+	LicenseID   string         `json:"licenseId" db:"license_id"`
+	FromStatus  *LicenseStatus `json:"fromStatus,omitempty" db:"from_status"`
+	ToStatus    LicenseStatus  `json:"toStatus" db:"to_status"`
+	Reason      *string        `json:"reason,omitempty"`
+	ChangedByID *string        `json:"changedById,omitempty" db:"changed_by_id"`
+	ChangedAt   string         `json:"changedAt" db:"changed_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+// Physical location of a business
+type Location struct {
+	ID         string  `json:"id"`
+	BusinessID string  `json:"businessId" db:"business_id"`
+	Address    string  `json:"address"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	ZipCode    string  `json:"zipCode" db:"zip_code"`
+	IsPrimary  bool    `json:"isPrimary" db:"is_primary"`
+	CreatedAt  string  `json:"createdAt" db:"created_at"`
+	UpdatedAt  *string `json:"updatedAt,omitempty" db:"updated_at"`
+}
@@ -7,12 +7,53 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
+type BusinessConnection struct {
+	Edges      []*BusinessEdge `json:"edges"`
+	PageInfo   *PageInfo       `json:"pageInfo"`
+	TotalCount int             `json:"totalCount"`
+}
+
+type BusinessEdge struct {
+	Cursor string    `json:"cursor"`
+	Node   *Business `json:"node"`
+}
+
 type BusinessFilter struct {
-	Type   *BusinessType `json:"type,omitempty"`
-	State  *string       `json:"state,omitempty"`
-	Search *string       `json:"search,omitempty"`
+	Type      *BusinessType  `json:"type,omitempty"`
+	Types     []BusinessType `json:"types,omitempty"`
+	State     *string        `json:"state,omitempty"`
+	Search    *string        `json:"search,omitempty"`
+	CreatedAt *DateRange     `json:"createdAt,omitempty"`
+}
+
+type BusinessOrder struct {
+	Field     BusinessOrderField `json:"field"`
+	Direction OrderDirection     `json:"direction"`
+}
+
+type CalendarFeedSubscription struct {
+	Feed *CalendarFeed `json:"feed"`
+	URL  string        `json:"url"`
+}
+
+type ComplianceCheckConnection struct {
+	Edges      []*ComplianceCheckEdge `json:"edges"`
+	PageInfo   *PageInfo              `json:"pageInfo"`
+	TotalCount int                    `json:"totalCount"`
+}
+
+type ComplianceCheckEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *ComplianceCheck `json:"node"`
+}
+
+type ComplianceCheckOrder struct {
+	Field     ComplianceCheckOrderField `json:"field"`
+	Direction OrderDirection            `json:"direction"`
 }
 
 type CreateBusinessInput struct {
@@ -30,13 +71,27 @@ type CreateComplianceCheckInput struct {
 	Notes        *string          `json:"notes,omitempty"`
 }
 
+type CreateComplianceScheduleInput struct {
+	LicenseID    string  `json:"licenseId"`
+	Title        string  `json:"title"`
+	Notes        *string `json:"notes,omitempty"`
+	Rrule        string  `json:"rrule"`
+	StartsAt     string  `json:"startsAt"`
+	LeadDays     int     `json:"leadDays"`
+	AssignedToID *string `json:"assignedToId,omitempty"`
+}
+
 type CreateDocumentInput struct {
-	Name                 string  `json:"name"`
-	Description          *string `json:"description,omitempty"`
-	FileURL              string  `json:"fileUrl"`
-	FileType             string  `json:"fileType"`
-	LicenseID            *string `json:"licenseId,omitempty"`
-	RenewalRequirementID *string `json:"renewalRequirementId,omitempty"`
+	Name                 string            `json:"name"`
+	Description          *string           `json:"description,omitempty"`
+	File                 *graphql.Upload   `json:"file,omitempty"`
+	FileURL              *string           `json:"fileUrl,omitempty"`
+	FileType             *string           `json:"fileType,omitempty"`
+	Category             *DocumentCategory `json:"category,omitempty"`
+	ValidFrom            *string           `json:"validFrom,omitempty"`
+	ValidUntil           *string           `json:"validUntil,omitempty"`
+	LicenseID            *string           `json:"licenseId,omitempty"`
+	RenewalRequirementID *string           `json:"renewalRequirementId,omitempty"`
 }
 
 type CreateLicenseInput struct {
@@ -48,6 +103,7 @@ type CreateLicenseInput struct {
 	IssuedDate     string        `json:"issuedDate"`
 	ExpirationDate string        `json:"expirationDate"`
 	Status         LicenseStatus `json:"status"`
+	StatusReason   *string       `json:"statusReason,omitempty"`
 	Notes          *string       `json:"notes,omitempty"`
 }
 
@@ -67,6 +123,20 @@ type CreateRenewalRequirementInput struct {
 	IsCompleted bool    `json:"isCompleted"`
 }
 
+type CreateRenewalTemplateInput struct {
+	JurisdictionID string                      `json:"jurisdictionId"`
+	LicenseType    *LicenseType                `json:"licenseType,omitempty"`
+	Name           string                      `json:"name"`
+	Items          []*RenewalTemplateItemInput `json:"items"`
+}
+
+type CreateWebhookSubscriptionInput struct {
+	BusinessID  string             `json:"businessId"`
+	URL         string             `json:"url"`
+	EventTypes  []WebhookEventType `json:"eventTypes"`
+	Description *string            `json:"description,omitempty"`
+}
+
 type DashboardSummary struct {
 	BusinessID          string          `json:"businessId"`
 	ActiveLicenses      int             `json:"activeLicenses"`
@@ -76,40 +146,157 @@ type DashboardSummary struct {
 	RecentNotifications []*Notification `json:"recentNotifications"`
 }
 
-// Document attached to a license or renewal requirement
-type Document struct {
-	ID                   string              `json:"id"`
-	Name                 string              `json:"name"`
-	Description          *string             `json:"description,omitempty"`
-	FileURL              string              `json:"fileUrl"`
-	FileType             string              `json:"fileType"`
-	UploadedBy           *User               `json:"uploadedBy"`
-	LicenseID            *string             `json:"licenseId,omitempty"`
-	License              *License            `json:"license,omitempty"`
-	RenewalRequirementID *string             `json:"renewalRequirementId,omitempty"`
-	RenewalRequirement   *RenewalRequirement `json:"renewalRequirement,omitempty"`
-	CreatedAt            string              `json:"createdAt"`
-	UpdatedAt            *string             `json:"updatedAt,omitempty"`
-}
-
-// Physical location of a business
-type Location struct {
-	ID         string     `json:"id"`
-	BusinessID string     `json:"businessId"`
-	Business   *Business  `json:"business"`
-	Address    string     `json:"address"`
-	City       string     `json:"city"`
-	State      string     `json:"state"`
-	ZipCode    string     `json:"zipCode"`
-	IsPrimary  bool       `json:"isPrimary"`
-	Licenses   []*License `json:"licenses,omitempty"`
-	CreatedAt  string     `json:"createdAt"`
-	UpdatedAt  *string    `json:"updatedAt,omitempty"`
+type DateRange struct {
+	From *string `json:"from,omitempty"`
+	To   *string `json:"to,omitempty"`
+}
+
+type DocumentVersionInput struct {
+	Name        *string           `json:"name,omitempty"`
+	Description *string           `json:"description,omitempty"`
+	Category    *DocumentCategory `json:"category,omitempty"`
+	File        *graphql.Upload   `json:"file,omitempty"`
+	FileURL     *string           `json:"fileUrl,omitempty"`
+	FileType    *string           `json:"fileType,omitempty"`
+	ValidFrom   *string           `json:"validFrom,omitempty"`
+	ValidUntil  *string           `json:"validUntil,omitempty"`
+}
+
+type JurisdictionConnection struct {
+	Edges      []*JurisdictionEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int                 `json:"totalCount"`
+}
+
+type JurisdictionEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *Jurisdiction `json:"node"`
+}
+
+type JurisdictionOrder struct {
+	Field     JurisdictionOrderField `json:"field"`
+	Direction OrderDirection         `json:"direction"`
+}
+
+type LicenseConnection struct {
+	Edges      []*LicenseEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int            `json:"totalCount"`
+}
+
+type LicenseEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *License `json:"node"`
+}
+
+type LicenseImportError struct {
+	Row     int     `json:"row"`
+	Field   *string `json:"field,omitempty"`
+	Message string  `json:"message"`
+}
+
+// The outcome of importLicenses. The file's header row names the columns,
+// in any case and spacing: business (name or id; may be left out by members
+// of one business), licenseNumber, licenseType, jurisdiction (name or id),
+// location (address or id; optional), issuedDate, expirationDate (YYYY-MM-DD,
+// M/D/YYYY or spreadsheet dates), status (default ACTIVE), statusReason and
+// notes.
+type LicenseImportResult struct {
+	DryRun     bool                  `json:"dryRun"`
+	RowCount   int                   `json:"rowCount"`
+	ValidCount int                   `json:"validCount"`
+	Licenses   []*License            `json:"licenses"`
+	Errors     []*LicenseImportError `json:"errors"`
+}
+
+// Format of the license numbers a jurisdiction issues for some license types.
+// A format without license types applies to every type without a format of
+// its own. A valid number starts with the prefix, matches the pattern and ends
+// in a valid check digit, each if given.
+type LicenseNumberFormat struct {
+	LicenseTypes []LicenseType        `json:"licenseTypes,omitempty"`
+	Prefix       *string              `json:"prefix,omitempty"`
+	Pattern      *string              `json:"pattern,omitempty"`
+	CheckDigit   *CheckDigitAlgorithm `json:"checkDigit,omitempty"`
+	Example      *string              `json:"example,omitempty"`
+	Description  *string              `json:"description,omitempty"`
+}
+
+type LicenseNumberFormatInput struct {
+	LicenseTypes []LicenseType        `json:"licenseTypes,omitempty"`
+	Prefix       *string              `json:"prefix,omitempty"`
+	Pattern      *string              `json:"pattern,omitempty"`
+	CheckDigit   *CheckDigitAlgorithm `json:"checkDigit,omitempty"`
+	Example      *string              `json:"example,omitempty"`
+	Description  *string              `json:"description,omitempty"`
+}
+
+type LicenseNumberValidation struct {
+	Valid         bool                 `json:"valid"`
+	LicenseNumber string               `json:"licenseNumber"`
+	Errors        []string             `json:"errors"`
+	Format        *LicenseNumberFormat `json:"format,omitempty"`
+}
+
+type LicenseOrder struct {
+	Field     LicenseOrderField `json:"field"`
+	Direction OrderDirection    `json:"direction"`
 }
 
 type Mutation struct {
 }
 
+type NotificationChannels struct {
+	Type     NotificationType      `json:"type"`
+	Channels []NotificationChannel `json:"channels"`
+}
+
+type NotificationChannelsInput struct {
+	Type     NotificationType      `json:"type"`
+	Channels []NotificationChannel `json:"channels"`
+}
+
+type NotificationConnection struct {
+	Edges      []*NotificationEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int                 `json:"totalCount"`
+}
+
+type NotificationEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *Notification `json:"node"`
+}
+
+// How and when a user is notified. Users who have not saved preferences get
+// every notification in the app and by email, immediately, in UTC.
+type NotificationPreferences struct {
+	UserID          string                  `json:"userId"`
+	Timezone        string                  `json:"timezone"`
+	QuietHoursStart *string                 `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd   *string                 `json:"quietHoursEnd,omitempty"`
+	Digest          DigestMode              `json:"digest"`
+	DigestHour      int                     `json:"digestHour"`
+	DigestDay       Weekday                 `json:"digestDay"`
+	Channels        []*NotificationChannels `json:"channels"`
+}
+
+type NotificationPreferencesInput struct {
+	Timezone        string                       `json:"timezone"`
+	QuietHoursStart *string                      `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd   *string                      `json:"quietHoursEnd,omitempty"`
+	Digest          DigestMode                   `json:"digest"`
+	DigestHour      int                          `json:"digestHour"`
+	DigestDay       Weekday                      `json:"digestDay"`
+	Channels        []*NotificationChannelsInput `json:"channels"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
 type Query struct {
 }
 
@@ -128,17 +315,22 @@ type Regulation struct {
 	UpdatedAt        *string            `json:"updatedAt,omitempty"`
 }
 
-// Requirements for license renewal
-type RenewalRequirement struct {
-	ID          string      `json:"id"`
-	LicenseID   string      `json:"licenseId"`
-	License     *License    `json:"license"`
-	Description string      `json:"description"`
-	Deadline    *string     `json:"deadline,omitempty"`
-	IsCompleted bool        `json:"isCompleted"`
-	Documents   []*Document `json:"documents,omitempty"`
-	CreatedAt   string      `json:"createdAt"`
-	UpdatedAt   *string     `json:"updatedAt,omitempty"`
+type RenewalPermitInput struct {
+	LicenseNumber  *string `json:"licenseNumber,omitempty"`
+	IssuedDate     string  `json:"issuedDate"`
+	ExpirationDate string  `json:"expirationDate"`
+}
+
+type RenewalTemplateItemInput struct {
+	Description      string            `json:"description"`
+	DueDaysBefore    *int              `json:"dueDaysBefore,omitempty"`
+	DocumentCategory *DocumentCategory `json:"documentCategory,omitempty"`
+}
+
+type ScheduledComplianceCheck struct {
+	Schedule        *ComplianceSchedule `json:"schedule"`
+	DueDate         string              `json:"dueDate"`
+	ComplianceCheck *ComplianceCheck    `json:"complianceCheck,omitempty"`
 }
 
 type Subscription struct {
@@ -158,6 +350,16 @@ type UpdateComplianceCheckInput struct {
 	Notes        *string           `json:"notes,omitempty"`
 }
 
+type UpdateComplianceScheduleInput struct {
+	Title        *string `json:"title,omitempty"`
+	Notes        *string `json:"notes,omitempty"`
+	Rrule        *string `json:"rrule,omitempty"`
+	StartsAt     *string `json:"startsAt,omitempty"`
+	LeadDays     *int    `json:"leadDays,omitempty"`
+	AssignedToID *string `json:"assignedToId,omitempty"`
+	Active       *bool   `json:"active,omitempty"`
+}
+
 type UpdateLicenseInput struct {
 	LocationID     *string        `json:"locationId,omitempty"`
 	LicenseNumber  *string        `json:"licenseNumber,omitempty"`
@@ -166,6 +368,7 @@ type UpdateLicenseInput struct {
 	IssuedDate     *string        `json:"issuedDate,omitempty"`
 	ExpirationDate *string        `json:"expirationDate,omitempty"`
 	Status         *LicenseStatus `json:"status,omitempty"`
+	StatusReason   *string        `json:"statusReason,omitempty"`
 	Notes          *string        `json:"notes,omitempty"`
 }
 
@@ -190,58 +393,89 @@ type UpdateUserInput struct {
 	Role      *UserRole `json:"role,omitempty"`
 }
 
-type BusinessType string
+type UpdateWebhookSubscriptionInput struct {
+	URL         *string            `json:"url,omitempty"`
+	EventTypes  []WebhookEventType `json:"eventTypes,omitempty"`
+	Description *string            `json:"description,omitempty"`
+	Active      *bool              `json:"active,omitempty"`
+}
+
+type UserConnection struct {
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *User  `json:"node"`
+}
+
+type UserOrder struct {
+	Field     UserOrderField `json:"field"`
+	Direction OrderDirection `json:"direction"`
+}
+
+type WebhookDeliveryConnection struct {
+	Edges      []*WebhookDeliveryEdge `json:"edges"`
+	PageInfo   *PageInfo              `json:"pageInfo"`
+	TotalCount int                    `json:"totalCount"`
+}
+
+type WebhookDeliveryEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *WebhookDelivery `json:"node"`
+}
+
+type WebhookSubscriptionSecret struct {
+	Subscription *WebhookSubscription `json:"subscription"`
+	Secret       string               `json:"secret"`
+}
+
+type AuditOperation string
 
 const (
-	BusinessTypeCultivator  BusinessType = "CULTIVATOR"
-	BusinessTypeProcessor   BusinessType = "PROCESSOR"
-	BusinessTypeDistributor BusinessType = "DISTRIBUTOR"
-	BusinessTypeRetailer    BusinessType = "RETAILER"
-	BusinessTypeTestingLab  BusinessType = "TESTING_LAB"
-	BusinessTypeDelivery    BusinessType = "DELIVERY"
-	BusinessTypeIntegrated  BusinessType = "INTEGRATED"
+	AuditOperationCreate AuditOperation = "CREATE"
+	AuditOperationUpdate AuditOperation = "UPDATE"
+	AuditOperationDelete AuditOperation = "DELETE"
 )
 
-var AllBusinessType = []BusinessType{
-	BusinessTypeCultivator,
-	BusinessTypeProcessor,
-	BusinessTypeDistributor,
-	BusinessTypeRetailer,
-	BusinessTypeTestingLab,
-	BusinessTypeDelivery,
-	BusinessTypeIntegrated,
+var AllAuditOperation = []AuditOperation{
+	AuditOperationCreate,
+	AuditOperationUpdate,
+	AuditOperationDelete,
 }
 
-func (e BusinessType) IsValid() bool {
+func (e AuditOperation) IsValid() bool {
 	switch e {
-	case BusinessTypeCultivator, BusinessTypeProcessor, BusinessTypeDistributor, BusinessTypeRetailer, BusinessTypeTestingLab, BusinessTypeDelivery, BusinessTypeIntegrated:
+	case AuditOperationCreate, AuditOperationUpdate, AuditOperationDelete:
 		return true
 	}
 	return false
 }
 
-func (e BusinessType) String() string {
+func (e AuditOperation) String() string {
 	return string(e)
 }
 
-func (e *BusinessType) UnmarshalGQL(v any) error {
+func (e *AuditOperation) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = BusinessType(str)
+	*e = AuditOperation(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid BusinessType", str)
+		return fmt.Errorf("%s is not a valid AuditOperation", str)
 	}
 	return nil
 }
 
-func (e BusinessType) MarshalGQL(w io.Writer) {
+func (e AuditOperation) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *BusinessType) UnmarshalJSON(b []byte) error {
+func (e *AuditOperation) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -249,60 +483,54 @@ func (e *BusinessType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e BusinessType) MarshalJSON() ([]byte, error) {
+func (e AuditOperation) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type ComplianceStatus string
+type BusinessOrderField string
 
 const (
-	ComplianceStatusCompliant      ComplianceStatus = "COMPLIANT"
-	ComplianceStatusNonCompliant   ComplianceStatus = "NON_COMPLIANT"
-	ComplianceStatusPendingReview  ComplianceStatus = "PENDING_REVIEW"
-	ComplianceStatusNeedsAttention ComplianceStatus = "NEEDS_ATTENTION"
-	ComplianceStatusNotApplicable  ComplianceStatus = "NOT_APPLICABLE"
+	BusinessOrderFieldCreatedAt BusinessOrderField = "CREATED_AT"
+	BusinessOrderFieldName      BusinessOrderField = "NAME"
 )
 
-var AllComplianceStatus = []ComplianceStatus{
-	ComplianceStatusCompliant,
-	ComplianceStatusNonCompliant,
-	ComplianceStatusPendingReview,
-	ComplianceStatusNeedsAttention,
-	ComplianceStatusNotApplicable,
+var AllBusinessOrderField = []BusinessOrderField{
+	BusinessOrderFieldCreatedAt,
+	BusinessOrderFieldName,
 }
 
-func (e ComplianceStatus) IsValid() bool {
+func (e BusinessOrderField) IsValid() bool {
 	switch e {
-	case ComplianceStatusCompliant, ComplianceStatusNonCompliant, ComplianceStatusPendingReview, ComplianceStatusNeedsAttention, ComplianceStatusNotApplicable:
+	case BusinessOrderFieldCreatedAt, BusinessOrderFieldName:
 		return true
 	}
 	return false
 }
 
-func (e ComplianceStatus) String() string {
+func (e BusinessOrderField) String() string {
 	return string(e)
 }
 
-func (e *ComplianceStatus) UnmarshalGQL(v any) error {
+func (e *BusinessOrderField) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = ComplianceStatus(str)
+	*e = BusinessOrderField(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid ComplianceStatus", str)
+		return fmt.Errorf("%s is not a valid BusinessOrderField", str)
 	}
 	return nil
 }
 
-func (e ComplianceStatus) MarshalGQL(w io.Writer) {
+func (e BusinessOrderField) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *ComplianceStatus) UnmarshalJSON(b []byte) error {
+func (e *BusinessOrderField) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -310,56 +538,64 @@ func (e *ComplianceStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e ComplianceStatus) MarshalJSON() ([]byte, error) {
+func (e BusinessOrderField) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type JurisdictionType string
+type BusinessType string
 
 const (
-	JurisdictionTypeUsState          JurisdictionType = "US_STATE"
-	JurisdictionTypeCanadianProvince JurisdictionType = "CANADIAN_PROVINCE"
-	JurisdictionTypeCountry          JurisdictionType = "COUNTRY"
+	BusinessTypeCultivator  BusinessType = "CULTIVATOR"
+	BusinessTypeProcessor   BusinessType = "PROCESSOR"
+	BusinessTypeDistributor BusinessType = "DISTRIBUTOR"
+	BusinessTypeRetailer    BusinessType = "RETAILER"
+	BusinessTypeTestingLab  BusinessType = "TESTING_LAB"
+	BusinessTypeDelivery    BusinessType = "DELIVERY"
+	BusinessTypeIntegrated  BusinessType = "INTEGRATED"
 )
 
-var AllJurisdictionType = []JurisdictionType{
-	JurisdictionTypeUsState,
-	JurisdictionTypeCanadianProvince,
-	JurisdictionTypeCountry,
+var AllBusinessType = []BusinessType{
+	BusinessTypeCultivator,
+	BusinessTypeProcessor,
+	BusinessTypeDistributor,
+	BusinessTypeRetailer,
+	BusinessTypeTestingLab,
+	BusinessTypeDelivery,
+	BusinessTypeIntegrated,
 }
 
-func (e JurisdictionType) IsValid() bool {
+func (e BusinessType) IsValid() bool {
 	switch e {
-	case JurisdictionTypeUsState, JurisdictionTypeCanadianProvince, JurisdictionTypeCountry:
+	case BusinessTypeCultivator, BusinessTypeProcessor, BusinessTypeDistributor, BusinessTypeRetailer, BusinessTypeTestingLab, BusinessTypeDelivery, BusinessTypeIntegrated:
 		return true
 	}
 	return false
 }
 
-func (e JurisdictionType) String() string {
+func (e BusinessType) String() string {
 	return string(e)
 }
 
-func (e *JurisdictionType) UnmarshalGQL(v any) error {
+func (e *BusinessType) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = JurisdictionType(str)
+	*e = BusinessType(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid JurisdictionType", str)
+		return fmt.Errorf("%s is not a valid BusinessType", str)
 	}
 	return nil
 }
 
-func (e JurisdictionType) MarshalGQL(w io.Writer) {
+func (e BusinessType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *JurisdictionType) UnmarshalJSON(b []byte) error {
+func (e *BusinessType) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -367,62 +603,54 @@ func (e *JurisdictionType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e JurisdictionType) MarshalJSON() ([]byte, error) {
+func (e BusinessType) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type LicenseStatus string
+type CheckDigitAlgorithm string
 
 const (
-	LicenseStatusActive            LicenseStatus = "ACTIVE"
-	LicenseStatusPending           LicenseStatus = "PENDING"
-	LicenseStatusExpired           LicenseStatus = "EXPIRED"
-	LicenseStatusRevoked           LicenseStatus = "REVOKED"
-	LicenseStatusSuspended         LicenseStatus = "SUSPENDED"
-	LicenseStatusRenewalInProgress LicenseStatus = "RENEWAL_IN_PROGRESS"
+	CheckDigitAlgorithmLuhn  CheckDigitAlgorithm = "LUHN"
+	CheckDigitAlgorithmMod11 CheckDigitAlgorithm = "MOD11"
 )
 
-var AllLicenseStatus = []LicenseStatus{
-	LicenseStatusActive,
-	LicenseStatusPending,
-	LicenseStatusExpired,
-	LicenseStatusRevoked,
-	LicenseStatusSuspended,
-	LicenseStatusRenewalInProgress,
+var AllCheckDigitAlgorithm = []CheckDigitAlgorithm{
+	CheckDigitAlgorithmLuhn,
+	CheckDigitAlgorithmMod11,
 }
 
-func (e LicenseStatus) IsValid() bool {
+func (e CheckDigitAlgorithm) IsValid() bool {
 	switch e {
-	case LicenseStatusActive, LicenseStatusPending, LicenseStatusExpired, LicenseStatusRevoked, LicenseStatusSuspended, LicenseStatusRenewalInProgress:
+	case CheckDigitAlgorithmLuhn, CheckDigitAlgorithmMod11:
 		return true
 	}
 	return false
 }
 
-func (e LicenseStatus) String() string {
+func (e CheckDigitAlgorithm) String() string {
 	return string(e)
 }
 
-func (e *LicenseStatus) UnmarshalGQL(v any) error {
+func (e *CheckDigitAlgorithm) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = LicenseStatus(str)
+	*e = CheckDigitAlgorithm(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid LicenseStatus", str)
+		return fmt.Errorf("%s is not a valid CheckDigitAlgorithm", str)
 	}
 	return nil
 }
 
-func (e LicenseStatus) MarshalGQL(w io.Writer) {
+func (e CheckDigitAlgorithm) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *LicenseStatus) UnmarshalJSON(b []byte) error {
+func (e *CheckDigitAlgorithm) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -430,70 +658,56 @@ func (e *LicenseStatus) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e LicenseStatus) MarshalJSON() ([]byte, error) {
+func (e CheckDigitAlgorithm) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type LicenseType string
+type ComplianceCheckOrderField string
 
 const (
-	LicenseTypeCultivation    LicenseType = "CULTIVATION"
-	LicenseTypeManufacturing  LicenseType = "MANUFACTURING"
-	LicenseTypeDistribution   LicenseType = "DISTRIBUTION"
-	LicenseTypeRetail         LicenseType = "RETAIL"
-	LicenseTypeDelivery       LicenseType = "DELIVERY"
-	LicenseTypeTesting        LicenseType = "TESTING"
-	LicenseTypeMicrobusiness  LicenseType = "MICROBUSINESS"
-	LicenseTypeResearch       LicenseType = "RESEARCH"
-	LicenseTypeTransportation LicenseType = "TRANSPORTATION"
-	LicenseTypeNursery        LicenseType = "NURSERY"
+	ComplianceCheckOrderFieldCreatedAt ComplianceCheckOrderField = "CREATED_AT"
+	ComplianceCheckOrderFieldDueDate   ComplianceCheckOrderField = "DUE_DATE"
+	ComplianceCheckOrderFieldStatus    ComplianceCheckOrderField = "STATUS"
 )
 
-var AllLicenseType = []LicenseType{
-	LicenseTypeCultivation,
-	LicenseTypeManufacturing,
-	LicenseTypeDistribution,
-	LicenseTypeRetail,
-	LicenseTypeDelivery,
-	LicenseTypeTesting,
-	LicenseTypeMicrobusiness,
-	LicenseTypeResearch,
-	LicenseTypeTransportation,
-	LicenseTypeNursery,
+var AllComplianceCheckOrderField = []ComplianceCheckOrderField{
+	ComplianceCheckOrderFieldCreatedAt,
+	ComplianceCheckOrderFieldDueDate,
+	ComplianceCheckOrderFieldStatus,
 }
 
-func (e LicenseType) IsValid() bool {
+func (e ComplianceCheckOrderField) IsValid() bool {
 	switch e {
-	case LicenseTypeCultivation, LicenseTypeManufacturing, LicenseTypeDistribution, LicenseTypeRetail, LicenseTypeDelivery, LicenseTypeTesting, LicenseTypeMicrobusiness, LicenseTypeResearch, LicenseTypeTransportation, LicenseTypeNursery:
+	case ComplianceCheckOrderFieldCreatedAt, ComplianceCheckOrderFieldDueDate, ComplianceCheckOrderFieldStatus:
 		return true
 	}
 	return false
 }
 
-func (e LicenseType) String() string {
+func (e ComplianceCheckOrderField) String() string {
 	return string(e)
 }
 
-func (e *LicenseType) UnmarshalGQL(v any) error {
+func (e *ComplianceCheckOrderField) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = LicenseType(str)
+	*e = ComplianceCheckOrderField(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid LicenseType", str)
+		return fmt.Errorf("%s is not a valid ComplianceCheckOrderField", str)
 	}
 	return nil
 }
 
-func (e LicenseType) MarshalGQL(w io.Writer) {
+func (e ComplianceCheckOrderField) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *LicenseType) UnmarshalJSON(b []byte) error {
+func (e *ComplianceCheckOrderField) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -501,60 +715,900 @@ func (e *LicenseType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e LicenseType) MarshalJSON() ([]byte, error) {
+func (e ComplianceCheckOrderField) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type NotificationType string
+type ComplianceStatus string
 
 const (
-	NotificationTypeLicenseExpiring  NotificationType = "LICENSE_EXPIRING"
-	NotificationTypeRenewalDue       NotificationType = "RENEWAL_DUE"
-	NotificationTypeComplianceIssue  NotificationType = "COMPLIANCE_ISSUE"
-	NotificationTypeDocumentRequired NotificationType = "DOCUMENT_REQUIRED"
-	NotificationTypeRegulationUpdate NotificationType = "REGULATION_UPDATE"
+	ComplianceStatusCompliant      ComplianceStatus = "COMPLIANT"
+	ComplianceStatusNonCompliant   ComplianceStatus = "NON_COMPLIANT"
+	ComplianceStatusPendingReview  ComplianceStatus = "PENDING_REVIEW"
+	ComplianceStatusNeedsAttention ComplianceStatus = "NEEDS_ATTENTION"
+	ComplianceStatusNotApplicable  ComplianceStatus = "NOT_APPLICABLE"
 )
 
-var AllNotificationType = []NotificationType{
-	NotificationTypeLicenseExpiring,
-	NotificationTypeRenewalDue,
-	NotificationTypeComplianceIssue,
-	NotificationTypeDocumentRequired,
-	NotificationTypeRegulationUpdate,
+var AllComplianceStatus = []ComplianceStatus{
+	ComplianceStatusCompliant,
+	ComplianceStatusNonCompliant,
+	ComplianceStatusPendingReview,
+	ComplianceStatusNeedsAttention,
+	ComplianceStatusNotApplicable,
 }
 
-func (e NotificationType) IsValid() bool {
+func (e ComplianceStatus) IsValid() bool {
 	switch e {
-	case NotificationTypeLicenseExpiring, NotificationTypeRenewalDue, NotificationTypeComplianceIssue, NotificationTypeDocumentRequired, NotificationTypeRegulationUpdate:
+	case ComplianceStatusCompliant, ComplianceStatusNonCompliant, ComplianceStatusPendingReview, ComplianceStatusNeedsAttention, ComplianceStatusNotApplicable:
 		return true
 	}
 	return false
 }
 
-func (e NotificationType) String() string {
+func (e ComplianceStatus) String() string {
 	return string(e)
 }
 
-func (e *NotificationType) UnmarshalGQL(v any) error {
+func (e *ComplianceStatus) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = NotificationType(str)
+	*e = ComplianceStatus(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid NotificationType", str)
+		return fmt.Errorf("%s is not a valid ComplianceStatus", str)
+	}
+	return nil
+}
+
+func (e ComplianceStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ComplianceStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ComplianceStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DeliveryChannel string
+
+const (
+	DeliveryChannelEmail DeliveryChannel = "EMAIL"
+)
+
+var AllDeliveryChannel = []DeliveryChannel{
+	DeliveryChannelEmail,
+}
+
+func (e DeliveryChannel) IsValid() bool {
+	switch e {
+	case DeliveryChannelEmail:
+		return true
+	}
+	return false
+}
+
+func (e DeliveryChannel) String() string {
+	return string(e)
+}
+
+func (e *DeliveryChannel) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeliveryChannel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeliveryChannel", str)
+	}
+	return nil
+}
+
+func (e DeliveryChannel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DeliveryChannel) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DeliveryChannel) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending  DeliveryStatus = "PENDING"
+	DeliveryStatusRetrying DeliveryStatus = "RETRYING"
+	DeliveryStatusSent     DeliveryStatus = "SENT"
+	DeliveryStatusBounced  DeliveryStatus = "BOUNCED"
+	DeliveryStatusFailed   DeliveryStatus = "FAILED"
+)
+
+var AllDeliveryStatus = []DeliveryStatus{
+	DeliveryStatusPending,
+	DeliveryStatusRetrying,
+	DeliveryStatusSent,
+	DeliveryStatusBounced,
+	DeliveryStatusFailed,
+}
+
+func (e DeliveryStatus) IsValid() bool {
+	switch e {
+	case DeliveryStatusPending, DeliveryStatusRetrying, DeliveryStatusSent, DeliveryStatusBounced, DeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e DeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *DeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e DeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DigestMode string
+
+const (
+	DigestModeImmediate DigestMode = "IMMEDIATE"
+	DigestModeDaily     DigestMode = "DAILY"
+	DigestModeWeekly    DigestMode = "WEEKLY"
+)
+
+var AllDigestMode = []DigestMode{
+	DigestModeImmediate,
+	DigestModeDaily,
+	DigestModeWeekly,
+}
+
+func (e DigestMode) IsValid() bool {
+	switch e {
+	case DigestModeImmediate, DigestModeDaily, DigestModeWeekly:
+		return true
+	}
+	return false
+}
+
+func (e DigestMode) String() string {
+	return string(e)
+}
+
+func (e *DigestMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DigestMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DigestMode", str)
+	}
+	return nil
+}
+
+func (e DigestMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DigestMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DigestMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type DocumentCategory string
+
+const (
+	DocumentCategorySecurity              DocumentCategory = "SECURITY"
+	DocumentCategoryInsurance             DocumentCategory = "INSURANCE"
+	DocumentCategoryCertificateOfAnalysis DocumentCategory = "CERTIFICATE_OF_ANALYSIS"
+	DocumentCategorySitePlan              DocumentCategory = "SITE_PLAN"
+	DocumentCategoryOperatingProcedures   DocumentCategory = "OPERATING_PROCEDURES"
+	DocumentCategoryTraining              DocumentCategory = "TRAINING"
+	DocumentCategoryPermit                DocumentCategory = "PERMIT"
+	DocumentCategoryOther                 DocumentCategory = "OTHER"
+)
+
+var AllDocumentCategory = []DocumentCategory{
+	DocumentCategorySecurity,
+	DocumentCategoryInsurance,
+	DocumentCategoryCertificateOfAnalysis,
+	DocumentCategorySitePlan,
+	DocumentCategoryOperatingProcedures,
+	DocumentCategoryTraining,
+	DocumentCategoryPermit,
+	DocumentCategoryOther,
+}
+
+func (e DocumentCategory) IsValid() bool {
+	switch e {
+	case DocumentCategorySecurity, DocumentCategoryInsurance, DocumentCategoryCertificateOfAnalysis, DocumentCategorySitePlan, DocumentCategoryOperatingProcedures, DocumentCategoryTraining, DocumentCategoryPermit, DocumentCategoryOther:
+		return true
+	}
+	return false
+}
+
+func (e DocumentCategory) String() string {
+	return string(e)
+}
+
+func (e *DocumentCategory) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DocumentCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DocumentCategory", str)
+	}
+	return nil
+}
+
+func (e DocumentCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DocumentCategory) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DocumentCategory) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type JurisdictionOrderField string
+
+const (
+	JurisdictionOrderFieldCreatedAt JurisdictionOrderField = "CREATED_AT"
+	JurisdictionOrderFieldName      JurisdictionOrderField = "NAME"
+)
+
+var AllJurisdictionOrderField = []JurisdictionOrderField{
+	JurisdictionOrderFieldCreatedAt,
+	JurisdictionOrderFieldName,
+}
+
+func (e JurisdictionOrderField) IsValid() bool {
+	switch e {
+	case JurisdictionOrderFieldCreatedAt, JurisdictionOrderFieldName:
+		return true
+	}
+	return false
+}
+
+func (e JurisdictionOrderField) String() string {
+	return string(e)
+}
+
+func (e *JurisdictionOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JurisdictionOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JurisdictionOrderField", str)
+	}
+	return nil
+}
+
+func (e JurisdictionOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *JurisdictionOrderField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e JurisdictionOrderField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type JurisdictionType string
+
+const (
+	JurisdictionTypeUsState          JurisdictionType = "US_STATE"
+	JurisdictionTypeCanadianProvince JurisdictionType = "CANADIAN_PROVINCE"
+	JurisdictionTypeCountry          JurisdictionType = "COUNTRY"
+)
+
+var AllJurisdictionType = []JurisdictionType{
+	JurisdictionTypeUsState,
+	JurisdictionTypeCanadianProvince,
+	JurisdictionTypeCountry,
+}
+
+func (e JurisdictionType) IsValid() bool {
+	switch e {
+	case JurisdictionTypeUsState, JurisdictionTypeCanadianProvince, JurisdictionTypeCountry:
+		return true
+	}
+	return false
+}
+
+func (e JurisdictionType) String() string {
+	return string(e)
+}
+
+func (e *JurisdictionType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = JurisdictionType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid JurisdictionType", str)
+	}
+	return nil
+}
+
+func (e JurisdictionType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *JurisdictionType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e JurisdictionType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type LicenseOrderField string
+
+const (
+	LicenseOrderFieldCreatedAt      LicenseOrderField = "CREATED_AT"
+	LicenseOrderFieldExpirationDate LicenseOrderField = "EXPIRATION_DATE"
+	LicenseOrderFieldLicenseNumber  LicenseOrderField = "LICENSE_NUMBER"
+	LicenseOrderFieldStatus         LicenseOrderField = "STATUS"
+)
+
+var AllLicenseOrderField = []LicenseOrderField{
+	LicenseOrderFieldCreatedAt,
+	LicenseOrderFieldExpirationDate,
+	LicenseOrderFieldLicenseNumber,
+	LicenseOrderFieldStatus,
+}
+
+func (e LicenseOrderField) IsValid() bool {
+	switch e {
+	case LicenseOrderFieldCreatedAt, LicenseOrderFieldExpirationDate, LicenseOrderFieldLicenseNumber, LicenseOrderFieldStatus:
+		return true
+	}
+	return false
+}
+
+func (e LicenseOrderField) String() string {
+	return string(e)
+}
+
+func (e *LicenseOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LicenseOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LicenseOrderField", str)
+	}
+	return nil
+}
+
+func (e LicenseOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LicenseOrderField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LicenseOrderField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type LicenseStatus string
+
+const (
+	LicenseStatusActive            LicenseStatus = "ACTIVE"
+	LicenseStatusPending           LicenseStatus = "PENDING"
+	LicenseStatusExpired           LicenseStatus = "EXPIRED"
+	LicenseStatusRevoked           LicenseStatus = "REVOKED"
+	LicenseStatusSuspended         LicenseStatus = "SUSPENDED"
+	LicenseStatusRenewalInProgress LicenseStatus = "RENEWAL_IN_PROGRESS"
+)
+
+var AllLicenseStatus = []LicenseStatus{
+	LicenseStatusActive,
+	LicenseStatusPending,
+	LicenseStatusExpired,
+	LicenseStatusRevoked,
+	LicenseStatusSuspended,
+	LicenseStatusRenewalInProgress,
+}
+
+func (e LicenseStatus) IsValid() bool {
+	switch e {
+	case LicenseStatusActive, LicenseStatusPending, LicenseStatusExpired, LicenseStatusRevoked, LicenseStatusSuspended, LicenseStatusRenewalInProgress:
+		return true
+	}
+	return false
+}
+
+func (e LicenseStatus) String() string {
+	return string(e)
+}
+
+func (e *LicenseStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LicenseStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LicenseStatus", str)
+	}
+	return nil
+}
+
+func (e LicenseStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LicenseStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LicenseStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type LicenseType string
+
+const (
+	LicenseTypeCultivation    LicenseType = "CULTIVATION"
+	LicenseTypeManufacturing  LicenseType = "MANUFACTURING"
+	LicenseTypeDistribution   LicenseType = "DISTRIBUTION"
+	LicenseTypeRetail         LicenseType = "RETAIL"
+	LicenseTypeDelivery       LicenseType = "DELIVERY"
+	LicenseTypeTesting        LicenseType = "TESTING"
+	LicenseTypeMicrobusiness  LicenseType = "MICROBUSINESS"
+	LicenseTypeResearch       LicenseType = "RESEARCH"
+	LicenseTypeTransportation LicenseType = "TRANSPORTATION"
+	LicenseTypeNursery        LicenseType = "NURSERY"
+)
+
+var AllLicenseType = []LicenseType{
+	LicenseTypeCultivation,
+	LicenseTypeManufacturing,
+	LicenseTypeDistribution,
+	LicenseTypeRetail,
+	LicenseTypeDelivery,
+	LicenseTypeTesting,
+	LicenseTypeMicrobusiness,
+	LicenseTypeResearch,
+	LicenseTypeTransportation,
+	LicenseTypeNursery,
+}
+
+func (e LicenseType) IsValid() bool {
+	switch e {
+	case LicenseTypeCultivation, LicenseTypeManufacturing, LicenseTypeDistribution, LicenseTypeRetail, LicenseTypeDelivery, LicenseTypeTesting, LicenseTypeMicrobusiness, LicenseTypeResearch, LicenseTypeTransportation, LicenseTypeNursery:
+		return true
+	}
+	return false
+}
+
+func (e LicenseType) String() string {
+	return string(e)
+}
+
+func (e *LicenseType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = LicenseType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid LicenseType", str)
+	}
+	return nil
+}
+
+func (e LicenseType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *LicenseType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e LicenseType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "IN_APP"
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+)
+
+var AllNotificationChannel = []NotificationChannel{
+	NotificationChannelInApp,
+	NotificationChannelEmail,
+}
+
+func (e NotificationChannel) IsValid() bool {
+	switch e {
+	case NotificationChannelInApp, NotificationChannelEmail:
+		return true
+	}
+	return false
+}
+
+func (e NotificationChannel) String() string {
+	return string(e)
+}
+
+func (e *NotificationChannel) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationChannel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationChannel", str)
+	}
+	return nil
+}
+
+func (e NotificationChannel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NotificationChannel) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NotificationChannel) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NotificationType string
+
+const (
+	NotificationTypeLicenseExpiring  NotificationType = "LICENSE_EXPIRING"
+	NotificationTypeRenewalDue       NotificationType = "RENEWAL_DUE"
+	NotificationTypeComplianceIssue  NotificationType = "COMPLIANCE_ISSUE"
+	NotificationTypeDocumentRequired NotificationType = "DOCUMENT_REQUIRED"
+	NotificationTypeRegulationUpdate NotificationType = "REGULATION_UPDATE"
+)
+
+var AllNotificationType = []NotificationType{
+	NotificationTypeLicenseExpiring,
+	NotificationTypeRenewalDue,
+	NotificationTypeComplianceIssue,
+	NotificationTypeDocumentRequired,
+	NotificationTypeRegulationUpdate,
+}
+
+func (e NotificationType) IsValid() bool {
+	switch e {
+	case NotificationTypeLicenseExpiring, NotificationTypeRenewalDue, NotificationTypeComplianceIssue, NotificationTypeDocumentRequired, NotificationTypeRegulationUpdate:
+		return true
+	}
+	return false
+}
+
+func (e NotificationType) String() string {
+	return string(e)
+}
+
+func (e *NotificationType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NotificationType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NotificationType", str)
+	}
+	return nil
+}
+
+func (e NotificationType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NotificationType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NotificationType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OrderDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OrderDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type RegulationCategory string
+
+const (
+	RegulationCategoryLicensing      RegulationCategory = "LICENSING"
+	RegulationCategoryTesting        RegulationCategory = "TESTING"
+	RegulationCategoryPackaging      RegulationCategory = "PACKAGING"
+	RegulationCategoryLabeling       RegulationCategory = "LABELING"
+	RegulationCategoryTracking       RegulationCategory = "TRACKING"
+	RegulationCategorySecurity       RegulationCategory = "SECURITY"
+	RegulationCategoryTransportation RegulationCategory = "TRANSPORTATION"
+	RegulationCategoryAdvertising    RegulationCategory = "ADVERTISING"
+	RegulationCategoryTaxation       RegulationCategory = "TAXATION"
+)
+
+var AllRegulationCategory = []RegulationCategory{
+	RegulationCategoryLicensing,
+	RegulationCategoryTesting,
+	RegulationCategoryPackaging,
+	RegulationCategoryLabeling,
+	RegulationCategoryTracking,
+	RegulationCategorySecurity,
+	RegulationCategoryTransportation,
+	RegulationCategoryAdvertising,
+	RegulationCategoryTaxation,
+}
+
+func (e RegulationCategory) IsValid() bool {
+	switch e {
+	case RegulationCategoryLicensing, RegulationCategoryTesting, RegulationCategoryPackaging, RegulationCategoryLabeling, RegulationCategoryTracking, RegulationCategorySecurity, RegulationCategoryTransportation, RegulationCategoryAdvertising, RegulationCategoryTaxation:
+		return true
+	}
+	return false
+}
+
+func (e RegulationCategory) String() string {
+	return string(e)
+}
+
+func (e *RegulationCategory) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RegulationCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RegulationCategory", str)
+	}
+	return nil
+}
+
+func (e RegulationCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RegulationCategory) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RegulationCategory) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type RenewalStatus string
+
+const (
+	RenewalStatusInProgress RenewalStatus = "IN_PROGRESS"
+	RenewalStatusCompleted  RenewalStatus = "COMPLETED"
+	RenewalStatusCancelled  RenewalStatus = "CANCELLED"
+)
+
+var AllRenewalStatus = []RenewalStatus{
+	RenewalStatusInProgress,
+	RenewalStatusCompleted,
+	RenewalStatusCancelled,
+}
+
+func (e RenewalStatus) IsValid() bool {
+	switch e {
+	case RenewalStatusInProgress, RenewalStatusCompleted, RenewalStatusCancelled:
+		return true
+	}
+	return false
+}
+
+func (e RenewalStatus) String() string {
+	return string(e)
+}
+
+func (e *RenewalStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RenewalStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RenewalStatus", str)
 	}
 	return nil
 }
 
-func (e NotificationType) MarshalGQL(w io.Writer) {
+func (e RenewalStatus) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *NotificationType) UnmarshalJSON(b []byte) error {
+func (e *RenewalStatus) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -562,68 +1616,56 @@ func (e *NotificationType) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e NotificationType) MarshalJSON() ([]byte, error) {
+func (e RenewalStatus) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
 
-type RegulationCategory string
+type ReportFormat string
 
 const (
-	RegulationCategoryLicensing      RegulationCategory = "LICENSING"
-	RegulationCategoryTesting        RegulationCategory = "TESTING"
-	RegulationCategoryPackaging      RegulationCategory = "PACKAGING"
-	RegulationCategoryLabeling       RegulationCategory = "LABELING"
-	RegulationCategoryTracking       RegulationCategory = "TRACKING"
-	RegulationCategorySecurity       RegulationCategory = "SECURITY"
-	RegulationCategoryTransportation RegulationCategory = "TRANSPORTATION"
-	RegulationCategoryAdvertising    RegulationCategory = "ADVERTISING"
-	RegulationCategoryTaxation       RegulationCategory = "TAXATION"
+	ReportFormatPDF  ReportFormat = "PDF"
+	ReportFormatCSV  ReportFormat = "CSV"
+	ReportFormatXlsx ReportFormat = "XLSX"
 )
 
-var AllRegulationCategory = []RegulationCategory{
-	RegulationCategoryLicensing,
-	RegulationCategoryTesting,
-	RegulationCategoryPackaging,
-	RegulationCategoryLabeling,
-	RegulationCategoryTracking,
-	RegulationCategorySecurity,
-	RegulationCategoryTransportation,
-	RegulationCategoryAdvertising,
-	RegulationCategoryTaxation,
+var AllReportFormat = []ReportFormat{
+	ReportFormatPDF,
+	ReportFormatCSV,
+	ReportFormatXlsx,
 }
 
-func (e RegulationCategory) IsValid() bool {
+func (e ReportFormat) IsValid() bool {
 	switch e {
-	case RegulationCategoryLicensing, RegulationCategoryTesting, RegulationCategoryPackaging, RegulationCategoryLabeling, RegulationCategoryTracking, RegulationCategorySecurity, RegulationCategoryTransportation, RegulationCategoryAdvertising, RegulationCategoryTaxation:
+	case ReportFormatPDF, ReportFormatCSV, ReportFormatXlsx:
 		return true
 	}
 	return false
 }
 
-func (e RegulationCategory) String() string {
+func (e ReportFormat) String() string {
 	return string(e)
 }
 
-func (e *RegulationCategory) UnmarshalGQL(v any) error {
+func (e *ReportFormat) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = RegulationCategory(str)
+	*e = ReportFormat(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid RegulationCategory", str)
+		return fmt.Errorf("%s is not a valid ReportFormat", str)
 	}
 	return nil
 }
 
-func (e RegulationCategory) MarshalGQL(w io.Writer) {
+func (e ReportFormat) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *RegulationCategory) UnmarshalJSON(b []byte) error {
+func (e *ReportFormat) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -631,7 +1673,123 @@ func (e *RegulationCategory) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e RegulationCategory) MarshalJSON() ([]byte, error) {
+func (e ReportFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReportJobStatus string
+
+const (
+	ReportJobStatusPending   ReportJobStatus = "PENDING"
+	ReportJobStatusRunning   ReportJobStatus = "RUNNING"
+	ReportJobStatusSucceeded ReportJobStatus = "SUCCEEDED"
+	ReportJobStatusFailed    ReportJobStatus = "FAILED"
+)
+
+var AllReportJobStatus = []ReportJobStatus{
+	ReportJobStatusPending,
+	ReportJobStatusRunning,
+	ReportJobStatusSucceeded,
+	ReportJobStatusFailed,
+}
+
+func (e ReportJobStatus) IsValid() bool {
+	switch e {
+	case ReportJobStatusPending, ReportJobStatusRunning, ReportJobStatusSucceeded, ReportJobStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ReportJobStatus) String() string {
+	return string(e)
+}
+
+func (e *ReportJobStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReportJobStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReportJobStatus", str)
+	}
+	return nil
+}
+
+func (e ReportJobStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReportJobStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReportJobStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type UserOrderField string
+
+const (
+	UserOrderFieldCreatedAt UserOrderField = "CREATED_AT"
+	UserOrderFieldEmail     UserOrderField = "EMAIL"
+	UserOrderFieldLastName  UserOrderField = "LAST_NAME"
+)
+
+var AllUserOrderField = []UserOrderField{
+	UserOrderFieldCreatedAt,
+	UserOrderFieldEmail,
+	UserOrderFieldLastName,
+}
+
+func (e UserOrderField) IsValid() bool {
+	switch e {
+	case UserOrderFieldCreatedAt, UserOrderFieldEmail, UserOrderFieldLastName:
+		return true
+	}
+	return false
+}
+
+func (e UserOrderField) String() string {
+	return string(e)
+}
+
+func (e *UserOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserOrderField", str)
+	}
+	return nil
+}
+
+func (e UserOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *UserOrderField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e UserOrderField) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -695,3 +1853,184 @@ func (e UserRole) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryStatusRetrying  WebhookDeliveryStatus = "RETRYING"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+var AllWebhookDeliveryStatus = []WebhookDeliveryStatus{
+	WebhookDeliveryStatusPending,
+	WebhookDeliveryStatusRetrying,
+	WebhookDeliveryStatusSucceeded,
+	WebhookDeliveryStatusFailed,
+}
+
+func (e WebhookDeliveryStatus) IsValid() bool {
+	switch e {
+	case WebhookDeliveryStatusPending, WebhookDeliveryStatusRetrying, WebhookDeliveryStatusSucceeded, WebhookDeliveryStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WebhookDeliveryStatus) String() string {
+	return string(e)
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookDeliveryStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookDeliveryStatus", str)
+	}
+	return nil
+}
+
+func (e WebhookDeliveryStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookDeliveryStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type WebhookEventType string
+
+const (
+	WebhookEventTypeLicenseStatusChanged  WebhookEventType = "LICENSE_STATUS_CHANGED"
+	WebhookEventTypeComplianceCheckFailed WebhookEventType = "COMPLIANCE_CHECK_FAILED"
+	WebhookEventTypeNotificationCreated   WebhookEventType = "NOTIFICATION_CREATED"
+)
+
+var AllWebhookEventType = []WebhookEventType{
+	WebhookEventTypeLicenseStatusChanged,
+	WebhookEventTypeComplianceCheckFailed,
+	WebhookEventTypeNotificationCreated,
+}
+
+func (e WebhookEventType) IsValid() bool {
+	switch e {
+	case WebhookEventTypeLicenseStatusChanged, WebhookEventTypeComplianceCheckFailed, WebhookEventTypeNotificationCreated:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEventType) String() string {
+	return string(e)
+}
+
+func (e *WebhookEventType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WebhookEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WebhookEventType", str)
+	}
+	return nil
+}
+
+func (e WebhookEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *WebhookEventType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e WebhookEventType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type Weekday string
+
+const (
+	WeekdayMonday    Weekday = "MONDAY"
+	WeekdayTuesday   Weekday = "TUESDAY"
+	WeekdayWednesday Weekday = "WEDNESDAY"
+	WeekdayThursday  Weekday = "THURSDAY"
+	WeekdayFriday    Weekday = "FRIDAY"
+	WeekdaySaturday  Weekday = "SATURDAY"
+	WeekdaySunday    Weekday = "SUNDAY"
+)
+
+var AllWeekday = []Weekday{
+	WeekdayMonday,
+	WeekdayTuesday,
+	WeekdayWednesday,
+	WeekdayThursday,
+	WeekdayFriday,
+	WeekdaySaturday,
+	WeekdaySunday,
+}
+
+func (e Weekday) IsValid() bool {
+	switch e {
+	case WeekdayMonday, WeekdayTuesday, WeekdayWednesday, WeekdayThursday, WeekdayFriday, WeekdaySaturday, WeekdaySunday:
+		return true
+	}
+	return false
+}
+
+func (e Weekday) String() string {
+	return string(e)
+}
+
+func (e *Weekday) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Weekday(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Weekday", str)
+	}
+	return nil
+}
+
+func (e Weekday) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *Weekday) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e Weekday) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,17 @@
+package model
+
+// Attempt to deliver a notification outside the app
+type NotificationDelivery struct {
+	ID             string          `json:"id"`
+	NotificationID string          `json:"notificationId" db:"notification_id"`
+	Channel        DeliveryChannel `json:"channel"`
+	Recipient      string          `json:"recipient"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	Digest         bool            `json:"digest"`
+	NextAttemptAt  *string         `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+	LastError      *string         `json:"lastError,omitempty" db:"last_error"`
+	SentAt         *string         `json:"sentAt,omitempty" db:"sent_at"`
+	CreatedAt      string          `json:"createdAt" db:"created_at"`
+	UpdatedAt      *string         `json:"updatedAt,omitempty" db:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package model
+
+// Renewal of a license and its checklist of renewal requirements
+type Renewal struct {
+	ID                     string        `json:"id"`
+	LicenseID              string        `json:"licenseId" db:"license_id"`
+	TemplateID             *string       `json:"templateId,omitempty" db:"template_id"`
+	Status                 RenewalStatus `json:"status"`
+	PreviousExpirationDate string        `json:"previousExpirationDate" db:"previous_expiration_date"`
+	NewLicenseNumber       *string       `json:"newLicenseNumber,omitempty" db:"new_license_number"`
+	NewIssuedDate          *string       `json:"newIssuedDate,omitempty" db:"new_issued_date"`
+	NewExpirationDate      *string       `json:"newExpirationDate,omitempty" db:"new_expiration_date"`
+	PermitRecordedAt       *string       `json:"permitRecordedAt,omitempty" db:"permit_recorded_at"`
+	CompletedAt            *string       `json:"completedAt,omitempty" db:"completed_at"`
+	CreatedAt              string        `json:"createdAt" db:"created_at"`
+	UpdatedAt              *string       `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// Checklist copied into the renewals of a jurisdiction's licenses
+type RenewalTemplate struct {
+	ID             string       `json:"id"`
+	JurisdictionID string       `json:"jurisdictionId" db:"jurisdiction_id"`
+	LicenseType    *LicenseType `json:"licenseType,omitempty" db:"license_type"`
+	Name           string       `json:"name"`
+	CreatedAt      string       `json:"createdAt" db:"created_at"`
+	UpdatedAt      *string      `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// One entry of a renewal template's checklist
+type RenewalTemplateItem struct {
+	ID               string            `json:"id"`
+	TemplateID       string            `json:"-" db:"template_id"`
+	Position         int               `json:"position"`
+	Description      string            `json:"description"`
+	DueDaysBefore    *int              `json:"dueDaysBefore,omitempty" db:"due_days_before"`
+	DocumentCategory *DocumentCategory `json:"documentCategory,omitempty" db:"document_category"`
+}
@@ -0,0 +1,15 @@
+package model
+
+// Requirements for license renewal
+type RenewalRequirement struct {
+	ID          string  `json:"id"`
+	LicenseID   string  `json:"licenseId" db:"license_id"`
+	Description string  `json:"description"`
+	Deadline    *string `json:"deadline,omitempty" db:"due_date"`
+	IsCompleted bool    `json:"isCompleted" db:"is_completed"`
+	// RenewalID is the renewal whose checklist the requirement is on
+	RenewalID        *string           `json:"renewalId,omitempty" db:"renewal_id"`
+	DocumentCategory *DocumentCategory `json:"documentCategory,omitempty" db:"document_category"`
+	CreatedAt        string            `json:"createdAt" db:"created_at"`
+	UpdatedAt        *string           `json:"updatedAt,omitempty" db:"updated_at"`
+}
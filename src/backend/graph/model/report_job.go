@@ -0,0 +1,15 @@
+package model
+
+// A requested compliance report
+type ReportJob struct {
+	ID            string          `json:"id"`
+	BusinessID    string          `json:"businessId" db:"business_id"`
+	LocationID    *string         `json:"locationId,omitempty" db:"location_id"`
+	Format        ReportFormat    `json:"format"`
+	Status        ReportJobStatus `json:"status"`
+	Error         *string         `json:"error,omitempty"`
+	DocumentID    *string         `json:"-" db:"document_id"`
+	RequestedByID string          `json:"-" db:"requested_by_id"`
+	CreatedAt     string          `json:"createdAt" db:"created_at"`
+	CompletedAt   *string         `json:"completedAt,omitempty" db:"completed_at"`
+}
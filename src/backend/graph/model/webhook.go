@@ -0,0 +1,37 @@
+package model
+
+import "github.com/lib/pq"
+
+// Subscription of a URL to a business's events. The secret is never loaded
+// into it.
+type WebhookSubscription struct {
+	ID         string `json:"id"`
+	BusinessID string `json:"businessId" db:"business_id"`
+	URL        string `json:"url"`
+	// EventNames are the event types as named in payloads, e.g.
+	// license.status_changed
+	EventNames  pq.StringArray `json:"-" db:"event_types"`
+	Description *string        `json:"description,omitempty"`
+	Active      bool           `json:"active"`
+	CreatedByID *string        `json:"createdById,omitempty" db:"created_by_id"`
+	CreatedAt   string         `json:"createdAt" db:"created_at"`
+	UpdatedAt   *string        `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// An event queued for a webhook subscription
+type WebhookDelivery struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscriptionId" db:"subscription_id"`
+	EventID        string `json:"eventId" db:"event_id"`
+	// EventName is the event type as named in payloads
+	EventName      string                `json:"-" db:"event_type"`
+	Payload        string                `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *string               `json:"nextAttemptAt,omitempty" db:"next_attempt_at"`
+	ResponseStatus *int                  `json:"responseStatus,omitempty" db:"response_status"`
+	LastError      *string               `json:"lastError,omitempty" db:"last_error"`
+	DeliveredAt    *string               `json:"deliveredAt,omitempty" db:"delivered_at"`
+	CreatedAt      string                `json:"createdAt" db:"created_at"`
+	UpdatedAt      *string               `json:"updatedAt,omitempty" db:"updated_at"`
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"budsafe/backend/notify"
+	"errors"
+	"strings"
+	"time"
+)
+
+// notificationPreferences converts a user's preferences, listing the
+// channels of every notification type
+func notificationPreferences(userID string, p *notify.Preferences) *model.NotificationPreferences {
+	prefs := &model.NotificationPreferences{
+		UserID:     userID,
+		Timezone:   p.Timezone,
+		Digest:     model.DigestMode(p.Digest),
+		DigestHour: p.DigestHour,
+		DigestDay:  model.Weekday(strings.ToUpper(p.DigestWeekday.String())),
+		Channels:   []*model.NotificationChannels{},
+	}
+	if p.QuietHoursStart != "" {
+		prefs.QuietHoursStart, prefs.QuietHoursEnd = &p.QuietHoursStart, &p.QuietHoursEnd
+	}
+	for _, typ := range model.AllNotificationType {
+		channels := []model.NotificationChannel{}
+		for _, channel := range model.AllNotificationChannel {
+			if p.Wants(string(typ), string(channel)) {
+				channels = append(channels, channel)
+			}
+		}
+		prefs.Channels = append(prefs.Channels, &model.NotificationChannels{Type: typ, Channels: channels})
+	}
+	return prefs
+}
+
+// parseNotificationPreferences checks the input of updateNotificationPreferences
+func parseNotificationPreferences(input model.NotificationPreferencesInput) (*notify.Preferences, error) {
+	p := &notify.Preferences{
+		Timezone:        strings.TrimSpace(input.Timezone),
+		QuietHoursStart: strings.TrimSpace(deref(input.QuietHoursStart)),
+		QuietHoursEnd:   strings.TrimSpace(deref(input.QuietHoursEnd)),
+		Digest:          string(input.Digest),
+		DigestHour:      input.DigestHour,
+		DigestWeekday:   -1,
+		Channels:        map[string][]string{},
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), string(input.DigestDay)) {
+			p.DigestWeekday = day
+		}
+	}
+	for i, c := range input.Channels {
+		if _, ok := p.Channels[string(c.Type)]; ok {
+			return nil, validationError("channels", "channels[%d]: %s is listed twice", i, c.Type)
+		}
+		channels := make([]string, len(c.Channels))
+		for j, channel := range c.Channels {
+			channels[j] = string(channel)
+		}
+		p.Channels[string(c.Type)] = channels
+	}
+
+	var fieldErr *notify.FieldError
+	if err := p.Check(); errors.As(err, &fieldErr) {
+		return nil, validationError(fieldErr.Field, "%s", fieldErr.Message)
+	} else if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
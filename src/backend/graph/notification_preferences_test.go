@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"testing"
+	"time"
+
+	"budsafe/backend/graph/model"
+	"budsafe/backend/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNotificationPreferences(t *testing.T) {
+	start, end := "22:00", "07:00"
+	p, err := parseNotificationPreferences(model.NotificationPreferencesInput{
+		Timezone:        " Europe/Berlin ",
+		QuietHoursStart: &start,
+		QuietHoursEnd:   &end,
+		Digest:          model.DigestModeWeekly,
+		DigestHour:      7,
+		DigestDay:       model.WeekdaySunday,
+		Channels: []*model.NotificationChannelsInput{
+			{Type: model.NotificationTypeRegulationUpdate, Channels: []model.NotificationChannel{}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Berlin", p.Timezone)
+	assert.Equal(t, time.Sunday, p.DigestWeekday)
+	assert.False(t, p.Wants("REGULATION_UPDATE", notify.InApp))
+	assert.True(t, p.Wants("RENEWAL_DUE", notify.Email))
+
+	prefs := notificationPreferences("user-1", p)
+	assert.Equal(t, model.WeekdaySunday, prefs.DigestDay)
+	assert.Equal(t, "22:00", *prefs.QuietHoursStart)
+	require.Len(t, prefs.Channels, len(model.AllNotificationType))
+
+	_, err = parseNotificationPreferences(model.NotificationPreferencesInput{
+		Timezone: "UTC", QuietHoursStart: &start, Digest: model.DigestModeImmediate, DigestDay: model.WeekdayMonday,
+	})
+	assert.ErrorContains(t, err, "quiet hours need both a start and an end")
+
+	_, err = parseNotificationPreferences(model.NotificationPreferencesInput{
+		Timezone: "UTC", Digest: model.DigestModeImmediate, DigestDay: model.WeekdayMonday,
+		Channels: []*model.NotificationChannelsInput{
+			{Type: model.NotificationTypeRenewalDue, Channels: []model.NotificationChannel{}},
+			{Type: model.NotificationTypeRenewalDue, Channels: []model.NotificationChannel{}},
+		},
+	})
+	assert.ErrorContains(t, err, "listed twice")
+}
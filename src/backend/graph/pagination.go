@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jmoiron/sqlx"
+)
+
+// Page sizes for connection fields
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pageArgs are the Relay connection arguments of a list field
+type pageArgs struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// limit validates the arguments and returns the page size and whether the
+// page is taken from the end (last/before) rather than the start
+func (a pageArgs) limit() (int, bool, error) {
+	if a.First != nil && a.Last != nil {
+		return 0, false, validationError("last", "first and last cannot be combined")
+	}
+	for field, n := range map[string]*int{"first": a.First, "last": a.Last} {
+		if n != nil && (*n < 0 || *n > maxPageSize) {
+			return 0, false, validationError(field, "%s must be between 0 and %d", field, maxPageSize)
+		}
+	}
+	switch {
+	case a.First != nil:
+		return *a.First, false, nil
+	case a.Last != nil:
+		return *a.Last, true, nil
+	default:
+		return defaultPageSize, false, nil
+	}
+}
+
+// cursor is the decoded form of an opaque connection cursor: the sort key
+// value of a row and its id, which breaks ties between equal sort keys
+type cursor struct {
+	Value string
+	ID    string
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal([]string{c.Value, c.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(field, s string) (cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	var parts []string
+	if err == nil {
+		err = json.Unmarshal(raw, &parts)
+	}
+	if err != nil || len(parts) != 2 {
+		return cursor{}, validationError(field, "invalid cursor %q", s)
+	}
+	return cursor{Value: parts[0], ID: parts[1]}, nil
+}
+
+// whereClause accumulates AND-ed SQL conditions. Conditions are written
+// with ? placeholders, which are numbered as they are added.
+type whereClause struct {
+	conds []string
+	args  []any
+}
+
+func (w *whereClause) add(cond string, args ...any) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		cond = strings.Replace(cond, "?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+	w.conds = append(w.conds, cond)
+}
+
+func (w whereClause) clone() whereClause {
+	return whereClause{conds: slices.Clone(w.conds), args: slices.Clone(w.args)}
+}
+
+func (w whereClause) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// orderKey is a sort order of a connection
+type orderKey[T any] struct {
+	// expr is the SQL sort expression; it must never be NULL
+	expr string
+	// cast is the SQL type a cursor value is cast to for comparison with expr
+	cast string
+	// value returns a node's sort key as Postgres renders expr as text
+	value func(*T) string
+}
+
+// listQuery describes the rows of one connection field
+type listQuery[T any] struct {
+	from    string
+	columns string
+	where   whereClause
+	key     orderKey[T]
+	desc    bool
+	// id returns a node's primary key
+	id   func(*T) string
+	scan func(*sqlx.Rows) (*T, error)
+}
+
+// pageOf is one page of a connection
+type pageOf[T any] struct {
+	Nodes      []*T
+	Cursors    []string
+	PageInfo   *model.PageInfo
+	TotalCount int
+}
+
+// structScan scans a row into T using its db tags
+func structScan[T any](rows *sqlx.Rows) (*T, error) {
+	var node T
+	err := rows.StructScan(&node)
+	return &node, err
+}
+
+// fetch loads the page selected by args using keyset pagination on
+// (key, id). totalCount is only computed when the client selected it.
+func (l listQuery[T]) fetch(ctx context.Context, q sqlx.QueryerContext, args pageArgs) (*pageOf[T], error) {
+	limit, backward, err := args.limit()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows after a cursor sort later in the requested direction
+	after, before := ">", "<"
+	if l.desc {
+		after, before = before, after
+	}
+	where := l.where.clone()
+	for _, bound := range []struct {
+		field, op string
+		value     *string
+	}{{"after", after, args.After}, {"before", before, args.Before}} {
+		if bound.value == nil {
+			continue
+		}
+		c, err := decodeCursor(bound.field, *bound.value)
+		if err != nil {
+			return nil, err
+		}
+		where.add(fmt.Sprintf("(%s, id) %s (?::%s, ?::uuid)", l.key.expr, bound.op, l.key.cast), c.Value, c.ID)
+	}
+
+	direction := "ASC"
+	if l.desc != backward {
+		direction = "DESC"
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY %s %s, id %s LIMIT %d",
+		l.columns, l.from, where.sql(), l.key.expr, direction, direction, limit+1)
+
+	rows, err := q.QueryxContext(ctx, query, where.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []*T
+	for rows.Next() {
+		node, err := l.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	hasMore := len(nodes) > limit
+	if hasMore {
+		nodes = nodes[:limit]
+	}
+	if backward {
+		slices.Reverse(nodes)
+	}
+
+	result := &pageOf[T]{Nodes: nodes, PageInfo: &model.PageInfo{}}
+	for _, node := range nodes {
+		result.Cursors = append(result.Cursors, encodeCursor(cursor{Value: l.key.value(node), ID: l.id(node)}))
+	}
+	if len(result.Cursors) > 0 {
+		result.PageInfo.StartCursor = &result.Cursors[0]
+		result.PageInfo.EndCursor = &result.Cursors[len(result.Cursors)-1]
+	}
+	if backward {
+		result.PageInfo.HasPreviousPage = hasMore
+		result.PageInfo.HasNextPage = args.Before != nil
+	} else {
+		result.PageInfo.HasNextPage = hasMore
+		result.PageInfo.HasPreviousPage = args.After != nil
+	}
+
+	if selectsField(ctx, "totalCount") {
+		err := sqlx.GetContext(ctx, q, &result.TotalCount,
+			fmt.Sprintf("SELECT COUNT(*) FROM %s %s", l.from, l.where.sql()), l.where.args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// selectsField reports whether the field being resolved selects the named
+// subfield. Outside a GraphQL operation every field counts as selected.
+func selectsField(ctx context.Context, name string) bool {
+	if !graphql.HasOperationContext(ctx) || graphql.GetFieldContext(ctx) == nil {
+		return true
+	}
+	return slices.Contains(graphql.CollectAllFields(ctx), name)
+}
+
+// orderDirection returns whether direction sorts descending
+func orderDirection(direction model.OrderDirection) bool {
+	return direction == model.OrderDirectionDesc
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageArgs_Limit(t *testing.T) {
+	five, tooMany := 5, maxPageSize+1
+
+	limit, backward, err := pageArgs{}.limit()
+	require.NoError(t, err)
+	assert.Equal(t, defaultPageSize, limit)
+	assert.False(t, backward)
+
+	limit, backward, err = pageArgs{Last: &five}.limit()
+	require.NoError(t, err)
+	assert.Equal(t, 5, limit)
+	assert.True(t, backward)
+
+	_, _, err = pageArgs{First: &five, Last: &five}.limit()
+	assert.Error(t, err)
+	_, _, err = pageArgs{First: &tooMany}.limit()
+	assert.Error(t, err)
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := cursor{Value: "2025-06-01 10:00:00.123456+00", ID: "8f14e45f-ceea-467f-a0e6-2f0bc3e2f1a3"}
+	decoded, err := decodeCursor("after", encodeCursor(c))
+	require.NoError(t, err)
+	assert.Equal(t, c, decoded)
+
+	_, err = decodeCursor("after", "not-a-cursor")
+	assert.Error(t, err)
+}
+
+func TestWhereClause_NumbersPlaceholders(t *testing.T) {
+	var where whereClause
+	assert.Equal(t, "", where.sql())
+
+	where.add("app_can_access_business(business_id)")
+	where.add("status = ?", "ACTIVE")
+	where.add("(expiration_date, id) > (?::date, ?::uuid)", "2025-01-01", "id-1")
+
+	assert.Equal(t, "WHERE app_can_access_business(business_id) AND status = $1 AND (expiration_date, id) > ($2::date, $3::uuid)", where.sql())
+	assert.Equal(t, []any{"ACTIVE", "2025-01-01", "id-1"}, where.args)
+
+	clone := where.clone()
+	clone.add("type = ?", "RETAIL")
+	assert.Len(t, where.conds, 3, "clone must not share conditions")
+}
@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// renewalCompletedReason explains the status change of a license whose
+// renewal completed
+const renewalCompletedReason = "Renewal completed"
+
+// syncRenewal keeps a license's renewal in step with its status after a
+// change from the status from (nil for a new license). Entering
+// RENEWAL_IN_PROGRESS opens a renewal, or resumes the open one, and leaving
+// it for anything but EXPIRED cancels the open renewal. If the resumed
+// renewal completes, license is reloaded.
+func (r *Resolver) syncRenewal(ctx context.Context, tx *sqlx.Tx, license *model.License, from *model.LicenseStatus) error {
+	switch {
+	case license.Status == model.LicenseStatusRenewalInProgress:
+		renewalID, err := openRenewal(ctx, tx, license)
+		if err != nil {
+			return err
+		}
+		completed, err := r.completeRenewalIfReady(ctx, tx, renewalID)
+		if err != nil || !completed {
+			return err
+		}
+		return tx.GetContext(ctx, license, `SELECT `+licenseColumns+` FROM licenses WHERE id = $1`, license.ID)
+	case from != nil && *from == model.LicenseStatusRenewalInProgress && license.Status != model.LicenseStatusExpired:
+		_, err := tx.ExecContext(ctx, `
+			UPDATE renewals SET status = 'CANCELLED', updated_at = NOW()
+			WHERE license_id = $1 AND status = 'IN_PROGRESS'
+		`, license.ID)
+		return err
+	}
+	return nil
+}
+
+// openRenewal returns the license's renewal in progress, starting one if
+// there is none. A new renewal's checklist is copied from the template for
+// the license's jurisdiction and type, and takes over the license's open
+// requirements that are not on a checklist yet.
+func openRenewal(ctx context.Context, tx *sqlx.Tx, license *model.License) (string, error) {
+	var renewalID string
+	err := tx.GetContext(ctx, &renewalID,
+		"SELECT id FROM renewals WHERE license_id = $1 AND status = 'IN_PROGRESS'", license.ID)
+	if err == nil {
+		return renewalID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	// A template for the license type wins over one for all types
+	var templateID *string
+	err = tx.GetContext(ctx, &templateID, `
+		SELECT id FROM renewal_templates
+		WHERE jurisdiction_id = $1 AND (license_type = $2 OR license_type IS NULL)
+		ORDER BY license_type IS NULL
+		LIMIT 1
+	`, license.JurisdictionID, license.LicenseType)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	err = tx.GetContext(ctx, &renewalID, `
+		INSERT INTO renewals (license_id, template_id, previous_expiration_date)
+		SELECT id, $2, expiration_date FROM licenses WHERE id = $1
+		RETURNING id
+	`, license.ID, templateID)
+	if err != nil {
+		return "", err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE renewal_requirements SET renewal_id = $2, updated_at = NOW()
+		WHERE license_id = $1 AND renewal_id IS NULL AND completed_at IS NULL
+	`, license.ID, renewalID)
+	if err != nil {
+		return "", err
+	}
+	if templateID != nil {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO renewal_requirements (license_id, renewal_id, description, due_date, document_category)
+			SELECT r.license_id, r.id, i.description, r.previous_expiration_date - i.due_days_before, i.document_category
+			FROM renewals r
+			JOIN renewal_template_items i ON i.template_id = $2
+			WHERE r.id = $1
+			ORDER BY i.position
+		`, renewalID, *templateID)
+		if err != nil {
+			return "", err
+		}
+	}
+	return renewalID, nil
+}
+
+// completeRenewalIfReady completes the renewal once its license is
+// RENEWAL_IN_PROGRESS, its new permit is recorded and every requirement on
+// its checklist is complete. The license then becomes ACTIVE with the new
+// permit's number and dates. It reports whether the renewal completed.
+func (r *Resolver) completeRenewalIfReady(ctx context.Context, tx *sqlx.Tx, renewalID string) (bool, error) {
+	var renewal model.Renewal
+	err := tx.GetContext(ctx, &renewal, `SELECT `+renewalColumns+` FROM renewals WHERE id = $1 FOR UPDATE`, renewalID)
+	if err != nil {
+		return false, getOrNotFound(err, "renewal", renewalID)
+	}
+	if renewal.Status != model.RenewalStatusInProgress || renewal.PermitRecordedAt == nil {
+		return false, nil
+	}
+
+	var ready bool
+	err = tx.GetContext(ctx, &ready, `
+		SELECT l.status = 'RENEWAL_IN_PROGRESS'
+		       AND NOT EXISTS (
+		         SELECT 1 FROM renewal_requirements rr
+		         WHERE rr.renewal_id = $1 AND rr.completed_at IS NULL
+		       )
+		FROM licenses l
+		WHERE l.id = $2
+		FOR UPDATE OF l
+	`, renewalID, renewal.LicenseID)
+	if err != nil || !ready {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE renewals SET status = 'COMPLETED', completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, renewalID)
+	if err != nil {
+		return false, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE licenses
+		SET status = 'ACTIVE',
+		    license_number = COALESCE($2, license_number),
+		    issued_date = $3,
+		    expiration_date = $4,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, renewal.LicenseID, renewal.NewLicenseNumber, renewal.NewIssuedDate, renewal.NewExpirationDate)
+	if err != nil {
+		return false, err
+	}
+	from, reason := model.LicenseStatusRenewalInProgress, renewalCompletedReason
+	if err := r.recordStatusChange(ctx, tx, renewal.LicenseID, &from, model.LicenseStatusActive, &reason); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// requireRequirementEvidence fails validation of field unless the renewal
+// requirement has the document it asks for: a current version of its
+// category that has not lapsed
+func requireRequirementEvidence(ctx context.Context, tx *sqlx.Tx, requirementID, field string) error {
+	var category string
+	err := tx.GetContext(ctx, &category, `
+		SELECT rr.document_category
+		FROM renewal_requirements rr
+		WHERE rr.id = $1
+		  AND rr.document_category IS NOT NULL
+		  AND NOT EXISTS (
+		    SELECT 1 FROM documents d
+		    WHERE d.renewal_requirement_id = rr.id
+		      AND d.category = rr.document_category
+		      AND d.superseded_by_id IS NULL
+		      AND (d.valid_until IS NULL OR d.valid_until >= CURRENT_DATE)
+		  )
+	`, requirementID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return validationError(field, "attach a current %s document before completing renewal requirement %s", category, requirementID)
+}
+
+// checkTemplateItems validates the checklist of a renewal template
+func checkTemplateItems(items []*model.RenewalTemplateItemInput) error {
+	if len(items) == 0 {
+		return validationError("items", "a renewal template needs at least one item")
+	}
+	for _, item := range items {
+		if err := requireNonBlank("items.description", item.Description); err != nil {
+			return err
+		}
+		if item.DueDaysBefore != nil && *item.DueDaysBefore < 0 {
+			return validationError("items.dueDaysBefore", "dueDaysBefore must not be negative")
+		}
+	}
+	return nil
+}
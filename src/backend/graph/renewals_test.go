@@ -0,0 +1,23 @@
+package graph
+
+import (
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTemplateItems(t *testing.T) {
+	days, negative := 30, -1
+	assert.NoError(t, checkTemplateItems([]*model.RenewalTemplateItemInput{
+		{Description: "Submit renewal application", DueDaysBefore: &days},
+		{Description: "Pay renewal fee"},
+	}))
+
+	assertValidationField(t, checkTemplateItems(nil), "items")
+	assertValidationField(t, checkTemplateItems([]*model.RenewalTemplateItemInput{{Description: " "}}), "items.description")
+	assertValidationField(t, checkTemplateItems([]*model.RenewalTemplateItemInput{
+		{Description: "Pay renewal fee", DueDaysBefore: &negative},
+	}), "items.dueDaysBefore")
+}
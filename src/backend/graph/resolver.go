@@ -1,6 +1,13 @@
 package graph
 
-import "github.com/jmoiron/sqlx"
+import (
+	"time"
+
+	"budsafe/backend/pubsub"
+	"budsafe/backend/storage"
+
+	"github.com/jmoiron/sqlx"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -8,4 +15,22 @@ import "github.com/jmoiron/sqlx"
 
 type Resolver struct {
 	DB *sqlx.DB
+	// AutoProvision creates a users row the first time an unknown Firebase
+	// account calls the API, instead of requiring createUser
+	AutoProvision bool
+	// Hub feeds the GraphQL subscriptions; they are unavailable when nil
+	Hub *pubsub.Hub
+	// Storage holds uploaded document files; uploads are rejected when nil
+	Storage storage.Storage
+	// UploadPolicy limits the size and content types of uploads
+	UploadPolicy storage.Policy
+	// DownloadURLTTL is how long signed document URLs stay valid; 15
+	// minutes if zero
+	DownloadURLTTL time.Duration
+	// PublicURL is the absolute base URL of the server, e.g.
+	// https://api.example.com, used to build calendar feed URLs
+	PublicURL string
+	// AllowLocalWebhooks accepts webhook URLs on localhost, over HTTP too,
+	// for development
+	AllowLocalWebhooks bool
 }
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"budsafe/backend/recurrence"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// upcomingHorizon bounds how far ahead a schedule's upcoming occurrences
+// are looked for
+const upcomingHorizon = 10 * 365 * 24 * time.Hour
+
+// parseScheduleRule validates the recurrence of a schedule input
+func parseScheduleRule(rule, startsAt string) (*recurrence.Rule, error) {
+	start, err := parseDateTime("startsAt", startsAt)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := recurrence.Parse(rule, start)
+	if err != nil {
+		return nil, validationError("rrule", "%s", err.Error())
+	}
+	return parsed, nil
+}
+
+// scheduleRule rebuilds the recurrence of a stored schedule
+func scheduleRule(s *model.ComplianceSchedule) (*recurrence.Rule, error) {
+	start, err := time.Parse(time.RFC3339, s.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	return recurrence.Parse(s.RRule, start)
+}
+
+// materializeSchedule creates the schedule's next compliance check if it is
+// due, e.g. right after the previous one was completed
+func (r *Resolver) materializeSchedule(ctx context.Context, tx *sqlx.Tx, scheduleID string) error {
+	_, err := (&recurrence.Generator{DB: r.DB}).Materialize(ctx, tx, &scheduleID)
+	return err
+}
+
+// requireLimit validates a limit argument against the page size cap
+func requireLimit(limit int) error {
+	if limit < 1 || limit > maxPageSize {
+		return validationError("limit", "limit must be between 1 and %d", maxPageSize)
+	}
+	return nil
+}
+
+// openScheduledCheck is a schedule's open check with its due date as a time
+type openScheduledCheck struct {
+	model.ComplianceCheck
+	DueAt time.Time `db:"due_at"`
+}
+
+// upcomingComplianceChecks lists the occurrences of the business's active
+// schedules up to until: open checks first, including overdue ones, then
+// the occurrences projected after the latest materialized one.
+func upcomingComplianceChecks(ctx context.Context, tx *sqlx.Tx, businessID string, now, until time.Time, limit int) ([]*model.ScheduledComplianceCheck, error) {
+	var schedules []*model.ComplianceSchedule
+	err := tx.SelectContext(ctx, &schedules, `
+		SELECT `+complianceScheduleColumns+`
+		FROM compliance_schedules
+		WHERE active AND license_id IN (
+		    SELECT id FROM licenses WHERE business_id = $1 AND status NOT IN ('REVOKED', 'EXPIRED')
+		)
+	`, businessID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(pq.StringArray, len(schedules))
+	for i, s := range schedules {
+		ids[i] = s.ID
+	}
+
+	var open []openScheduledCheck
+	err = tx.SelectContext(ctx, &open, `
+		SELECT `+complianceCheckColumns+`, next_check_date AS due_at
+		FROM compliance_checks
+		WHERE schedule_id = ANY($1::uuid[]) AND status = 'PENDING_REVIEW' AND next_check_date <= $2
+	`, ids, until)
+	if err != nil {
+		return nil, err
+	}
+	var latest []struct {
+		ScheduleID string    `db:"schedule_id"`
+		Occurrence time.Time `db:"occurrence_at"`
+	}
+	err = tx.SelectContext(ctx, &latest, `
+		SELECT schedule_id, MAX(occurrence_at) AS occurrence_at
+		FROM compliance_checks
+		WHERE schedule_id = ANY($1::uuid[])
+		GROUP BY schedule_id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	after := map[string]time.Time{}
+	for _, l := range latest {
+		after[l.ScheduleID] = l.Occurrence
+	}
+
+	byID := map[string]*model.ComplianceSchedule{}
+	for _, s := range schedules {
+		byID[s.ID] = s
+	}
+	type entry struct {
+		due time.Time
+		*model.ScheduledComplianceCheck
+	}
+	var entries []entry
+	for _, c := range open {
+		entries = append(entries, entry{c.DueAt, &model.ScheduledComplianceCheck{
+			Schedule:        byID[*c.ScheduleID],
+			DueDate:         c.DueAt.UTC().Format(time.RFC3339),
+			ComplianceCheck: &c.ComplianceCheck,
+		}})
+	}
+	for _, s := range schedules {
+		rule, err := scheduleRule(s)
+		if err != nil {
+			// Stored rules were validated on write; skip one that no longer parses
+			continue
+		}
+		from := now
+		if last, ok := after[s.ID]; ok && !last.Before(from) {
+			from = last.Add(time.Second)
+		}
+		for _, t := range rule.Between(from, until, limit) {
+			entries = append(entries, entry{t, &model.ScheduledComplianceCheck{
+				Schedule: s,
+				DueDate:  t.UTC().Format(time.RFC3339),
+			}})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].due.Before(entries[j].due) })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	result := make([]*model.ScheduledComplianceCheck, len(entries))
+	for i, e := range entries {
+		result[i] = e.ScheduledComplianceCheck
+	}
+	return result, nil
+}
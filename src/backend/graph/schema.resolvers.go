@@ -6,72 +6,310 @@ package graph
 
 import (
 	"budsafe/backend/auth"
+	"budsafe/backend/calendar"
 	"budsafe/backend/graph/generated"
 	"budsafe/backend/graph/model"
+	"budsafe/backend/notify"
+	"budsafe/backend/pubsub"
+	"budsafe/backend/rules"
+	"budsafe/backend/storage"
+	"budsafe/backend/tabular"
+	"budsafe/backend/webhook"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
-// ComplianceCheckLicense is the resolver for the complianceCheckLicense field.
-func (r *complianceCheckResolver) ComplianceCheckLicense(ctx context.Context, obj *model.ComplianceCheck) (*model.License, error) {
-	var license model.License
-	err := r.DB.Get(&license, `
-		SELECT id, business_id, jurisdiction_id, location_id, 
-		       license_number, type, status, issued_date::text, 
-		       expiration_date::text, renewal_date::text, fee_amount, 
-		       notes, created_at::text, updated_at::text 
-		FROM licenses 
-		WHERE id = $1
-	`, obj.LicenseID)
+// Actor is the resolver for the actor field.
+func (r *auditEventResolver) Actor(ctx context.Context, obj *model.AuditEvent) (*model.User, error) {
+	if obj.ActorID == nil {
+		return nil, nil
+	}
+	user, err := r.getUser(ctx, *obj.ActorID)
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Extensions["code"] == ErrCodeNotFound {
+		// The user has since been deleted; actorEmail still names them
+		return nil, nil
+	}
+	return user, err
+}
+
+// Before is the resolver for the before field.
+func (r *auditEventResolver) Before(ctx context.Context, obj *model.AuditEvent) (map[string]any, error) {
+	return auditValues(obj.Before)
+}
+
+// After is the resolver for the after field.
+func (r *auditEventResolver) After(ctx context.Context, obj *model.AuditEvent) (map[string]any, error) {
+	return auditValues(obj.After)
+}
+
+// Licenses is the resolver for the licenses field.
+func (r *businessResolver) Licenses(ctx context.Context, obj *model.Business) ([]*model.License, error) {
+	return r.loaders(ctx).licensesByBusiness.Load(ctx, obj.ID)()
+}
+
+// Locations is the resolver for the locations field.
+func (r *businessResolver) Locations(ctx context.Context, obj *model.Business) ([]*model.Location, error) {
+	return r.loaders(ctx).locationsByBusiness.Load(ctx, obj.ID)()
+}
+
+// Members is the resolver for the members field.
+func (r *businessResolver) Members(ctx context.Context, obj *model.Business) ([]*model.BusinessMember, error) {
+	var members []*model.BusinessMember
+	err := r.DB.SelectContext(ctx, &members, `
+		SELECT business_id, user_id, role, created_at::text, updated_at::text
+		FROM business_members
+		WHERE business_id = $1
+		ORDER BY created_at ASC
+	`, obj.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("license with id %s not found", obj.LicenseID)
-		}
-		return nil, fmt.Errorf("failed to get license: %v", err)
+		return nil, fmt.Errorf("failed to get business members: %v", err)
 	}
-	return &license, nil
+	return members, nil
+}
+
+// User is the resolver for the user field.
+func (r *businessMemberResolver) User(ctx context.Context, obj *model.BusinessMember) (*model.User, error) {
+	return r.getUser(ctx, obj.UserID)
+}
+
+// Business is the resolver for the business field.
+func (r *calendarFeedResolver) Business(ctx context.Context, obj *model.CalendarFeed) (*model.Business, error) {
+	return r.getBusiness(ctx, obj.BusinessID)
+}
+
+// ComplianceCheckLicense is the resolver for the complianceCheckLicense field.
+func (r *complianceCheckResolver) ComplianceCheckLicense(ctx context.Context, obj *model.ComplianceCheck) (*model.License, error) {
+	return r.getLicense(ctx, obj.LicenseID)
 }
 
 // ComplianceCheckUser is the resolver for the ComplianceCheckUser field.
 func (r *complianceCheckResolver) ComplianceCheckUser(ctx context.Context, obj *model.ComplianceCheck) (*model.User, error) {
-	var user model.User
-	var createdAt, updatedAt sql.NullString
-
 	if obj.UserID == nil {
 		return nil, nil
 	}
+	return r.getUser(ctx, *obj.UserID)
+}
 
-	err := r.DB.QueryRow(`
-		SELECT id, email, first_name, last_name, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`, obj.UserID).Scan(
-		&user.ID,
-		&user.Email,
-		&user.FirstName,
-		&user.LastName,
-		&user.Role,
-		&createdAt,
-		&updatedAt,
-	)
+// Schedule is the resolver for the schedule field.
+func (r *complianceCheckResolver) Schedule(ctx context.Context, obj *model.ComplianceCheck) (*model.ComplianceSchedule, error) {
+	if obj.ScheduleID == nil {
+		return nil, nil
+	}
+	var schedule model.ComplianceSchedule
+	err := r.DB.GetContext(ctx, &schedule, `SELECT `+complianceScheduleColumns+` FROM compliance_schedules WHERE id = $1`, *obj.ScheduleID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with id %v not found", obj.UserID)
-		}
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return nil, getOrNotFound(err, "compliance schedule", *obj.ScheduleID)
+	}
+	return &schedule, nil
+}
+
+// License is the resolver for the license field.
+func (r *complianceScheduleResolver) License(ctx context.Context, obj *model.ComplianceSchedule) (*model.License, error) {
+	return r.getLicense(ctx, obj.LicenseID)
+}
+
+// AssignedTo is the resolver for the assignedTo field.
+func (r *complianceScheduleResolver) AssignedTo(ctx context.Context, obj *model.ComplianceSchedule) (*model.User, error) {
+	if obj.AssignedToID == nil {
+		return nil, nil
 	}
+	return r.getUser(ctx, *obj.AssignedToID)
+}
 
-	if createdAt.Valid {
-		user.CreatedAt = createdAt.String
+// Upcoming is the resolver for the upcoming field.
+func (r *complianceScheduleResolver) Upcoming(ctx context.Context, obj *model.ComplianceSchedule, limit int) ([]string, error) {
+	if err := requireLimit(limit); err != nil {
+		return nil, err
+	}
+	upcoming := []string{}
+	if !obj.Active {
+		return upcoming, nil
 	}
-	if updatedAt.Valid {
-		user.UpdatedAt = &updatedAt.String
+	rule, err := scheduleRule(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse compliance schedule %s: %v", obj.ID, err)
+	}
+	now := time.Now()
+	for _, t := range rule.Between(now, now.Add(upcomingHorizon), limit) {
+		upcoming = append(upcoming, t.UTC().Format(time.RFC3339))
 	}
+	return upcoming, nil
+}
 
-	return &user, nil
+// ComplianceChecks is the resolver for the complianceChecks field.
+func (r *complianceScheduleResolver) ComplianceChecks(ctx context.Context, obj *model.ComplianceSchedule) ([]*model.ComplianceCheck, error) {
+	var checks []*model.ComplianceCheck
+	err := r.DB.SelectContext(ctx, &checks, `
+		SELECT `+complianceCheckColumns+`
+		FROM compliance_checks
+		WHERE schedule_id = $1
+		ORDER BY occurrence_at DESC
+	`, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled compliance checks: %v", err)
+	}
+	return checks, nil
+}
+
+// FileURL is the resolver for the fileUrl field.
+func (r *documentResolver) FileURL(ctx context.Context, obj *model.Document) (string, error) {
+	return r.documentURL(ctx, obj)
+}
+
+// SupersededBy is the resolver for the supersededBy field.
+func (r *documentResolver) SupersededBy(ctx context.Context, obj *model.Document) (*model.Document, error) {
+	if obj.SupersededByID == nil {
+		return nil, nil
+	}
+	var document model.Document
+	err := r.DB.GetContext(ctx, &document, `SELECT `+documentColumns+` FROM documents WHERE id = $1`, *obj.SupersededByID)
+	if err != nil {
+		return nil, getOrNotFound(err, "document", *obj.SupersededByID)
+	}
+	return &document, nil
+}
+
+// Versions is the resolver for the versions field.
+func (r *documentResolver) Versions(ctx context.Context, obj *model.Document) ([]*model.Document, error) {
+	var versions []*model.Document
+	err := r.DB.SelectContext(ctx, &versions, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE series_id = $1
+		ORDER BY version ASC
+	`, obj.SeriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document versions: %v", err)
+	}
+	return versions, nil
+}
+
+// UploadedBy is the resolver for the uploadedBy field.
+func (r *documentResolver) UploadedBy(ctx context.Context, obj *model.Document) (*model.User, error) {
+	return r.getUser(ctx, obj.UploadedByID)
+}
+
+// Business is the resolver for the business field.
+func (r *documentResolver) Business(ctx context.Context, obj *model.Document) (*model.Business, error) {
+	if obj.BusinessID == nil {
+		return nil, nil
+	}
+	return r.getBusiness(ctx, *obj.BusinessID)
+}
+
+// License is the resolver for the license field.
+func (r *documentResolver) License(ctx context.Context, obj *model.Document) (*model.License, error) {
+	if obj.LicenseID == nil {
+		return nil, nil
+	}
+	return r.getLicense(ctx, *obj.LicenseID)
+}
+
+// RenewalRequirement is the resolver for the renewalRequirement field.
+func (r *documentResolver) RenewalRequirement(ctx context.Context, obj *model.Document) (*model.RenewalRequirement, error) {
+	if obj.RenewalRequirementID == nil {
+		return nil, nil
+	}
+	var requirement model.RenewalRequirement
+	err := r.DB.GetContext(ctx, &requirement, `SELECT `+renewalRequirementColumns+` FROM renewal_requirements WHERE id = $1`, *obj.RenewalRequirementID)
+	if err != nil {
+		return nil, getOrNotFound(err, "renewal requirement", *obj.RenewalRequirementID)
+	}
+	return &requirement, nil
+}
+
+// Business is the resolver for the business field.
+func (r *licenseResolver) Business(ctx context.Context, obj *model.License) (*model.Business, error) {
+	return r.getBusiness(ctx, obj.BusinessID)
+}
+
+// Location is the resolver for the location field.
+func (r *licenseResolver) Location(ctx context.Context, obj *model.License) (*model.Location, error) {
+	if obj.LocationID == nil {
+		return nil, nil
+	}
+	return r.getLocation(ctx, *obj.LocationID)
+}
+
+// Jurisdiction is the resolver for the jurisdiction field.
+func (r *licenseResolver) Jurisdiction(ctx context.Context, obj *model.License) (*model.Jurisdiction, error) {
+	return r.getJurisdiction(ctx, obj.JurisdictionID)
+}
+
+// StatusHistory is the resolver for the statusHistory field.
+func (r *licenseResolver) StatusHistory(ctx context.Context, obj *model.License) ([]*model.LicenseStatusChange, error) {
+	var history []*model.LicenseStatusChange
+	err := r.DB.SelectContext(ctx, &history, `
+		SELECT id, license_id, from_status, to_status, reason, changed_by_id, changed_at::text
+		FROM license_status_history
+		WHERE license_id = $1
+		ORDER BY changed_at ASC, id ASC
+	`, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get license status history: %v", err)
+	}
+	return history, nil
+}
+
+// Renewals is the resolver for the renewals field.
+func (r *licenseResolver) Renewals(ctx context.Context, obj *model.License) ([]*model.Renewal, error) {
+	renewals := []*model.Renewal{}
+	err := r.DB.SelectContext(ctx, &renewals, `
+		SELECT `+renewalColumns+`
+		FROM renewals
+		WHERE license_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get license renewals: %v", err)
+	}
+	return renewals, nil
+}
+
+// CurrentRenewal is the resolver for the currentRenewal field.
+func (r *licenseResolver) CurrentRenewal(ctx context.Context, obj *model.License) (*model.Renewal, error) {
+	var renewal model.Renewal
+	err := r.DB.GetContext(ctx, &renewal, `
+		SELECT `+renewalColumns+`
+		FROM renewals
+		WHERE license_id = $1 AND status = 'IN_PROGRESS'
+	`, obj.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current renewal: %v", err)
+	}
+	return &renewal, nil
+}
+
+// ChangedBy is the resolver for the changedBy field.
+func (r *licenseStatusChangeResolver) ChangedBy(ctx context.Context, obj *model.LicenseStatusChange) (*model.User, error) {
+	if obj.ChangedByID == nil {
+		return nil, nil
+	}
+	return r.getUser(ctx, *obj.ChangedByID)
+}
+
+// Business is the resolver for the business field.
+func (r *locationResolver) Business(ctx context.Context, obj *model.Location) (*model.Business, error) {
+	return r.getBusiness(ctx, obj.BusinessID)
+}
+
+// Licenses is the resolver for the licenses field.
+func (r *locationResolver) Licenses(ctx context.Context, obj *model.Location) ([]*model.License, error) {
+	return r.loaders(ctx).licensesByLocation.Load(ctx, obj.ID)()
 }
 
 // CreateUser is the resolver for the createUser field.
@@ -79,24 +317,27 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUse
 	// Get the authenticated user from the context.
 	authUser := auth.ForContext(ctx)
 	if authUser == nil {
-		return nil, fmt.Errorf("access denied: user not authenticated")
+		return nil, unauthenticatedError("user not authenticated")
 	}
 
-	// --- TEMPORARY DEBUGGING LINE ---
-	// This will print the exact value and type of the role being sent to the query.
-	log.Printf("DEBUG: Attempting to insert role. Value: '%s', Type: %T", input.Role, input.Role)
-	// --- END DEBUGGING ---
+	// Self-registration cannot grant ADMIN; admins are promoted by an existing admin via updateUser.
+	if input.Role == model.UserRoleAdmin {
+		return nil, forbiddenError("the ADMIN role cannot be self-assigned")
+	}
 
 	// Now proceed with the user creation logic...
 	query := `
 		INSERT INTO users (id, email, first_name, last_name, role, firebase_uid, created_at, updated_at)
 		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, NOW(), NOW())
-		RETURNING *`
+		RETURNING ` + userColumns
 
 	var user model.User
-	err := r.DB.GetContext(ctx, &user, query, authUser.Email, input.FirstName, input.LastName, string(input.Role), authUser.UID)
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &user, query, authUser.Email, input.FirstName, input.LastName, string(input.Role), authUser.UID)
+		return dbError(err, "create user profile")
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create user profile: %w", err)
+		return nil, err
 	}
 
 	return &user, nil
@@ -104,367 +345,1645 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUse
 
 // UpdateUser is the resolver for the updateUser field.
 func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input model.UpdateUserInput) (*model.User, error) {
-	panic(fmt.Errorf("not implemented: UpdateUser - updateUser"))
+	viewer, err := r.requireSelfOrAdmin(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Role != nil && viewer.Role != model.UserRoleAdmin {
+		return nil, forbiddenError("only an ADMIN can change user roles")
+	}
+	if input.Email != nil {
+		if err := requireNonBlank("email", *input.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	var user model.User
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		return updateRow(ctx, tx, &user, "users", "user", id, map[string]interface{}{
+			"email":      input.Email,
+			"first_name": input.FirstName,
+			"last_name":  input.LastName,
+			"role":       input.Role,
+		}, userColumns)
+	})
+	if err != nil {
+		return nil, dbError(err, "update user")
+	}
+	return &user, nil
 }
 
 // DeleteUser is the resolver for the deleteUser field.
 func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteUser - deleteUser"))
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		return deleteRow(ctx, tx, "users", "user", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete user")
+	}
+	return true, nil
 }
 
 // CreateBusiness is the resolver for the createBusiness field.
 func (r *mutationResolver) CreateBusiness(ctx context.Context, input model.CreateBusinessInput) (*model.Business, error) {
-	panic(fmt.Errorf("not implemented: CreateBusiness - createBusiness"))
+	if err := requireNonBlank("name", input.Name); err != nil {
+		return nil, err
+	}
+
+	var business model.Business
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		ownerID, err := currentUserID(ctx, tx)
+		if err != nil {
+			return err
+		}
+		err = tx.GetContext(ctx, &business, `
+			INSERT INTO businesses (name, type, description, owner_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+businessColumns,
+			input.Name, input.Type, input.Description, ownerID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO business_members (business_id, user_id, role)
+			VALUES ($1, $2, 'BUSINESS_OWNER')
+		`, business.ID, ownerID)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "create business")
+	}
+	return &business, nil
 }
 
 // UpdateBusiness is the resolver for the updateBusiness field.
 func (r *mutationResolver) UpdateBusiness(ctx context.Context, id string, input model.UpdateBusinessInput) (*model.Business, error) {
-	panic(fmt.Errorf("not implemented: UpdateBusiness - updateBusiness"))
+	if input.Name != nil {
+		if err := requireNonBlank("name", *input.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var business model.Business
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return updateRow(ctx, tx, &business, "businesses", "business", id, map[string]interface{}{
+			"name":        input.Name,
+			"type":        input.Type,
+			"description": input.Description,
+		}, businessColumns)
+	})
+	if err != nil {
+		return nil, dbError(err, "update business")
+	}
+	return &business, nil
 }
 
 // DeleteBusiness is the resolver for the deleteBusiness field.
 func (r *mutationResolver) DeleteBusiness(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteBusiness - deleteBusiness"))
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return deleteRow(ctx, tx, "businesses", "business", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete business")
+	}
+	return true, nil
+}
+
+// AddBusinessMember is the resolver for the addBusinessMember field.
+func (r *mutationResolver) AddBusinessMember(ctx context.Context, businessID string, userID string, role model.UserRole) (*model.BusinessMember, error) {
+	if role == model.UserRoleAdmin {
+		return nil, validationError("role", "ADMIN is a global role and cannot be granted per business")
+	}
+
+	var member model.BusinessMember
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		if err := requireExists(ctx, tx, "users", "user", userID); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &member, `
+			INSERT INTO business_members (business_id, user_id, role)
+			VALUES ($1, $2, $3)
+			RETURNING business_id, user_id, role, created_at::text, updated_at::text`,
+			businessID, userID, role)
+	})
+	if err != nil {
+		return nil, dbError(err, "add business member")
+	}
+	return &member, nil
+}
+
+// UpdateBusinessMember is the resolver for the updateBusinessMember field.
+func (r *mutationResolver) UpdateBusinessMember(ctx context.Context, businessID string, userID string, role model.UserRole) (*model.BusinessMember, error) {
+	if role == model.UserRoleAdmin {
+		return nil, validationError("role", "ADMIN is a global role and cannot be granted per business")
+	}
+
+	var member model.BusinessMember
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		if err := requireNotOwner(ctx, tx, businessID, userID, role); err != nil {
+			return err
+		}
+		err := tx.GetContext(ctx, &member, `
+			UPDATE business_members
+			SET role = $3, updated_at = NOW()
+			WHERE business_id = $1 AND user_id = $2
+			RETURNING business_id, user_id, role, created_at::text, updated_at::text`,
+			businessID, userID, role)
+		return getOrNotFound(err, "business member", userID)
+	})
+	if err != nil {
+		return nil, dbError(err, "update business member")
+	}
+	return &member, nil
+}
+
+// RemoveBusinessMember is the resolver for the removeBusinessMember field.
+func (r *mutationResolver) RemoveBusinessMember(ctx context.Context, businessID string, userID string) (bool, error) {
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		if err := requireNotOwner(ctx, tx, businessID, userID, ""); err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM business_members WHERE business_id = $1 AND user_id = $2", businessID, userID)
+		if err != nil {
+			return err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return notFoundError("business member", userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, dbError(err, "remove business member")
+	}
+	return true, nil
 }
 
 // CreateLicense is the resolver for the createLicense field.
 func (r *mutationResolver) CreateLicense(ctx context.Context, input model.CreateLicenseInput) (*model.License, error) {
-	panic(fmt.Errorf("not implemented: CreateLicense - createLicense"))
+	var license *model.License
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		license, err = r.createLicense(ctx, tx, input)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "create license")
+	}
+	return license, nil
+}
+
+// ImportLicenses is the resolver for the importLicenses field.
+func (r *mutationResolver) ImportLicenses(ctx context.Context, file graphql.Upload, dryRun bool) (*model.LicenseImportResult, error) {
+	if limit := r.UploadPolicy.Limit(); file.Size > limit {
+		return nil, validationError("file", "file is %d bytes; the limit is %d bytes", file.Size, limit)
+	}
+	records, err := tabular.Read(file.Filename, file.File)
+	if err != nil {
+		return nil, validationError("file", "%v", err)
+	}
+	return r.importLicenses(ctx, records, dryRun)
 }
 
 // UpdateLicense is the resolver for the updateLicense field.
 func (r *mutationResolver) UpdateLicense(ctx context.Context, id string, input model.UpdateLicenseInput) (*model.License, error) {
-	panic(fmt.Errorf("not implemented: UpdateLicense - updateLicense"))
+	if input.LicenseNumber != nil {
+		if err := requireNonBlank("licenseNumber", *input.LicenseNumber); err != nil {
+			return nil, err
+		}
+		trimmed := strings.TrimSpace(*input.LicenseNumber)
+		input.LicenseNumber = &trimmed
+	}
+	var issuedDate, expirationDate *string
+	if input.IssuedDate != nil {
+		date, err := parseDate("issuedDate", *input.IssuedDate)
+		if err != nil {
+			return nil, err
+		}
+		issuedDate = &date
+	}
+	if input.ExpirationDate != nil {
+		date, err := parseDate("expirationDate", *input.ExpirationDate)
+		if err != nil {
+			return nil, err
+		}
+		expirationDate = &date
+	}
+
+	var license model.License
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "licenses", "license", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		var current model.License
+		err := tx.GetContext(ctx, &current, `SELECT `+licenseColumns+` FROM licenses WHERE id = $1 FOR UPDATE`, id)
+		if err != nil {
+			return getOrNotFound(err, "license", id)
+		}
+
+		// Validate the date range against whichever side is not being changed
+		issued := current.IssuedDate[:min(len(current.IssuedDate), len(time.DateOnly))]
+		expires := current.ExpirationDate[:min(len(current.ExpirationDate), len(time.DateOnly))]
+		if issuedDate != nil {
+			issued = *issuedDate
+		}
+		if expirationDate != nil {
+			expires = *expirationDate
+		}
+		if err := requireDateOrder(issued, expires); err != nil {
+			return err
+		}
+
+		if input.JurisdictionID != nil {
+			if err := requireExists(ctx, tx, "jurisdictions", "jurisdiction", *input.JurisdictionID); err != nil {
+				return err
+			}
+		}
+		// Numbers are checked once any of what makes them valid changes, so
+		// licenses entered before their jurisdiction had formats keep working
+		if input.LicenseNumber != nil || input.LicenseType != nil || input.JurisdictionID != nil {
+			number, licenseType, jurisdictionID := current.LicenseNumber, current.LicenseType, current.JurisdictionID
+			if input.LicenseNumber != nil {
+				number = *input.LicenseNumber
+			}
+			if input.LicenseType != nil {
+				licenseType = *input.LicenseType
+			}
+			if input.JurisdictionID != nil {
+				jurisdictionID = *input.JurisdictionID
+			}
+			if err := checkLicenseNumber(ctx, tx, jurisdictionID, licenseType, number); err != nil {
+				return err
+			}
+		}
+		if input.LocationID != nil {
+			if err := requireLocationOfBusiness(ctx, tx, *input.LocationID, current.BusinessID); err != nil {
+				return err
+			}
+		}
+
+		statusChanged := input.Status != nil && *input.Status != current.Status
+		var statusReason *string
+		if statusChanged {
+			if err := checkLicenseTransition(current.Status, *input.Status); err != nil {
+				return err
+			}
+			if statusReason, err = checkStatusReason(*input.Status, input.StatusReason); err != nil {
+				return err
+			}
+		} else if input.StatusReason != nil {
+			return validationError("statusReason", "statusReason is only accepted with a status change")
+		}
+
+		err = updateRow(ctx, tx, &license, "licenses", "license", id, map[string]interface{}{
+			"location_id":     input.LocationID,
+			"license_number":  input.LicenseNumber,
+			"type":            input.LicenseType,
+			"jurisdiction_id": input.JurisdictionID,
+			"issued_date":     issuedDate,
+			"expiration_date": expirationDate,
+			"status":          input.Status,
+			"notes":           input.Notes,
+		}, licenseColumns)
+		if err != nil || !statusChanged {
+			return err
+		}
+		if err := r.recordStatusChange(ctx, tx, id, &current.Status, license.Status, statusReason); err != nil {
+			return err
+		}
+		return r.syncRenewal(ctx, tx, &license, &current.Status)
+	})
+	if err != nil {
+		return nil, dbError(err, "update license")
+	}
+	return &license, nil
 }
 
 // DeleteLicense is the resolver for the deleteLicense field.
 func (r *mutationResolver) DeleteLicense(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteLicense - deleteLicense"))
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "licenses", "license", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return deleteRow(ctx, tx, "licenses", "license", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete license")
+	}
+	return true, nil
 }
 
 // CreateLocation is the resolver for the createLocation field.
 func (r *mutationResolver) CreateLocation(ctx context.Context, input model.CreateLocationInput) (*model.Location, error) {
-	panic(fmt.Errorf("not implemented: CreateLocation - createLocation"))
+	for field, value := range map[string]string{
+		"address": input.Address,
+		"city":    input.City,
+		"state":   input.State,
+		"zipCode": input.ZipCode,
+	} {
+		if err := requireNonBlank(field, value); err != nil {
+			return nil, err
+		}
+	}
+
+	var location model.Location
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, input.BusinessID, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		if input.IsPrimary {
+			if err := clearPrimaryLocation(ctx, tx, input.BusinessID, ""); err != nil {
+				return err
+			}
+		}
+		return tx.GetContext(ctx, &location, `
+			INSERT INTO locations (business_id, address, city, state, zip_code, is_primary)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+locationColumns,
+			input.BusinessID, input.Address, input.City, input.State, input.ZipCode, input.IsPrimary)
+	})
+	if err != nil {
+		return nil, dbError(err, "create location")
+	}
+	return &location, nil
 }
 
 // UpdateLocation is the resolver for the updateLocation field.
 func (r *mutationResolver) UpdateLocation(ctx context.Context, id string, input model.UpdateLocationInput) (*model.Location, error) {
-	panic(fmt.Errorf("not implemented: UpdateLocation - updateLocation"))
+	for field, value := range map[string]*string{
+		"address": input.Address,
+		"city":    input.City,
+		"state":   input.State,
+		"zipCode": input.ZipCode,
+	} {
+		if value != nil {
+			if err := requireNonBlank(field, *value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var location model.Location
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		businessID, err := r.requireEntityRole(ctx, tx, "locations", "location", id, model.UserRoleBusinessOwner)
+		if err != nil {
+			return err
+		}
+		if input.IsPrimary != nil && *input.IsPrimary {
+			if err := clearPrimaryLocation(ctx, tx, businessID, id); err != nil {
+				return err
+			}
+		}
+		return updateRow(ctx, tx, &location, "locations", "location", id, map[string]interface{}{
+			"address":    input.Address,
+			"city":       input.City,
+			"state":      input.State,
+			"zip_code":   input.ZipCode,
+			"is_primary": input.IsPrimary,
+		}, locationColumns)
+	})
+	if err != nil {
+		return nil, dbError(err, "update location")
+	}
+	return &location, nil
 }
 
 // DeleteLocation is the resolver for the deleteLocation field.
 func (r *mutationResolver) DeleteLocation(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteLocation - deleteLocation"))
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "locations", "location", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return deleteRow(ctx, tx, "locations", "location", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete location")
+	}
+	return true, nil
 }
 
 // CreateComplianceCheck is the resolver for the createComplianceCheck field.
 func (r *mutationResolver) CreateComplianceCheck(ctx context.Context, input model.CreateComplianceCheckInput) (*model.ComplianceCheck, error) {
-	panic(fmt.Errorf("not implemented: CreateComplianceCheck - createComplianceCheck"))
+	if err := requireNonBlank("title", input.Title); err != nil {
+		return nil, err
+	}
+	dueDate, err := parseDateTime("dueDate", input.DueDate)
+	if err != nil {
+		return nil, err
+	}
+	var checkedAt *time.Time
+	if isCheckOutcome(input.Status) {
+		now := time.Now()
+		checkedAt = &now
+	}
+
+	var check model.ComplianceCheck
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		businessID, err := r.requireEntityRole(ctx, tx, "licenses", "license", input.LicenseID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager)
+		if err != nil {
+			return err
+		}
+		if input.AssignedToID != nil {
+			if err := requireMember(ctx, tx, businessID, *input.AssignedToID, "assignedToId"); err != nil {
+				return err
+			}
+		}
+		return tx.GetContext(ctx, &check, `
+			INSERT INTO compliance_checks (license_id, check_type, status, checked_at, next_check_date, notes, checked_by_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING `+complianceCheckColumns,
+			input.LicenseID, input.Title, input.Status, checkedAt, dueDate, input.Notes, input.AssignedToID)
+	})
+	if err != nil {
+		return nil, dbError(err, "create compliance check")
+	}
+	return &check, nil
 }
 
 // UpdateComplianceCheck is the resolver for the updateComplianceCheck field.
 func (r *mutationResolver) UpdateComplianceCheck(ctx context.Context, id string, input model.UpdateComplianceCheckInput) (*model.ComplianceCheck, error) {
-	panic(fmt.Errorf("not implemented: UpdateComplianceCheck - updateComplianceCheck"))
-}
+	if input.Title != nil {
+		if err := requireNonBlank("title", *input.Title); err != nil {
+			return nil, err
+		}
+	}
+	var dueDate, checkedAt *time.Time
+	if input.DueDate != nil {
+		parsed, err := parseDateTime("dueDate", *input.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		dueDate = &parsed
+	}
+	if input.Status != nil && isCheckOutcome(*input.Status) {
+		now := time.Now()
+		checkedAt = &now
+	}
 
-// DeleteComplianceCheck is the resolver for the deleteComplianceCheck field.
+	var check model.ComplianceCheck
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		businessID, err := r.requireEntityRole(ctx, tx, "compliance_checks", "compliance check", id,
+			model.UserRoleBusinessOwner, model.UserRoleComplianceManager, model.UserRoleEmployee)
+		if err != nil {
+			return err
+		}
+		if input.AssignedToID != nil {
+			if err := requireMember(ctx, tx, businessID, *input.AssignedToID, "assignedToId"); err != nil {
+				return err
+			}
+		}
+		err = updateRow(ctx, tx, &check, "compliance_checks", "compliance check", id, map[string]interface{}{
+			"check_type":      input.Title,
+			"next_check_date": dueDate,
+			"status":          input.Status,
+			"checked_at":      checkedAt,
+			"checked_by_id":   input.AssignedToID,
+			"notes":           input.Notes,
+		}, complianceCheckColumns)
+		if err != nil {
+			return err
+		}
+		// Completing a scheduled check brings on the next occurrence
+		if check.ScheduleID != nil && checkedAt != nil {
+			return r.materializeSchedule(ctx, tx, *check.ScheduleID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "update compliance check")
+	}
+	return &check, nil
+}
+
+// DeleteComplianceCheck is the resolver for the deleteComplianceCheck field.
 func (r *mutationResolver) DeleteComplianceCheck(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteComplianceCheck - deleteComplianceCheck"))
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "compliance_checks", "compliance check", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		return deleteRow(ctx, tx, "compliance_checks", "compliance check", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete compliance check")
+	}
+	return true, nil
+}
+
+// CreateComplianceSchedule is the resolver for the createComplianceSchedule field.
+func (r *mutationResolver) CreateComplianceSchedule(ctx context.Context, input model.CreateComplianceScheduleInput) (*model.ComplianceSchedule, error) {
+	if err := requireNonBlank("title", input.Title); err != nil {
+		return nil, err
+	}
+	if input.LeadDays < 0 {
+		return nil, validationError("leadDays", "leadDays must not be negative")
+	}
+	rule, err := parseScheduleRule(input.Rrule, input.StartsAt)
+	if err != nil {
+		return nil, err
+	}
+	startsAt, _ := parseDateTime("startsAt", input.StartsAt)
+
+	var schedule model.ComplianceSchedule
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		businessID, err := r.requireEntityRole(ctx, tx, "licenses", "license", input.LicenseID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager)
+		if err != nil {
+			return err
+		}
+		if input.AssignedToID != nil {
+			if err := requireMember(ctx, tx, businessID, *input.AssignedToID, "assignedToId"); err != nil {
+				return err
+			}
+		}
+		err = tx.GetContext(ctx, &schedule, `
+			INSERT INTO compliance_schedules (license_id, title, notes, rrule, starts_at, lead_days, assigned_to_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING `+complianceScheduleColumns,
+			input.LicenseID, input.Title, input.Notes, rule.String(), startsAt, input.LeadDays, input.AssignedToID)
+		if err != nil {
+			return err
+		}
+		return r.materializeSchedule(ctx, tx, schedule.ID)
+	})
+	if err != nil {
+		return nil, dbError(err, "create compliance schedule")
+	}
+	return &schedule, nil
+}
+
+// UpdateComplianceSchedule is the resolver for the updateComplianceSchedule field.
+func (r *mutationResolver) UpdateComplianceSchedule(ctx context.Context, id string, input model.UpdateComplianceScheduleInput) (*model.ComplianceSchedule, error) {
+	if input.Title != nil {
+		if err := requireNonBlank("title", *input.Title); err != nil {
+			return nil, err
+		}
+	}
+	if input.LeadDays != nil && *input.LeadDays < 0 {
+		return nil, validationError("leadDays", "leadDays must not be negative")
+	}
+
+	var schedule model.ComplianceSchedule
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		businessID, err := r.requireEntityRole(ctx, tx, "compliance_schedules", "compliance schedule", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager)
+		if err != nil {
+			return err
+		}
+		if input.AssignedToID != nil {
+			if err := requireMember(ctx, tx, businessID, *input.AssignedToID, "assignedToId"); err != nil {
+				return err
+			}
+		}
+
+		// Validate the recurrence as it will be after the update
+		var rrule *string
+		var startsAt *time.Time
+		if input.Rrule != nil || input.StartsAt != nil {
+			var current model.ComplianceSchedule
+			err := tx.GetContext(ctx, &current, `SELECT `+complianceScheduleColumns+` FROM compliance_schedules WHERE id = $1`, id)
+			if err != nil {
+				return getOrNotFound(err, "compliance schedule", id)
+			}
+			if input.Rrule != nil {
+				current.RRule = *input.Rrule
+			}
+			if input.StartsAt != nil {
+				current.StartsAt = *input.StartsAt
+			}
+			rule, err := parseScheduleRule(current.RRule, current.StartsAt)
+			if err != nil {
+				return err
+			}
+			normalized := rule.String()
+			start, _ := parseDateTime("startsAt", current.StartsAt)
+			rrule, startsAt = &normalized, &start
+		}
+
+		err = updateRow(ctx, tx, &schedule, "compliance_schedules", "compliance schedule", id, map[string]interface{}{
+			"title":          input.Title,
+			"notes":          input.Notes,
+			"rrule":          rrule,
+			"starts_at":      startsAt,
+			"lead_days":      input.LeadDays,
+			"assigned_to_id": input.AssignedToID,
+			"active":         input.Active,
+		}, complianceScheduleColumns)
+		if err != nil {
+			return err
+		}
+		return r.materializeSchedule(ctx, tx, id)
+	})
+	if err != nil {
+		return nil, dbError(err, "update compliance schedule")
+	}
+	return &schedule, nil
+}
+
+// DeleteComplianceSchedule is the resolver for the deleteComplianceSchedule field.
+func (r *mutationResolver) DeleteComplianceSchedule(ctx context.Context, id string) (bool, error) {
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "compliance_schedules", "compliance schedule", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		return deleteRow(ctx, tx, "compliance_schedules", "compliance schedule", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete compliance schedule")
+	}
+	return true, nil
+}
+
+// EvaluateCompliance is the resolver for the evaluateCompliance field.
+func (r *mutationResolver) EvaluateCompliance(ctx context.Context, businessID string) ([]*model.ComplianceCheck, error) {
+	checks := []*model.ComplianceCheck{}
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		ids, err := (&rules.Runner{DB: r.DB}).Evaluate(ctx, tx, &businessID)
+		if err != nil {
+			return err
+		}
+		return tx.SelectContext(ctx, &checks, `
+			SELECT `+complianceCheckColumns+`
+			FROM compliance_checks
+			WHERE id = ANY($1::uuid[])
+			ORDER BY license_id, regulation_id, rule_clause
+		`, pq.Array(ids))
+	})
+	if err != nil {
+		return nil, dbError(err, "evaluate compliance")
+	}
+	return checks, nil
 }
 
 // CreateRenewalRequirement is the resolver for the createRenewalRequirement field.
 func (r *mutationResolver) CreateRenewalRequirement(ctx context.Context, input model.CreateRenewalRequirementInput) (*model.RenewalRequirement, error) {
-	panic(fmt.Errorf("not implemented: CreateRenewalRequirement - createRenewalRequirement"))
+	if err := requireNonBlank("description", input.Description); err != nil {
+		return nil, err
+	}
+	var deadline *string
+	if input.Deadline != nil {
+		date, err := parseDate("deadline", *input.Deadline)
+		if err != nil {
+			return nil, err
+		}
+		deadline = &date
+	}
+
+	var requirement model.RenewalRequirement
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "licenses", "license", input.LicenseID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		// A requirement added during a renewal joins its checklist
+		return tx.GetContext(ctx, &requirement, `
+			INSERT INTO renewal_requirements (license_id, description, due_date, completed_at, renewal_id)
+			VALUES ($1, $2, $3, CASE WHEN $4 THEN NOW() END,
+			        (SELECT id FROM renewals WHERE license_id = $1 AND status = 'IN_PROGRESS'))
+			RETURNING `+renewalRequirementColumns,
+			input.LicenseID, input.Description, deadline, input.IsCompleted)
+	})
+	if err != nil {
+		return nil, dbError(err, "create renewal requirement")
+	}
+	return &requirement, nil
 }
 
 // UpdateRenewalRequirement is the resolver for the updateRenewalRequirement field.
 func (r *mutationResolver) UpdateRenewalRequirement(ctx context.Context, id string, input model.UpdateRenewalRequirementInput) (*model.RenewalRequirement, error) {
-	panic(fmt.Errorf("not implemented: UpdateRenewalRequirement - updateRenewalRequirement"))
+	if input.Description != nil {
+		if err := requireNonBlank("description", *input.Description); err != nil {
+			return nil, err
+		}
+	}
+	var deadline *string
+	if input.Deadline != nil {
+		date, err := parseDate("deadline", *input.Deadline)
+		if err != nil {
+			return nil, err
+		}
+		deadline = &date
+	}
+
+	var requirement model.RenewalRequirement
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "renewal_requirements", "renewal requirement", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		if input.IsCompleted != nil {
+			if *input.IsCompleted {
+				if err := requireRequirementEvidence(ctx, tx, id, "isCompleted"); err != nil {
+					return err
+				}
+			}
+			// Keep the original completion time when re-completing
+			_, err := tx.ExecContext(ctx, `
+				UPDATE renewal_requirements
+				SET completed_at = CASE WHEN $2 THEN COALESCE(completed_at, NOW()) END,
+				    updated_at = NOW()
+				WHERE id = $1
+			`, id, *input.IsCompleted)
+			if err != nil {
+				return err
+			}
+		}
+		err := updateRow(ctx, tx, &requirement, "renewal_requirements", "renewal requirement", id, map[string]interface{}{
+			"description": input.Description,
+			"due_date":    deadline,
+		}, renewalRequirementColumns)
+		if err != nil || !requirement.IsCompleted || requirement.RenewalID == nil {
+			return err
+		}
+		_, err = r.completeRenewalIfReady(ctx, tx, *requirement.RenewalID)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "update renewal requirement")
+	}
+	return &requirement, nil
 }
 
 // CompleteRenewalRequirement is the resolver for the completeRenewalRequirement field.
 func (r *mutationResolver) CompleteRenewalRequirement(ctx context.Context, id string) (*model.RenewalRequirement, error) {
-	panic(fmt.Errorf("not implemented: CompleteRenewalRequirement - completeRenewalRequirement"))
+	var requirement model.RenewalRequirement
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "renewal_requirements", "renewal requirement", id,
+			model.UserRoleBusinessOwner, model.UserRoleComplianceManager, model.UserRoleEmployee); err != nil {
+			return err
+		}
+		if err := requireRequirementEvidence(ctx, tx, id, "id"); err != nil {
+			return err
+		}
+		err := tx.GetContext(ctx, &requirement, `
+			UPDATE renewal_requirements
+			SET completed_at = COALESCE(completed_at, NOW()), updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+renewalRequirementColumns, id)
+		if err != nil || requirement.RenewalID == nil {
+			return getOrNotFound(err, "renewal requirement", id)
+		}
+		_, err = r.completeRenewalIfReady(ctx, tx, *requirement.RenewalID)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "complete renewal requirement")
+	}
+	return &requirement, nil
+}
+
+// RecordRenewalPermit is the resolver for the recordRenewalPermit field.
+func (r *mutationResolver) RecordRenewalPermit(ctx context.Context, renewalID string, input model.RenewalPermitInput) (*model.Renewal, error) {
+	var licenseNumber *string
+	if input.LicenseNumber != nil {
+		if err := requireNonBlank("licenseNumber", *input.LicenseNumber); err != nil {
+			return nil, err
+		}
+		trimmed := strings.TrimSpace(*input.LicenseNumber)
+		licenseNumber = &trimmed
+	}
+	issuedDate, err := parseDate("issuedDate", input.IssuedDate)
+	if err != nil {
+		return nil, err
+	}
+	expirationDate, err := parseDate("expirationDate", input.ExpirationDate)
+	if err != nil {
+		return nil, err
+	}
+	if expirationDate < issuedDate {
+		return nil, validationError("expirationDate", "expirationDate must not be before issuedDate")
+	}
+
+	var renewal model.Renewal
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "renewals", "renewal", renewalID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		var current model.Renewal
+		err := tx.GetContext(ctx, &current, `SELECT `+renewalColumns+` FROM renewals WHERE id = $1 FOR UPDATE`, renewalID)
+		if err != nil {
+			return getOrNotFound(err, "renewal", renewalID)
+		}
+		if current.Status != model.RenewalStatusInProgress {
+			return conflictError("renewal %s is %s", renewalID, current.Status)
+		}
+		if expirationDate <= current.PreviousExpirationDate {
+			return validationError("expirationDate", "the renewed license must expire after %s", current.PreviousExpirationDate)
+		}
+		if licenseNumber != nil {
+			var license model.License
+			if err := tx.GetContext(ctx, &license, `SELECT `+licenseColumns+` FROM licenses WHERE id = $1`, current.LicenseID); err != nil {
+				return err
+			}
+			if err := checkLicenseNumber(ctx, tx, license.JurisdictionID, license.LicenseType, *licenseNumber); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE renewals
+			SET new_license_number = $2, new_issued_date = $3, new_expiration_date = $4,
+			    permit_recorded_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+		`, renewalID, licenseNumber, issuedDate, expirationDate)
+		if err != nil {
+			return err
+		}
+		if _, err := r.completeRenewalIfReady(ctx, tx, renewalID); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &renewal, `SELECT `+renewalColumns+` FROM renewals WHERE id = $1`, renewalID)
+	})
+	if err != nil {
+		return nil, dbError(err, "record renewal permit")
+	}
+	return &renewal, nil
+}
+
+// CreateRenewalTemplate is the resolver for the createRenewalTemplate field.
+func (r *mutationResolver) CreateRenewalTemplate(ctx context.Context, input model.CreateRenewalTemplateInput) (*model.RenewalTemplate, error) {
+	if err := requireNonBlank("name", input.Name); err != nil {
+		return nil, err
+	}
+	if err := checkTemplateItems(input.Items); err != nil {
+		return nil, err
+	}
+
+	var template model.RenewalTemplate
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := requireExists(ctx, tx, "jurisdictions", "jurisdiction", input.JurisdictionID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			DELETE FROM renewal_templates
+			WHERE jurisdiction_id = $1 AND license_type IS NOT DISTINCT FROM $2
+		`, input.JurisdictionID, input.LicenseType)
+		if err != nil {
+			return err
+		}
+		err = tx.GetContext(ctx, &template, `
+			INSERT INTO renewal_templates (jurisdiction_id, license_type, name)
+			VALUES ($1, $2, $3)
+			RETURNING `+renewalTemplateColumns,
+			input.JurisdictionID, input.LicenseType, input.Name)
+		if err != nil {
+			return err
+		}
+		for i, item := range input.Items {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO renewal_template_items (template_id, position, description, due_days_before, document_category)
+				VALUES ($1, $2, $3, $4, $5)
+			`, template.ID, i+1, item.Description, item.DueDaysBefore, item.DocumentCategory)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "create renewal template")
+	}
+	return &template, nil
+}
+
+// DeleteRenewalTemplate is the resolver for the deleteRenewalTemplate field.
+func (r *mutationResolver) DeleteRenewalTemplate(ctx context.Context, id string) (bool, error) {
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		return deleteRow(ctx, tx, "renewal_templates", "renewal template", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete renewal template")
+	}
+	return true, nil
+}
+
+// SetLicenseNumberFormats is the resolver for the setLicenseNumberFormats field.
+func (r *mutationResolver) SetLicenseNumberFormats(ctx context.Context, jurisdictionID string, formats []*model.LicenseNumberFormatInput) (*model.Jurisdiction, error) {
+	raw, err := parseFormatInputs(formats)
+	if err != nil {
+		return nil, err
+	}
+
+	var jurisdiction *model.Jurisdiction
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		row := tx.QueryRowContext(ctx, `
+			UPDATE jurisdictions SET license_number_formats = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+jurisdictionColumns, jurisdictionID, raw)
+		var err error
+		if jurisdiction, err = scanJurisdiction(row); err != nil {
+			return err
+		}
+		if jurisdiction == nil {
+			return notFoundError("jurisdiction", jurisdictionID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "set license number formats")
+	}
+	return jurisdiction, nil
 }
 
 // CreateDocument is the resolver for the createDocument field.
 func (r *mutationResolver) CreateDocument(ctx context.Context, input model.CreateDocumentInput) (*model.Document, error) {
-	panic(fmt.Errorf("not implemented: CreateDocument - createDocument"))
+	if err := requireNonBlank("name", input.Name); err != nil {
+		return nil, err
+	}
+	file, err := r.checkDocumentFile(input.File, input.FileURL, input.FileType)
+	if err != nil {
+		return nil, err
+	}
+	validity, err := parseValidity(input.ValidFrom, input.ValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	if input.LicenseID == nil && input.RenewalRequirementID == nil {
+		return nil, validationError("licenseId", "a document must be attached to a license or a renewal requirement")
+	}
+
+	var document *model.Document
+	var stored *storage.Object
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		uploadedByID, err := currentUserID(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if input.LicenseID != nil {
+			if _, err := r.requireEntityRole(ctx, tx, "licenses", "license", *input.LicenseID); err != nil {
+				return err
+			}
+		}
+		if input.RenewalRequirementID != nil {
+			if _, err := r.requireEntityRole(ctx, tx, "renewal_requirements", "renewal requirement", *input.RenewalRequirementID); err != nil {
+				return err
+			}
+			var licenseID string
+			err := tx.GetContext(ctx, &licenseID, "SELECT license_id FROM renewal_requirements WHERE id = $1", *input.RenewalRequirementID)
+			if err != nil {
+				return getOrNotFound(err, "renewal requirement", *input.RenewalRequirementID)
+			}
+			if input.LicenseID != nil && *input.LicenseID != licenseID {
+				return validationError("renewalRequirementId", "renewal requirement %s does not belong to license %s", *input.RenewalRequirementID, *input.LicenseID)
+			}
+		}
+
+		// Store the file only once the caller may attach it
+		document, stored, err = r.insertDocument(ctx, tx, documentRow{
+			name:                 input.Name,
+			description:          input.Description,
+			uploadedByID:         uploadedByID,
+			licenseID:            input.LicenseID,
+			renewalRequirementID: input.RenewalRequirementID,
+			category:             input.Category,
+			validity:             validity,
+		}, file)
+		return err
+	})
+	if err != nil {
+		if stored != nil {
+			r.discardStoredFile(stored.Key)
+		}
+		return nil, dbError(err, "create document")
+	}
+	return document, nil
+}
+
+// AddDocumentVersion is the resolver for the addDocumentVersion field.
+func (r *mutationResolver) AddDocumentVersion(ctx context.Context, documentID string, input model.DocumentVersionInput) (*model.Document, error) {
+	if input.Name != nil {
+		if err := requireNonBlank("name", *input.Name); err != nil {
+			return nil, err
+		}
+	}
+	file, err := r.checkDocumentFile(input.File, input.FileURL, input.FileType)
+	if err != nil {
+		return nil, err
+	}
+	validity, err := parseValidity(input.ValidFrom, input.ValidUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	var document *model.Document
+	var stored *storage.Object
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		uploadedByID, err := currentUserID(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if _, err := r.requireEntityRole(ctx, tx, "documents", "document", documentID); err != nil {
+			return err
+		}
+
+		// Lock the current version so concurrent uploads are numbered in turn
+		var current model.Document
+		err = tx.GetContext(ctx, &current, `
+			SELECT `+documentColumns+`
+			FROM documents
+			WHERE series_id = (SELECT series_id FROM documents WHERE id = $1)
+			  AND superseded_by_id IS NULL
+			ORDER BY version DESC
+			LIMIT 1
+			FOR UPDATE
+		`, documentID)
+		if err != nil {
+			return getOrNotFound(err, "document", documentID)
+		}
+
+		row := documentRow{
+			name:                 current.Name,
+			description:          current.Description,
+			uploadedByID:         uploadedByID,
+			licenseID:            current.LicenseID,
+			renewalRequirementID: current.RenewalRequirementID,
+			category:             current.Category,
+			validity:             validity,
+			seriesID:             &current.SeriesID,
+			version:              current.Version + 1,
+		}
+		if input.Name != nil {
+			row.name = *input.Name
+		}
+		if input.Description != nil {
+			row.description = input.Description
+		}
+		if input.Category != nil {
+			row.category = input.Category
+		}
+		document, stored, err = r.insertDocument(ctx, tx, row, file)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"UPDATE documents SET superseded_by_id = $1, updated_at = NOW() WHERE id = $2",
+			document.ID, current.ID)
+		return err
+	})
+	if err != nil {
+		if stored != nil {
+			r.discardStoredFile(stored.Key)
+		}
+		return nil, dbError(err, "add document version")
+	}
+	return document, nil
 }
 
 // DeleteDocument is the resolver for the deleteDocument field.
 func (r *mutationResolver) DeleteDocument(ctx context.Context, id string) (bool, error) {
-	panic(fmt.Errorf("not implemented: DeleteDocument - deleteDocument"))
+	var storageKey *string
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "documents", "document", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		// Relink the predecessor to the deleted version's successor so that
+		// the series keeps a single current version
+		_, err := tx.ExecContext(ctx, `
+			UPDATE documents p SET superseded_by_id = d.superseded_by_id, updated_at = NOW()
+			FROM documents d
+			WHERE d.id = $1 AND p.superseded_by_id = d.id
+		`, id)
+		if err != nil {
+			return err
+		}
+		err = tx.GetContext(ctx, &storageKey, "DELETE FROM documents WHERE id = $1 RETURNING storage_key", id)
+		return getOrNotFound(err, "document", id)
+	})
+	if err != nil {
+		return false, dbError(err, "delete document")
+	}
+	if storageKey != nil && r.Storage != nil {
+		r.discardStoredFile(*storageKey)
+	}
+	return true, nil
 }
 
-// MarkNotificationAsRead is the resolver for the markNotificationAsRead field.
-func (r *mutationResolver) MarkNotificationAsRead(ctx context.Context, id string) (*model.Notification, error) {
-	panic(fmt.Errorf("not implemented: MarkNotificationAsRead - markNotificationAsRead"))
+// GenerateReport is the resolver for the generateReport field.
+func (r *mutationResolver) GenerateReport(ctx context.Context, businessID string, locationID *string, format model.ReportFormat) (*model.ReportJob, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The generated file is kept in document storage
+	if r.Storage == nil {
+		return nil, validationError("", "reports are not enabled on this server")
+	}
+
+	var job model.ReportJob
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		if locationID != nil {
+			if err := requireLocationOfBusiness(ctx, tx, *locationID, businessID); err != nil {
+				return err
+			}
+		}
+		return tx.GetContext(ctx, &job, `
+			INSERT INTO report_jobs (business_id, location_id, format, requested_by_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+reportJobColumns,
+			businessID, locationID, format, viewer.ID)
+	})
+	if err != nil {
+		return nil, dbError(err, "generate report")
+	}
+	return &job, nil
 }
 
-// MarkAllNotificationsAsRead is the resolver for the markAllNotificationsAsRead field.
-func (r *mutationResolver) MarkAllNotificationsAsRead(ctx context.Context, userID string) (bool, error) {
-	panic(fmt.Errorf("not implemented: MarkAllNotificationsAsRead - markAllNotificationsAsRead"))
+// CreateCalendarFeed is the resolver for the createCalendarFeed field.
+func (r *mutationResolver) CreateCalendarFeed(ctx context.Context, businessID string, name *string) (*model.CalendarFeedSubscription, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if name != nil {
+		if err := requireNonBlank("name", *name); err != nil {
+			return nil, err
+		}
+	}
+	token, hash, err := calendar.NewToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar feed token: %w", err)
+	}
+
+	var feed model.CalendarFeed
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &feed, `
+			INSERT INTO calendar_feeds (user_id, business_id, name, token_hash)
+			SELECT $1, id, COALESCE($3, 'BudSafe - ' || name), $4 FROM businesses WHERE id = $2
+			RETURNING `+calendarFeedColumns,
+			viewer.ID, businessID, name, hash)
+	})
+	if err != nil {
+		return nil, dbError(err, "create calendar feed")
+	}
+	return &model.CalendarFeedSubscription{
+		Feed: &feed,
+		URL:  strings.TrimSuffix(r.PublicURL, "/") + "/calendar/" + token + ".ics",
+	}, nil
 }
 
-// NotificationUser is the resolver for the notificationUser field.
-func (r *notificationResolver) NotificationUser(ctx context.Context, obj *model.Notification) (*model.User, error) {
-	var user model.User
-	var createdAt, updatedAt sql.NullString
+// RevokeCalendarFeed is the resolver for the revokeCalendarFeed field.
+func (r *mutationResolver) RevokeCalendarFeed(ctx context.Context, id string) (*model.CalendarFeed, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.DB.QueryRow(`
-		SELECT id, email, first_name, last_name, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`, obj.UserID).Scan(
-		&user.ID,
-		&user.Email,
-		&user.FirstName,
-		&user.LastName,
-		&user.Role,
-		&createdAt,
-		&updatedAt,
-	)
+	var feed model.CalendarFeed
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.GetContext(ctx, &feed, `SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE id = $1 FOR UPDATE`, id)
+		if err != nil {
+			return getOrNotFound(err, "calendar feed", id)
+		}
+		if feed.UserID != viewer.ID && viewer.Role != model.UserRoleAdmin {
+			return notFoundError("calendar feed", id)
+		}
+		if feed.RevokedAt != nil {
+			return nil
+		}
+		return tx.GetContext(ctx, &feed, `
+			UPDATE calendar_feeds SET revoked_at = NOW(), updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+calendarFeedColumns, id)
+	})
+	if err != nil {
+		return nil, dbError(err, "revoke calendar feed")
+	}
+	return &feed, nil
+}
+
+// CreateWebhookSubscription is the resolver for the createWebhookSubscription field.
+func (r *mutationResolver) CreateWebhookSubscription(ctx context.Context, input model.CreateWebhookSubscriptionInput) (*model.WebhookSubscriptionSecret, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSpace(input.URL)
+	if err := r.checkWebhookURL(url); err != nil {
+		return nil, err
+	}
+	events, err := webhookEventNamesOf(input.EventTypes)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with id %s not found", obj.UserID)
+		return nil, err
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook secret: %w", err)
+	}
+
+	var subscription model.WebhookSubscription
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, input.BusinessID, model.UserRoleBusinessOwner); err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return tx.GetContext(ctx, &subscription, `
+			INSERT INTO webhook_subscriptions (business_id, url, secret, event_types, description, created_by_id)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+webhookSubscriptionColumns,
+			input.BusinessID, url, secret, events, input.Description, viewer.ID)
+	})
+	if err != nil {
+		return nil, dbError(err, "create webhook subscription")
 	}
+	return &model.WebhookSubscriptionSecret{Subscription: &subscription, Secret: secret}, nil
+}
 
-	if createdAt.Valid {
-		user.CreatedAt = createdAt.String
+// UpdateWebhookSubscription is the resolver for the updateWebhookSubscription field.
+func (r *mutationResolver) UpdateWebhookSubscription(ctx context.Context, id string, input model.UpdateWebhookSubscriptionInput) (*model.WebhookSubscription, error) {
+	if input.URL != nil {
+		url := strings.TrimSpace(*input.URL)
+		if err := r.checkWebhookURL(url); err != nil {
+			return nil, err
+		}
+		input.URL = &url
 	}
-	if updatedAt.Valid {
-		user.UpdatedAt = &updatedAt.String
+	var events pq.StringArray
+	if input.EventTypes != nil {
+		var err error
+		if events, err = webhookEventNamesOf(input.EventTypes); err != nil {
+			return nil, err
+		}
 	}
 
-	return &user, nil
+	var subscription model.WebhookSubscription
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "webhook_subscriptions", "webhook subscription", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return updateRow(ctx, tx, &subscription, "webhook_subscriptions", "webhook subscription", id, map[string]interface{}{
+			"url":         input.URL,
+			"event_types": events,
+			"description": input.Description,
+			"active":      input.Active,
+		}, webhookSubscriptionColumns)
+	})
+	if err != nil {
+		return nil, dbError(err, "update webhook subscription")
+	}
+	return &subscription, nil
 }
 
-// Me is the resolver for the me field.
-func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
-	// In a real app, you'd get the user ID from authentication context
-	// For now, return the first user
-	var user model.User
-	err := r.DB.Get(&user, `
-		SELECT id, email, first_name, last_name, role, 
-		       firebase_uid, created_at::text, updated_at::text 
-		FROM users 
-		LIMIT 1
-	`)
+// DeleteWebhookSubscription is the resolver for the deleteWebhookSubscription field.
+func (r *mutationResolver) DeleteWebhookSubscription(ctx context.Context, id string) (bool, error) {
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "webhook_subscriptions", "webhook subscription", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return deleteRow(ctx, tx, "webhook_subscriptions", "webhook subscription", id)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get current user: %v", err)
+		return false, dbError(err, "delete webhook subscription")
 	}
-	return &user, nil
+	return true, nil
 }
 
-// User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
-	var user model.User
-	var createdAt, updatedAt sql.NullString
+// RotateWebhookSecret is the resolver for the rotateWebhookSecret field.
+func (r *mutationResolver) RotateWebhookSecret(ctx context.Context, id string) (*model.WebhookSubscriptionSecret, error) {
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook secret: %w", err)
+	}
 
-	err := r.DB.QueryRow(`
-		SELECT id, email, first_name, last_name, role, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.FirstName,
-		&user.LastName,
-		&user.Role,
-		&createdAt,
-		&updatedAt,
-	)
+	var subscription model.WebhookSubscription
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "webhook_subscriptions", "webhook subscription", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &subscription, `
+			UPDATE webhook_subscriptions SET secret = $2, updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+webhookSubscriptionColumns, id, secret)
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user with id %s not found", id)
+		return nil, dbError(err, "rotate webhook secret")
+	}
+	return &model.WebhookSubscriptionSecret{Subscription: &subscription, Secret: secret}, nil
+}
+
+// RetryWebhookDelivery is the resolver for the retryWebhookDelivery field.
+func (r *mutationResolver) RetryWebhookDelivery(ctx context.Context, id string) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "webhook_deliveries", "webhook delivery", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		err := tx.GetContext(ctx, &delivery, `
+			UPDATE webhook_deliveries
+			SET status = CASE WHEN attempts = 0 THEN 'PENDING' ELSE 'RETRYING' END,
+			    next_attempt_at = NOW(), updated_at = NOW()
+			WHERE id = $1 AND status <> 'SUCCEEDED'
+			RETURNING `+webhookDeliveryColumns, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return conflictError("webhook delivery %s has already succeeded", id)
 		}
-		return nil, fmt.Errorf("failed to get user: %v", err)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "retry webhook delivery")
+	}
+	return &delivery, nil
+}
+
+// MarkNotificationAsRead is the resolver for the markNotificationAsRead field.
+func (r *mutationResolver) MarkNotificationAsRead(ctx context.Context, id string) (*model.Notification, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if createdAt.Valid {
-		user.CreatedAt = createdAt.String
+	var notification model.Notification
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		var ownerID string
+		err := tx.GetContext(ctx, &ownerID, "SELECT user_id FROM notifications WHERE id = $1 FOR UPDATE", id)
+		if err != nil {
+			return getOrNotFound(err, "notification", id)
+		}
+		if ownerID != viewer.ID && viewer.Role != model.UserRoleAdmin {
+			return notFoundError("notification", id)
+		}
+
+		err = tx.GetContext(ctx, &notification, `
+			UPDATE notifications
+			SET is_read = TRUE, updated_at = NOW()
+			WHERE id = $1
+			RETURNING `+notificationColumns, id)
+		return getOrNotFound(err, "notification", id)
+	})
+	if err != nil {
+		return nil, dbError(err, "mark notification as read")
 	}
-	if updatedAt.Valid {
-		user.UpdatedAt = &updatedAt.String
+	return &notification, nil
+}
+
+// MarkAllNotificationsAsRead is the resolver for the markAllNotificationsAsRead field.
+func (r *mutationResolver) MarkAllNotificationsAsRead(ctx context.Context, userID string) (bool, error) {
+	if _, err := r.requireSelfOrAdmin(ctx, userID); err != nil {
+		return false, err
+	}
+
+	err := r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := requireExists(ctx, tx, "users", "user", userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			UPDATE notifications
+			SET is_read = TRUE, updated_at = NOW()
+			WHERE user_id = $1 AND NOT is_read
+		`, userID)
+		return err
+	})
+	if err != nil {
+		return false, dbError(err, "mark notifications as read")
+	}
+	return true, nil
+}
+
+// UpdateNotificationPreferences is the resolver for the updateNotificationPreferences field.
+func (r *mutationResolver) UpdateNotificationPreferences(ctx context.Context, userID string, input model.NotificationPreferencesInput) (*model.NotificationPreferences, error) {
+	if _, err := r.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+	prefs, err := parseNotificationPreferences(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.withTx(ctx, func(tx *sqlx.Tx) error {
+		if err := requireExists(ctx, tx, "users", "user", userID); err != nil {
+			return err
+		}
+		return notify.Save(ctx, tx, userID, prefs)
+	})
+	if err != nil {
+		return nil, dbError(err, "update notification preferences")
+	}
+	return notificationPreferences(userID, prefs), nil
+}
+
+// NotificationUser is the resolver for the notificationUser field.
+func (r *notificationResolver) NotificationUser(ctx context.Context, obj *model.Notification) (*model.User, error) {
+	return r.getUser(ctx, obj.UserID)
+}
+
+// Delivery is the resolver for the delivery field.
+func (r *notificationResolver) Delivery(ctx context.Context, obj *model.Notification) (*model.NotificationDelivery, error) {
+	var delivery model.NotificationDelivery
+	err := r.DB.GetContext(ctx, &delivery, `
+		SELECT `+notificationDeliveryColumns+`
+		FROM notification_deliveries
+		WHERE notification_id = $1 AND channel = 'EMAIL'
+	`, obj.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification delivery: %v", err)
+	}
+	return &delivery, nil
+}
+
+// DeliveryStatus is the resolver for the deliveryStatus field.
+func (r *notificationResolver) DeliveryStatus(ctx context.Context, obj *model.Notification) (*model.DeliveryStatus, error) {
+	delivery, err := r.Delivery(ctx, obj)
+	if err != nil || delivery == nil {
+		return nil, err
+	}
+	return &delivery.Status, nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	if auth.ForContext(ctx) == nil {
+		return nil, unauthenticatedError("authentication required")
+	}
+	return r.viewer(ctx)
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	var user model.User
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireColleague(ctx, tx, id); err != nil {
+			return err
+		}
+		err := tx.GetContext(ctx, &user, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+		return getOrNotFound(err, "user", id)
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return &user, nil
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
-	var users []*model.User
-
-	query := `
-		SELECT 
-			id,
-			email,
-			first_name,
-			last_name,
-			role,
-			created_at
-		FROM users
-		ORDER BY created_at DESC
-	`
-
-	rows, err := r.DB.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to query users: %w", err)
+func (r *queryResolver) Users(ctx context.Context, first *int, after *string, last *int, before *string, orderBy *model.UserOrder) (*model.UserConnection, error) {
+	key, desc := userOrderKeys[model.UserOrderFieldCreatedAt], true
+	if orderBy != nil {
+		key, desc = userOrderKeys[orderBy.Field], orderDirection(orderBy.Direction)
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var user model.User
-		var createdAt time.Time
-
-		err := rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.FirstName,
-			&user.LastName,
-			&user.Role,
-			&createdAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
-		}
 
-		user.CreatedAt = createdAt.Format(time.RFC3339)
-		users = append(users, &user)
+	page, err := listQuery[model.User]{
+		from:    "users",
+		columns: userColumns,
+		key:     key,
+		desc:    desc,
+		id:      func(u *model.User) string { return u.ID },
+		scan:    structScan[model.User],
+	}.fetch(ctx, r.DB, pageArgs{first, after, last, before})
+	if err != nil {
+		return nil, dbError(err, "query users")
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating users: %w", err)
+	conn := &model.UserConnection{Edges: make([]*model.UserEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.UserEdge{Cursor: page.Cursors[i], Node: node})
 	}
-
-	return users, nil
+	return conn, nil
 }
 
 // Business is the resolver for the business field.
 func (r *queryResolver) Business(ctx context.Context, id string) (*model.Business, error) {
 	var business model.Business
-	var createdAt, updatedAt sql.NullString
-
-	err := r.DB.QueryRow(`
-		SELECT id, name, type, description, owner_id, created_at::text, updated_at::text 
-		FROM businesses 
-		WHERE id = $1
-	`, id).Scan(
-		&business.ID,
-		&business.Name,
-		&business.Type,
-		&business.Description,
-		&business.OwnerID,
-		&createdAt,
-		&updatedAt,
-	)
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &business, `
+			SELECT `+businessColumns+`
+			FROM businesses
+			WHERE id = $1 AND app_can_access_business(id)
+		`, id)
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("business with id %s not found", id)
-		}
-		return nil, fmt.Errorf("failed to get business: %v", err)
-	}
-
-	if createdAt.Valid {
-		business.CreatedAt = createdAt.String
-	}
-	if updatedAt.Valid {
-		business.UpdatedAt = &updatedAt.String
+		return nil, getOrNotFound(err, "business", id)
 	}
-
 	return &business, nil
 }
 
 // Businesses is the resolver for the businesses field.
-func (r *queryResolver) Businesses(ctx context.Context, filter *model.BusinessFilter) ([]*model.Business, error) {
-	query := `
-		SELECT id, name, type, description, owner_id, created_at::text, updated_at::text 
-		FROM businesses
-	`
-	args := []interface{}{}
-
-	if filter != nil && filter.Type != nil {
-		query += " WHERE type = $1"
-		args = append(args, *filter.Type)
+func (r *queryResolver) Businesses(ctx context.Context, filter *model.BusinessFilter, first *int, after *string, last *int, before *string, orderBy *model.BusinessOrder) (*model.BusinessConnection, error) {
+	key, desc := businessOrderKeys[model.BusinessOrderFieldCreatedAt], true
+	if orderBy != nil {
+		key, desc = businessOrderKeys[orderBy.Field], orderDirection(orderBy.Direction)
 	}
 
-	query += " ORDER BY created_at DESC"
+	where, err := businessFilterWhere(filter)
+	if err != nil {
+		return nil, err
+	}
 
-	var businesses []*model.Business
-	err := r.DB.Select(&businesses, query, args...)
+	var page *pageOf[model.Business]
+	err = r.withReadTx(ctx, func(tx *sqlx.Tx) (err error) {
+		page, err = listQuery[model.Business]{
+			from:    "businesses",
+			columns: businessColumns,
+			where:   where,
+			key:     key,
+			desc:    desc,
+			id:      func(b *model.Business) string { return b.ID },
+			scan:    structScan[model.Business],
+		}.fetch(ctx, tx, pageArgs{first, after, last, before})
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get businesses: %v", err)
+		return nil, dbError(err, "query businesses")
 	}
-	return businesses, nil
+
+	conn := &model.BusinessConnection{Edges: make([]*model.BusinessEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.BusinessEdge{Cursor: page.Cursors[i], Node: node})
+	}
+	return conn, nil
 }
 
 // License is the resolver for the license field.
 func (r *queryResolver) License(ctx context.Context, id string) (*model.License, error) {
 	var license model.License
-	err := r.DB.Get(&license, `
-		SELECT id, business_id, jurisdiction_id, location_id, 
-		       license_number, type, status, issued_date::text, 
-		       expiration_date::text, renewal_date::text, fee_amount, 
-		       notes, created_at::text, updated_at::text 
-		FROM licenses 
-		WHERE id = $1
-	`, id)
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &license, `
+			SELECT `+licenseColumns+`
+			FROM licenses
+			WHERE id = $1 AND app_can_access_business(business_id)
+		`, id)
+	})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("license with id %s not found", id)
-		}
-		return nil, fmt.Errorf("failed to get license: %v", err)
+		return nil, getOrNotFound(err, "license", id)
 	}
 	return &license, nil
 }
 
 // Licenses is the resolver for the licenses field.
-func (r *queryResolver) Licenses(ctx context.Context, filter *model.License) ([]*model.License, error) {
-	query := `
-		SELECT id, business_id, jurisdiction_id, location_id, 
-		       license_number, type, status, issued_date::text, 
-		       expiration_date::text, renewal_date::text, fee_amount, 
-		       notes, created_at::text, updated_at::text
-		FROM licenses
-		ORDER BY created_at DESC
-	`
+func (r *queryResolver) Licenses(ctx context.Context, filter *model.LicenseFilter, first *int, after *string, last *int, before *string, orderBy *model.LicenseOrder) (*model.LicenseConnection, error) {
+	key, desc := licenseOrderKeys[model.LicenseOrderFieldCreatedAt], true
+	if orderBy != nil {
+		key, desc = licenseOrderKeys[orderBy.Field], orderDirection(orderBy.Direction)
+	}
 
-	rows, err := r.DB.Query(query)
+	where, err := licenseFilterWhere(filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query licenses: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	var licenses []*model.License
-	for rows.Next() {
-		license, err := scanLicense(rows)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan license: %w", err)
-		}
-		licenses = append(licenses, license)
+	var page *pageOf[model.License]
+	err = r.withReadTx(ctx, func(tx *sqlx.Tx) (err error) {
+		page, err = listQuery[model.License]{
+			from:    "licenses",
+			columns: licenseColumns,
+			where:   where,
+			key:     key,
+			desc:    desc,
+			id:      func(l *model.License) string { return l.ID },
+			scan:    structScan[model.License],
+		}.fetch(ctx, tx, pageArgs{first, after, last, before})
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "query licenses")
 	}
 
-	return licenses, nil
+	conn := &model.LicenseConnection{Edges: make([]*model.LicenseEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.LicenseEdge{Cursor: page.Cursors[i], Node: node})
+	}
+	return conn, nil
 }
 
 // ExpiringLicenses is the resolver for the expiringLicenses field.
 func (r *queryResolver) ExpiringLicenses(ctx context.Context, days int) ([]*model.License, error) {
+	if days < 0 {
+		return nil, validationError("days", "days must not be negative")
+	}
+
 	var licenses []*model.License
-	err := r.DB.Select(&licenses, `
-    SELECT id, business_id, jurisdiction_id, location_id, 
-           license_number, type, status, issued_date::text, 
-           expiration_date::text, renewal_date::text, fee_amount, 
-           notes, created_at::text, updated_at::text 
-    FROM licenses 
-    WHERE expiration_date <= CURRENT_DATE + ($1 || ' days')::interval
-      AND status IN ('ACTIVE', 'EXPIRING')
-    ORDER BY expiration_date ASC
-`, days)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get expiring licenses: %v", err)
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &licenses, `
+			SELECT `+licenseColumns+`
+			FROM licenses
+			WHERE expiration_date <= CURRENT_DATE + $1::int
+			  AND status IN ('ACTIVE', 'RENEWAL_IN_PROGRESS')
+			  AND app_can_access_business(business_id)
+			ORDER BY expiration_date ASC
+		`, days)
+	})
+	if err != nil {
+		return nil, dbError(err, "get expiring licenses")
 	}
 	return licenses, nil
 }
@@ -472,7 +1991,7 @@ func (r *queryResolver) ExpiringLicenses(ctx context.Context, days int) ([]*mode
 // Jurisdiction is the resolver for the jurisdiction field.
 func (r *queryResolver) Jurisdiction(ctx context.Context, id string) (*model.Jurisdiction, error) {
 	row := r.DB.QueryRow(`
-		SELECT id, name, type, country, regulatory_body, regulatory_website, license_types, created_at::text, updated_at::text 
+		SELECT `+jurisdictionColumns+`
 		FROM jurisdictions 
 		WHERE id = $1
 	`, id)
@@ -480,71 +1999,93 @@ func (r *queryResolver) Jurisdiction(ctx context.Context, id string) (*model.Jur
 }
 
 // Jurisdictions is the resolver for the jurisdictions field.
-func (r *queryResolver) Jurisdictions(ctx context.Context) ([]*model.Jurisdiction, error) {
-	rows, err := r.DB.Query(`
-		SELECT id, name, type, country, regulatory_body, regulatory_website, license_types, created_at::text, updated_at::text 
-		FROM jurisdictions 
-		ORDER BY name ASC
-	`)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get jurisdictions: %v", err)
+func (r *queryResolver) Jurisdictions(ctx context.Context, first *int, after *string, last *int, before *string, orderBy *model.JurisdictionOrder) (*model.JurisdictionConnection, error) {
+	key, desc := jurisdictionOrderKeys[model.JurisdictionOrderFieldName], false
+	if orderBy != nil {
+		key, desc = jurisdictionOrderKeys[orderBy.Field], orderDirection(orderBy.Direction)
 	}
-	defer rows.Close()
 
-	var jurisdictions []*model.Jurisdiction
-	for rows.Next() {
-		jurisdiction, err := scanJurisdiction(rows)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan jurisdiction: %v", err)
-		}
-		jurisdictions = append(jurisdictions, jurisdiction)
+	page, err := listQuery[model.Jurisdiction]{
+		from:    "jurisdictions",
+		columns: jurisdictionColumns,
+		key:     key,
+		desc:    desc,
+		id:      func(j *model.Jurisdiction) string { return j.ID },
+		scan:    scanJurisdictionRow,
+	}.fetch(ctx, r.DB, pageArgs{first, after, last, before})
+	if err != nil {
+		return nil, dbError(err, "query jurisdictions")
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating jurisdictions: %v", err)
+	conn := &model.JurisdictionConnection{Edges: make([]*model.JurisdictionEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.JurisdictionEdge{Cursor: page.Cursors[i], Node: node})
 	}
+	return conn, nil
+}
 
-	return jurisdictions, nil
+// ValidateLicenseNumber is the resolver for the validateLicenseNumber field.
+func (r *queryResolver) ValidateLicenseNumber(ctx context.Context, jurisdictionID string, licenseType model.LicenseType, number string) (*model.LicenseNumberValidation, error) {
+	number = strings.TrimSpace(number)
+	validation := &model.LicenseNumberValidation{LicenseNumber: number, Errors: []string{}}
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		formats, err := jurisdictionFormats(ctx, tx, jurisdictionID)
+		if err != nil {
+			return err
+		}
+		format := formats.For(string(licenseType))
+		if validation.Format, err = licenseNumberFormat(format); err != nil {
+			return err
+		}
+		if number == "" {
+			validation.Errors = append(validation.Errors, "licenseNumber must not be blank")
+		} else if format != nil {
+			validation.Errors = append(validation.Errors, format.Validate(number)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, dbError(err, "validate license number")
+	}
+	validation.Valid = len(validation.Errors) == 0
+	return validation, nil
 }
 
 // ComplianceChecks is the resolver for the complianceChecks field.
-func (r *queryResolver) ComplianceChecks(ctx context.Context, licenseID string) ([]*model.ComplianceCheck, error) {
-	query := `
-		SELECT 
-		    id, 
-		    license_id, 
-		    check_type,
-		    status, 
-		    checked_at,
-		    next_check_date,
-		    notes, 
-		    checked_by_id,
-		    created_at, 
-		    updated_at 
-		FROM compliance_checks 
-		WHERE license_id = $1
-		ORDER BY next_check_date DESC
-	`
+func (r *queryResolver) ComplianceChecks(ctx context.Context, licenseID string, first *int, after *string, last *int, before *string, orderBy *model.ComplianceCheckOrder) (*model.ComplianceCheckConnection, error) {
+	key, desc := complianceCheckOrderKeys[model.ComplianceCheckOrderFieldDueDate], true
+	if orderBy != nil {
+		key, desc = complianceCheckOrderKeys[orderBy.Field], orderDirection(orderBy.Direction)
+	}
 
-	var checks []*model.ComplianceCheck
+	var where whereClause
+	where.add("license_id = ?", licenseID)
 
-	err := r.DB.SelectContext(ctx, &checks, query, licenseID)
+	var page *pageOf[model.ComplianceCheck]
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) (err error) {
+		if _, err := r.requireEntityRole(ctx, tx, "licenses", "license", licenseID); err != nil {
+			return err
+		}
+		page, err = listQuery[model.ComplianceCheck]{
+			from:    "compliance_checks",
+			columns: complianceCheckColumns,
+			where:   where,
+			key:     key,
+			desc:    desc,
+			id:      func(c *model.ComplianceCheck) string { return c.ID },
+			scan:    structScan[model.ComplianceCheck],
+		}.fetch(ctx, tx, pageArgs{first, after, last, before})
+		return err
+	})
 	if err != nil {
-		fmt.Printf("Error querying compliance checks for licenseID %s: %v\n", licenseID, err)
-		return nil, fmt.Errorf("failed to query compliance checks: %v", err)
+		return nil, dbError(err, "query compliance checks")
 	}
 
-	// Defensive: Remove any compliance checks with empty or obviously invalid LicenseID
-	validChecks := make([]*model.ComplianceCheck, 0, len(checks))
-	for _, check := range checks {
-		if check.LicenseID != "" {
-			validChecks = append(validChecks, check)
-		} else {
-			fmt.Printf("Skipping ComplianceCheck with empty LicenseID: %+v\n", check)
-		}
+	conn := &model.ComplianceCheckConnection{Edges: make([]*model.ComplianceCheckEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.ComplianceCheckEdge{Cursor: page.Cursors[i], Node: node})
 	}
-
-	return validChecks, nil
+	return conn, nil
 }
 
 // ComplianceStatus is the resolver for the complianceStatus field.
@@ -554,7 +2095,11 @@ func (r *queryResolver) ComplianceStatus(ctx context.Context, businessID string)
 	}
 
 	// Get compliance counts for all licenses of this business
-	err := r.DB.Get(summary, `
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, summary, `
 		SELECT 
 			COUNT(CASE WHEN cc.status = 'COMPLIANT' THEN 1 END) as compliant_count,
 			COUNT(CASE WHEN cc.status = 'NON_COMPLIANT' THEN 1 END) as non_compliant_count,
@@ -564,8 +2109,9 @@ func (r *queryResolver) ComplianceStatus(ctx context.Context, businessID string)
 		JOIN licenses l ON cc.license_id = l.id
 		WHERE l.business_id = $1
 	`, businessID)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get compliance status: %v", err)
+		return nil, dbError(err, "get compliance status")
 	}
 
 	// Determine overall status
@@ -580,21 +2126,266 @@ func (r *queryResolver) ComplianceStatus(ctx context.Context, businessID string)
 	return summary, nil
 }
 
+// ComplianceSchedules is the resolver for the complianceSchedules field.
+func (r *queryResolver) ComplianceSchedules(ctx context.Context, licenseID string) ([]*model.ComplianceSchedule, error) {
+	schedules := []*model.ComplianceSchedule{}
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "licenses", "license", licenseID); err != nil {
+			return err
+		}
+		return tx.SelectContext(ctx, &schedules, `
+			SELECT `+complianceScheduleColumns+`
+			FROM compliance_schedules
+			WHERE license_id = $1
+			ORDER BY created_at ASC, id ASC
+		`, licenseID)
+	})
+	if err != nil {
+		return nil, dbError(err, "query compliance schedules")
+	}
+	return schedules, nil
+}
+
+// UpcomingComplianceChecks is the resolver for the upcomingComplianceChecks field.
+func (r *queryResolver) UpcomingComplianceChecks(ctx context.Context, businessID string, until string, limit int) ([]*model.ScheduledComplianceCheck, error) {
+	untilTime, err := parseDateTime("until", until)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireLimit(limit); err != nil {
+		return nil, err
+	}
+
+	var upcoming []*model.ScheduledComplianceCheck
+	err = r.withReadTx(ctx, func(tx *sqlx.Tx) (err error) {
+		if err := r.requireBusinessRole(ctx, tx, businessID); err != nil {
+			return err
+		}
+		upcoming, err = upcomingComplianceChecks(ctx, tx, businessID, time.Now(), untilTime, limit)
+		return err
+	})
+	if err != nil {
+		return nil, dbError(err, "query upcoming compliance checks")
+	}
+	return upcoming, nil
+}
+
+// Renewal is the resolver for the renewal field.
+func (r *queryResolver) Renewal(ctx context.Context, id string) (*model.Renewal, error) {
+	var renewal model.Renewal
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "renewals", "renewal", id); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &renewal, `SELECT `+renewalColumns+` FROM renewals WHERE id = $1`, id)
+	})
+	if err != nil {
+		return nil, getOrNotFound(err, "renewal", id)
+	}
+	return &renewal, nil
+}
+
+// RenewalTemplates is the resolver for the renewalTemplates field.
+func (r *queryResolver) RenewalTemplates(ctx context.Context, jurisdictionID string) ([]*model.RenewalTemplate, error) {
+	templates := []*model.RenewalTemplate{}
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &templates, `
+			SELECT `+renewalTemplateColumns+`
+			FROM renewal_templates
+			WHERE jurisdiction_id = $1
+			ORDER BY license_type NULLS LAST
+		`, jurisdictionID)
+	})
+	if err != nil {
+		return nil, dbError(err, "query renewal templates")
+	}
+	return templates, nil
+}
+
+// CalendarFeeds is the resolver for the calendarFeeds field.
+func (r *queryResolver) CalendarFeeds(ctx context.Context, businessID string) ([]*model.CalendarFeed, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds := []*model.CalendarFeed{}
+	err = r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &feeds, `
+			SELECT `+calendarFeedColumns+`
+			FROM calendar_feeds
+			WHERE user_id = $1 AND business_id = $2
+			ORDER BY created_at
+		`, viewer.ID, businessID)
+	})
+	if err != nil {
+		return nil, dbError(err, "query calendar feeds")
+	}
+	return feeds, nil
+}
+
+// WebhookSubscriptions is the resolver for the webhookSubscriptions field.
+func (r *queryResolver) WebhookSubscriptions(ctx context.Context, businessID string) ([]*model.WebhookSubscription, error) {
+	subscriptions := []*model.WebhookSubscription{}
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return tx.SelectContext(ctx, &subscriptions, `
+			SELECT `+webhookSubscriptionColumns+`
+			FROM webhook_subscriptions
+			WHERE business_id = $1
+			ORDER BY created_at
+		`, businessID)
+	})
+	if err != nil {
+		return nil, dbError(err, "query webhook subscriptions")
+	}
+	return subscriptions, nil
+}
+
+// WebhookSubscription is the resolver for the webhookSubscription field.
+func (r *queryResolver) WebhookSubscription(ctx context.Context, id string) (*model.WebhookSubscription, error) {
+	var subscription model.WebhookSubscription
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "webhook_subscriptions", "webhook subscription", id, model.UserRoleBusinessOwner); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &subscription, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id)
+	})
+	if err != nil {
+		return nil, getOrNotFound(err, "webhook subscription", id)
+	}
+	return &subscription, nil
+}
+
+// ReportJobs is the resolver for the reportJobs field.
+func (r *queryResolver) ReportJobs(ctx context.Context, businessID string) ([]*model.ReportJob, error) {
+	jobs := []*model.ReportJob{}
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		return tx.SelectContext(ctx, &jobs, `
+			SELECT `+reportJobColumns+`
+			FROM report_jobs
+			WHERE business_id = $1
+			ORDER BY created_at DESC
+		`, businessID)
+	})
+	if err != nil {
+		return nil, dbError(err, "query report jobs")
+	}
+	return jobs, nil
+}
+
+// ReportJob is the resolver for the reportJob field.
+func (r *queryResolver) ReportJob(ctx context.Context, id string) (*model.ReportJob, error) {
+	var job model.ReportJob
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := r.requireEntityRole(ctx, tx, "report_jobs", "report job", id, model.UserRoleBusinessOwner, model.UserRoleComplianceManager); err != nil {
+			return err
+		}
+		return tx.GetContext(ctx, &job, `SELECT `+reportJobColumns+` FROM report_jobs WHERE id = $1`, id)
+	})
+	if err != nil {
+		return nil, getOrNotFound(err, "report job", id)
+	}
+	return &job, nil
+}
+
+// AuditTrail is the resolver for the auditTrail field.
+func (r *queryResolver) AuditTrail(ctx context.Context, entityType string, entityID string) ([]*model.AuditEvent, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(auditEntityTypes, entityType) {
+		return nil, validationError("entityType", "unknown entity type %q", entityType)
+	}
+
+	events := []*model.AuditEvent{}
+	err = r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		err := tx.SelectContext(ctx, &events, `
+			SELECT `+auditEventColumns+`
+			FROM audit_events
+			WHERE entity_type = $1 AND entity_id = $2
+			ORDER BY seq
+		`, entityType, entityID)
+		if err != nil {
+			return dbError(err, "query audit trail")
+		}
+		if viewer.Role == model.UserRoleAdmin {
+			return nil
+		}
+
+		// The record may have moved between businesses; every one of them
+		// must be the viewer's
+		businessIDs, global := auditEntityBusinesses(events)
+		if global {
+			return forbiddenError("only an ADMIN can view the audit trail of %s records", entityType)
+		}
+		for _, businessID := range businessIDs {
+			if err := r.requireBusinessRole(ctx, tx, businessID, model.UserRoleComplianceManager); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
 // Notifications is the resolver for the notifications field.
-func (r *queryResolver) Notifications(ctx context.Context, userID string) ([]*model.Notification, error) {
-	var notifications []*model.Notification
-	err := r.DB.Select(&notifications, `
-		SELECT id, user_id, title, message, type, is_read, 
-		       related_entity_id, related_entity_type, created_at::text 
-		FROM notifications 
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT 50
-	`, userID)
+func (r *queryResolver) Notifications(ctx context.Context, userID string, first *int, after *string, last *int, before *string) (*model.NotificationConnection, error) {
+	if _, err := r.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var where whereClause
+	where.add("user_id = ?", userID)
+
+	page, err := listQuery[model.Notification]{
+		from:    "notifications",
+		columns: notificationColumns,
+		where:   where,
+		key:     notificationOrderKey,
+		desc:    true,
+		id:      func(n *model.Notification) string { return n.ID },
+		scan:    structScan[model.Notification],
+	}.fetch(ctx, r.DB, pageArgs{first, after, last, before})
+	if err != nil {
+		return nil, dbError(err, "query notifications")
+	}
+
+	conn := &model.NotificationConnection{Edges: make([]*model.NotificationEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.NotificationEdge{Cursor: page.Cursors[i], Node: node})
+	}
+	return conn, nil
+}
+
+// NotificationPreferences is the resolver for the notificationPreferences field.
+func (r *queryResolver) NotificationPreferences(ctx context.Context, userID string) (*model.NotificationPreferences, error) {
+	if _, err := r.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var prefs map[string]*notify.Preferences
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if err := requireExists(ctx, tx, "users", "user", userID); err != nil {
+			return err
+		}
+		var err error
+		prefs, err = notify.Load(ctx, tx, userID)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get notifications: %v", err)
+		return nil, dbError(err, "query notification preferences")
 	}
-	return notifications, nil
+	return notificationPreferences(userID, prefs[userID]), nil
 }
 
 // DashboardSummary is the resolver for the dashboardSummary field.
@@ -602,98 +2393,325 @@ func (r *queryResolver) DashboardSummary(ctx context.Context, businessID string)
 	summary := &model.DashboardSummary{
 		BusinessID: businessID,
 	}
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		if err := r.requireBusinessRole(ctx, tx, businessID); err != nil {
+			return err
+		}
+		viewer, err := r.requireViewer(ctx)
+		if err != nil {
+			return err
+		}
 
-	// Get active licenses count
-	err := r.DB.Get(&summary.ActiveLicenses, `
-		SELECT COUNT(*) FROM licenses WHERE business_id = $1 AND status = 'ACTIVE'
-	`, businessID)
+		err = tx.GetContext(ctx, &summary.ActiveLicenses, `
+			SELECT COUNT(*) FROM licenses WHERE business_id = $1 AND status = 'ACTIVE'
+		`, businessID)
+		if err != nil {
+			return dbError(err, "count active licenses")
+		}
+
+		// Licenses expiring within 30 days
+		err = tx.GetContext(ctx, &summary.ExpiringLicenses, `
+			SELECT COUNT(*) FROM licenses
+			WHERE business_id = $1
+			  AND expiration_date <= CURRENT_DATE + INTERVAL '30 days'
+			  AND status IN ('ACTIVE', 'EXPIRING')
+		`, businessID)
+		if err != nil {
+			return dbError(err, "count expiring licenses")
+		}
+
+		err = tx.GetContext(ctx, &summary.ComplianceIssues, `
+			SELECT COUNT(*) FROM compliance_checks cc
+			JOIN licenses l ON cc.license_id = l.id
+			WHERE l.business_id = $1 AND cc.status IN ('NON_COMPLIANT', 'NEEDS_ATTENTION')
+		`, businessID)
+		if err != nil {
+			return dbError(err, "count compliance issues")
+		}
+
+		// Renewal requirements due within 30 days
+		err = tx.GetContext(ctx, &summary.UpcomingRenewals, `
+			SELECT COUNT(*) FROM renewal_requirements rr
+			JOIN licenses l ON rr.license_id = l.id
+			WHERE l.business_id = $1
+			  AND rr.due_date <= CURRENT_DATE + INTERVAL '30 days'
+			  AND rr.completed_at IS NULL
+		`, businessID)
+		if err != nil {
+			return dbError(err, "count upcoming renewals")
+		}
+
+		// Notifications are the viewer's own, even for ADMINs
+		err = tx.SelectContext(ctx, &summary.RecentNotifications, `
+			SELECT `+notificationColumns+` FROM notifications
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT 5
+		`, viewer.ID)
+		return dbError(err, "list recent notifications")
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get active licenses count: %v", err)
+		return nil, err
 	}
+	return summary, nil
+}
 
-	// Get expiring licenses count (within 30 days)
-	err = r.DB.Get(&summary.ExpiringLicenses, `
-		SELECT COUNT(*) FROM licenses 
-		WHERE business_id = $1 
-		  AND expiration_date <= CURRENT_DATE + INTERVAL '30 days'
-		  AND status IN ('ACTIVE', 'EXPIRING')
-	`, businessID)
+// Hello is the resolver for the hello field.
+func (r *queryResolver) Hello(ctx context.Context) (string, error) {
+	return "Hello, BudSafe User with Real Database!", nil
+}
+
+// License is the resolver for the license field.
+func (r *renewalResolver) License(ctx context.Context, obj *model.Renewal) (*model.License, error) {
+	return r.getLicense(ctx, obj.LicenseID)
+}
+
+// Requirements is the resolver for the requirements field.
+func (r *renewalResolver) Requirements(ctx context.Context, obj *model.Renewal) ([]*model.RenewalRequirement, error) {
+	requirements := []*model.RenewalRequirement{}
+	err := r.DB.SelectContext(ctx, &requirements, `
+		SELECT `+renewalRequirementColumns+`
+		FROM renewal_requirements
+		WHERE renewal_id = $1
+		ORDER BY due_date ASC NULLS LAST, created_at ASC, id ASC
+	`, obj.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get expiring licenses count: %v", err)
+		return nil, fmt.Errorf("failed to get renewal requirements: %v", err)
 	}
+	return requirements, nil
+}
 
-	// Get compliance issues count
-	err = r.DB.Get(&summary.ComplianceIssues, `
-		SELECT COUNT(*) FROM compliance_checks cc
-		JOIN licenses l ON cc.license_id = l.id
-		WHERE l.business_id = $1 AND cc.status IN ('NON_COMPLIANT', 'NEEDS_ATTENTION')
-	`, businessID)
+// CompletedRequirements is the resolver for the completedRequirements field.
+func (r *renewalResolver) CompletedRequirements(ctx context.Context, obj *model.Renewal) (int, error) {
+	var count int
+	err := r.DB.GetContext(ctx, &count,
+		"SELECT COUNT(*) FROM renewal_requirements WHERE renewal_id = $1 AND completed_at IS NOT NULL", obj.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get compliance issues count: %v", err)
+		return 0, fmt.Errorf("failed to count completed renewal requirements: %v", err)
 	}
+	return count, nil
+}
 
-	// Get upcoming renewals count (within 30 days)
-	err = r.DB.Get(&summary.UpcomingRenewals, `
-		SELECT COUNT(*) FROM renewal_requirements rr
-		JOIN licenses l ON rr.license_id = l.id
-		WHERE l.business_id = $1 
-		  AND rr.due_date <= CURRENT_DATE + INTERVAL '30 days'
-		  AND rr.completed_at IS NULL
-	`, businessID)
+// TotalRequirements is the resolver for the totalRequirements field.
+func (r *renewalResolver) TotalRequirements(ctx context.Context, obj *model.Renewal) (int, error) {
+	var count int
+	err := r.DB.GetContext(ctx, &count, "SELECT COUNT(*) FROM renewal_requirements WHERE renewal_id = $1", obj.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get upcoming renewals count: %v", err)
-	}
-
-	// Get recent notifications
-	err = r.DB.Select(&summary.RecentNotifications, `
-		SELECT n.id, n.user_id, n.title, n.message, n.type, n.is_read, 
-		       n.related_entity_id, n.related_entity_type, n.created_at::text 
-		FROM notifications n
-		JOIN users u ON n.user_id = u.id
-		JOIN businesses b ON u.id = b.owner_id OR EXISTS (
-			SELECT 1 FROM licenses l WHERE l.business_id = b.id AND l.business_id = $1
-		)
-		WHERE b.id = $1
-		ORDER BY n.created_at DESC
-		LIMIT 5
-	`, businessID)
+		return 0, fmt.Errorf("failed to count renewal requirements: %v", err)
+	}
+	return count, nil
+}
+
+// License is the resolver for the license field.
+func (r *renewalRequirementResolver) License(ctx context.Context, obj *model.RenewalRequirement) (*model.License, error) {
+	return r.getLicense(ctx, obj.LicenseID)
+}
+
+// Documents is the resolver for the documents field.
+func (r *renewalRequirementResolver) Documents(ctx context.Context, obj *model.RenewalRequirement) ([]*model.Document, error) {
+	var documents []*model.Document
+	err := r.DB.SelectContext(ctx, &documents, `
+		SELECT `+documentColumns+`
+		FROM documents
+		WHERE renewal_requirement_id = $1 AND superseded_by_id IS NULL
+		ORDER BY created_at DESC
+	`, obj.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent notifications: %v", err)
+		return nil, fmt.Errorf("failed to get renewal requirement documents: %v", err)
 	}
+	return documents, nil
+}
 
-	return summary, nil
+// Jurisdiction is the resolver for the jurisdiction field.
+func (r *renewalTemplateResolver) Jurisdiction(ctx context.Context, obj *model.RenewalTemplate) (*model.Jurisdiction, error) {
+	return r.getJurisdiction(ctx, obj.JurisdictionID)
 }
 
-// Hello is the resolver for the hello field.
-func (r *queryResolver) Hello(ctx context.Context) (string, error) {
-	return "Hello, BudSafe User with Real Database!", nil
+// Items is the resolver for the items field.
+func (r *renewalTemplateResolver) Items(ctx context.Context, obj *model.RenewalTemplate) ([]*model.RenewalTemplateItem, error) {
+	items := []*model.RenewalTemplateItem{}
+	err := r.DB.SelectContext(ctx, &items, `
+		SELECT id, template_id, position, description, due_days_before, document_category
+		FROM renewal_template_items
+		WHERE template_id = $1
+		ORDER BY position
+	`, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get renewal template items: %v", err)
+	}
+	return items, nil
+}
+
+// Business is the resolver for the business field.
+func (r *reportJobResolver) Business(ctx context.Context, obj *model.ReportJob) (*model.Business, error) {
+	return r.getBusiness(ctx, obj.BusinessID)
+}
+
+// Location is the resolver for the location field.
+func (r *reportJobResolver) Location(ctx context.Context, obj *model.ReportJob) (*model.Location, error) {
+	if obj.LocationID == nil {
+		return nil, nil
+	}
+	return r.getLocation(ctx, *obj.LocationID)
+}
+
+// Document is the resolver for the document field.
+func (r *reportJobResolver) Document(ctx context.Context, obj *model.ReportJob) (*model.Document, error) {
+	if obj.DocumentID == nil {
+		return nil, nil
+	}
+	var document model.Document
+	err := r.DB.GetContext(ctx, &document, `SELECT `+documentColumns+` FROM documents WHERE id = $1`, *obj.DocumentID)
+	if err != nil {
+		return nil, getOrNotFound(err, "document", *obj.DocumentID)
+	}
+	return &document, nil
+}
+
+// RequestedBy is the resolver for the requestedBy field.
+func (r *reportJobResolver) RequestedBy(ctx context.Context, obj *model.ReportJob) (*model.User, error) {
+	return r.getUser(ctx, obj.RequestedByID)
 }
 
 // NotificationAdded is the resolver for the notificationAdded field.
 func (r *subscriptionResolver) NotificationAdded(ctx context.Context, userID string) (<-chan *model.Notification, error) {
-	panic(fmt.Errorf("not implemented: NotificationAdded - notificationAdded"))
+	if _, err := r.requireSelfOrAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+	return subscribe(ctx, r.Resolver, pubsub.TopicNotificationAdded,
+		func(event pubsub.Event) bool { return event.UserID == userID },
+		r.loadNotification)
 }
 
 // LicenseStatusChanged is the resolver for the licenseStatusChanged field.
 func (r *subscriptionResolver) LicenseStatusChanged(ctx context.Context, businessID *string) (<-chan *model.License, error) {
-	panic(fmt.Errorf("not implemented: LicenseStatusChanged - licenseStatusChanged"))
+	if businessID != nil {
+		err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+			return r.requireBusinessRole(ctx, tx, *businessID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return subscribe(ctx, r.Resolver, pubsub.TopicLicenseStatusChanged, businessFilter(businessID), r.loadVisibleLicense)
 }
 
 // ComplianceStatusChanged is the resolver for the complianceStatusChanged field.
 func (r *subscriptionResolver) ComplianceStatusChanged(ctx context.Context, businessID *string) (<-chan *model.ComplianceCheck, error) {
-	panic(fmt.Errorf("not implemented: ComplianceStatusChanged - complianceStatusChanged"))
+	if businessID != nil {
+		err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+			return r.requireBusinessRole(ctx, tx, *businessID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return subscribe(ctx, r.Resolver, pubsub.TopicComplianceStatusChanged, businessFilter(businessID), r.loadVisibleComplianceCheck)
+}
+
+// Businesses is the resolver for the businesses field.
+func (r *userResolver) Businesses(ctx context.Context, obj *model.User) ([]*model.Business, error) {
+	var businesses []*model.Business
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, &businesses, `
+			SELECT b.id, b.name, b.type, b.description, b.owner_id, b.created_at::text, b.updated_at::text
+			FROM businesses b
+			JOIN business_members m ON m.business_id = b.id
+			WHERE m.user_id = $1 AND app_can_access_business(b.id)
+			ORDER BY b.name ASC
+		`, obj.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user businesses: %v", err)
+	}
+	return businesses, nil
+}
+
+// EventType is the resolver for the eventType field.
+func (r *webhookDeliveryResolver) EventType(ctx context.Context, obj *model.WebhookDelivery) (model.WebhookEventType, error) {
+	return webhookEventType(obj.EventName), nil
+}
+
+// Business is the resolver for the business field.
+func (r *webhookSubscriptionResolver) Business(ctx context.Context, obj *model.WebhookSubscription) (*model.Business, error) {
+	return r.getBusiness(ctx, obj.BusinessID)
+}
+
+// EventTypes is the resolver for the eventTypes field.
+func (r *webhookSubscriptionResolver) EventTypes(ctx context.Context, obj *model.WebhookSubscription) ([]model.WebhookEventType, error) {
+	types := make([]model.WebhookEventType, len(obj.EventNames))
+	for i, name := range obj.EventNames {
+		types[i] = webhookEventType(name)
+	}
+	return types, nil
+}
+
+// Deliveries is the resolver for the deliveries field.
+func (r *webhookSubscriptionResolver) Deliveries(ctx context.Context, obj *model.WebhookSubscription, status *model.WebhookDeliveryStatus, first *int, after *string, last *int, before *string) (*model.WebhookDeliveryConnection, error) {
+	var where whereClause
+	where.add("subscription_id = ?", obj.ID)
+	if status != nil {
+		where.add("status = ?", *status)
+	}
+
+	page, err := listQuery[model.WebhookDelivery]{
+		from:    "webhook_deliveries",
+		columns: webhookDeliveryColumns,
+		where:   where,
+		key:     webhookDeliveryOrderKey,
+		desc:    true,
+		id:      func(d *model.WebhookDelivery) string { return d.ID },
+		scan:    structScan[model.WebhookDelivery],
+	}.fetch(ctx, r.DB, pageArgs{first, after, last, before})
+	if err != nil {
+		return nil, dbError(err, "query webhook deliveries")
+	}
+
+	conn := &model.WebhookDeliveryConnection{Edges: make([]*model.WebhookDeliveryEdge, 0, len(page.Nodes)), PageInfo: page.PageInfo, TotalCount: page.TotalCount}
+	for i, node := range page.Nodes {
+		conn.Edges = append(conn.Edges, &model.WebhookDeliveryEdge{Cursor: page.Cursors[i], Node: node})
+	}
+	return conn, nil
 }
 
-// ExpiringBefore is the resolver for the expiringBefore field.
-func (r *licenseFilterResolver) ExpiringBefore(ctx context.Context, obj *model.License, data *string) error {
-	panic(fmt.Errorf("not implemented: ExpiringBefore - expiringBefore"))
+// AuditEvent returns generated.AuditEventResolver implementation.
+func (r *Resolver) AuditEvent() generated.AuditEventResolver { return &auditEventResolver{r} }
+
+// Business returns generated.BusinessResolver implementation.
+func (r *Resolver) Business() generated.BusinessResolver { return &businessResolver{r} }
+
+// BusinessMember returns generated.BusinessMemberResolver implementation.
+func (r *Resolver) BusinessMember() generated.BusinessMemberResolver {
+	return &businessMemberResolver{r}
 }
 
+// CalendarFeed returns generated.CalendarFeedResolver implementation.
+func (r *Resolver) CalendarFeed() generated.CalendarFeedResolver { return &calendarFeedResolver{r} }
+
 // ComplianceCheck returns generated.ComplianceCheckResolver implementation.
 func (r *Resolver) ComplianceCheck() generated.ComplianceCheckResolver {
 	return &complianceCheckResolver{r}
 }
 
+// ComplianceSchedule returns generated.ComplianceScheduleResolver implementation.
+func (r *Resolver) ComplianceSchedule() generated.ComplianceScheduleResolver {
+	return &complianceScheduleResolver{r}
+}
+
+// Document returns generated.DocumentResolver implementation.
+func (r *Resolver) Document() generated.DocumentResolver { return &documentResolver{r} }
+
+// License returns generated.LicenseResolver implementation.
+func (r *Resolver) License() generated.LicenseResolver { return &licenseResolver{r} }
+
+// LicenseStatusChange returns generated.LicenseStatusChangeResolver implementation.
+func (r *Resolver) LicenseStatusChange() generated.LicenseStatusChangeResolver {
+	return &licenseStatusChangeResolver{r}
+}
+
+// Location returns generated.LocationResolver implementation.
+func (r *Resolver) Location() generated.LocationResolver { return &locationResolver{r} }
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
@@ -703,15 +2721,56 @@ func (r *Resolver) Notification() generated.NotificationResolver { return &notif
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// Renewal returns generated.RenewalResolver implementation.
+func (r *Resolver) Renewal() generated.RenewalResolver { return &renewalResolver{r} }
+
+// RenewalRequirement returns generated.RenewalRequirementResolver implementation.
+func (r *Resolver) RenewalRequirement() generated.RenewalRequirementResolver {
+	return &renewalRequirementResolver{r}
+}
+
+// RenewalTemplate returns generated.RenewalTemplateResolver implementation.
+func (r *Resolver) RenewalTemplate() generated.RenewalTemplateResolver {
+	return &renewalTemplateResolver{r}
+}
+
+// ReportJob returns generated.ReportJobResolver implementation.
+func (r *Resolver) ReportJob() generated.ReportJobResolver { return &reportJobResolver{r} }
+
 // Subscription returns generated.SubscriptionResolver implementation.
 func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
 
-// LicenseFilter returns generated.LicenseFilterResolver implementation.
-func (r *Resolver) LicenseFilter() generated.LicenseFilterResolver { return &licenseFilterResolver{r} }
+// User returns generated.UserResolver implementation.
+func (r *Resolver) User() generated.UserResolver { return &userResolver{r} }
+
+// WebhookDelivery returns generated.WebhookDeliveryResolver implementation.
+func (r *Resolver) WebhookDelivery() generated.WebhookDeliveryResolver {
+	return &webhookDeliveryResolver{r}
+}
+
+// WebhookSubscription returns generated.WebhookSubscriptionResolver implementation.
+func (r *Resolver) WebhookSubscription() generated.WebhookSubscriptionResolver {
+	return &webhookSubscriptionResolver{r}
+}
 
+type auditEventResolver struct{ *Resolver }
+type businessResolver struct{ *Resolver }
+type businessMemberResolver struct{ *Resolver }
+type calendarFeedResolver struct{ *Resolver }
 type complianceCheckResolver struct{ *Resolver }
+type complianceScheduleResolver struct{ *Resolver }
+type documentResolver struct{ *Resolver }
+type licenseResolver struct{ *Resolver }
+type licenseStatusChangeResolver struct{ *Resolver }
+type locationResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type notificationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type renewalResolver struct{ *Resolver }
+type renewalRequirementResolver struct{ *Resolver }
+type renewalTemplateResolver struct{ *Resolver }
+type reportJobResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
-type licenseFilterResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
+type webhookDeliveryResolver struct{ *Resolver }
+type webhookSubscriptionResolver struct{ *Resolver }
@@ -2,20 +2,41 @@ package graph_test
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"budsafe/backend/audit"
 	"budsafe/backend/auth"
 	"budsafe/backend/graph"
+	"budsafe/backend/graph/generated"
 	"budsafe/backend/graph/model"
+	"budsafe/backend/reporting"
+	"budsafe/backend/storage"
+	"budsafe/backend/tracking"
+	"budsafe/backend/webhook"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
 // setupTestDB remains the same
@@ -34,6 +55,52 @@ func setupTestDB(t *testing.T) *sqlx.DB {
 	return db
 }
 
+// licenseFixture is a business owner, signed in as Ctx, with a business in
+// a jurisdiction of its own that licenses RETAIL and CULTIVATION
+type licenseFixture struct {
+	Ctx            context.Context
+	Owner          *model.User
+	JurisdictionID string
+	Business       *model.Business
+}
+
+// newLicenseFixture creates a fixture named after prefix, which for
+// "renewal" is the owner renewal.owner@example.com, the jurisdiction
+// "Renewal Test State" and the business "Renewal Dispensary". It is
+// deleted when the test ends.
+func newLicenseFixture(t *testing.T, db *sqlx.DB, prefix string) *licenseFixture {
+	t.Helper()
+	mutationResolver := (&graph.Resolver{DB: db}).Mutation()
+	name := strings.ToUpper(prefix[:1]) + prefix[1:]
+	uid, email := "test-firebase-uid-"+prefix+"-owner", prefix+".owner@example.com"
+
+	f := &licenseFixture{Ctx: auth.NewContext(context.Background(), &auth.User{UID: uid, Email: email})}
+	owner, err := mutationResolver.CreateUser(f.Ctx, model.CreateUserInput{
+		Email:       email,
+		FirstName:   name,
+		LastName:    "Owner",
+		Role:        model.UserRoleBusinessOwner,
+		FirebaseUID: uid,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec("DELETE FROM users WHERE id = $1", owner.ID) })
+	f.Owner = owner
+
+	err = db.Get(&f.JurisdictionID, `
+		INSERT INTO jurisdictions (name, type, country, regulatory_body, license_types)
+		VALUES ($1, 'US_STATE', 'US', 'Test Cannabis Board', '{RETAIL,CULTIVATION}')
+		RETURNING id
+	`, name+" Test State")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec("DELETE FROM jurisdictions WHERE id = $1", f.JurisdictionID) })
+
+	business, err := mutationResolver.CreateBusiness(f.Ctx, model.CreateBusinessInput{Name: name + " Dispensary", Type: model.BusinessTypeRetailer})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Exec("DELETE FROM businesses WHERE id = $1", business.ID) })
+	f.Business = business
+	return f
+}
+
 func TestMutationResolver_CreateUser(t *testing.T) {
 	// --- 1. SETUP ---
 	db := setupTestDB(t)
@@ -53,7 +120,7 @@ func TestMutationResolver_CreateUser(t *testing.T) {
 		Email: 	 fakeAuthUser.Email,
 		FirstName: "Jane",
 		LastName:  "Doe",
-		Role:      model.UserRoleAdmin,
+		Role:      model.UserRoleBusinessOwner,
 		FirebaseUID: fakeAuthUser.UID,
 	}
 
@@ -89,4 +156,1051 @@ func TestMutationResolver_CreateUser(t *testing.T) {
 	// Check that the pointer is not nil and then dereference it
 	require.NotNil(t, dbUser.FirstName, "FirstName should not be nil")
 	assert.Equal(t, "Jane", *dbUser.FirstName)
-}
\ No newline at end of file
+}
+func TestMutationResolver_BusinessLifecycle(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+
+	fakeAuthUser := &auth.User{
+		UID:   "test-firebase-uid-business-123",
+		Email: "business.test@example.com",
+	}
+	ctx := auth.NewContext(context.Background(), fakeAuthUser)
+
+	owner, err := mutationResolver.CreateUser(ctx, model.CreateUserInput{
+		Email:       fakeAuthUser.Email,
+		FirstName:   "Owen",
+		LastName:    "Owner",
+		Role:        model.UserRoleBusinessOwner,
+		FirebaseUID: fakeAuthUser.UID,
+	})
+	require.NoError(t, err)
+	defer func() {
+		_, delErr := db.Exec("DELETE FROM users WHERE id = $1", owner.ID)
+		require.NoError(t, delErr, "Cleanup failed: Could not delete test user.")
+	}()
+
+	// --- 2. CREATE ---
+	business, err := mutationResolver.CreateBusiness(ctx, model.CreateBusinessInput{
+		Name: "Test Dispensary",
+		Type: model.BusinessTypeRetailer,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, business.OwnerID)
+
+	// --- 3. UPDATE ---
+	newName := "Renamed Dispensary"
+	updated, err := mutationResolver.UpdateBusiness(ctx, business.ID, model.UpdateBusinessInput{Name: &newName})
+	require.NoError(t, err)
+	assert.Equal(t, newName, updated.Name)
+	assert.Equal(t, model.BusinessTypeRetailer, updated.Type)
+
+	// --- 4. VALIDATION ---
+	blank := "  "
+	_, err = mutationResolver.UpdateBusiness(ctx, business.ID, model.UpdateBusinessInput{Name: &blank})
+	require.Error(t, err)
+
+	// --- 5. DELETE ---
+	deleted, err := mutationResolver.DeleteBusiness(ctx, business.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = mutationResolver.DeleteBusiness(ctx, business.ID)
+	require.Error(t, err, "Deleting a missing business should report not found")
+}
+
+func TestQueryResolver_TenantIsolation(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	ownerCtx := auth.NewContext(context.Background(), &auth.User{UID: "test-firebase-uid-tenant-owner", Email: "tenant.owner@example.com"})
+	otherCtx := auth.NewContext(context.Background(), &auth.User{UID: "test-firebase-uid-tenant-other", Email: "tenant.other@example.com"})
+
+	var userIDs []string
+	defer func() {
+		for _, id := range userIDs {
+			_, delErr := db.Exec("DELETE FROM users WHERE id = $1", id)
+			require.NoError(t, delErr, "Cleanup failed: Could not delete test user.")
+		}
+	}()
+	for _, ctx := range []context.Context{ownerCtx, otherCtx} {
+		authUser := auth.ForContext(ctx)
+		user, err := mutationResolver.CreateUser(ctx, model.CreateUserInput{
+			Email:       authUser.Email,
+			FirstName:   "Tenant",
+			LastName:    "Test",
+			Role:        model.UserRoleBusinessOwner,
+			FirebaseUID: authUser.UID,
+		})
+		require.NoError(t, err)
+		userIDs = append(userIDs, user.ID)
+	}
+
+	business, err := mutationResolver.CreateBusiness(ownerCtx, model.CreateBusinessInput{
+		Name: "Isolated Dispensary",
+		Type: model.BusinessTypeRetailer,
+	})
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM businesses WHERE id = $1", business.ID)
+
+	// --- 2. OTHER TENANTS CANNOT SEE OR CHANGE IT ---
+	_, err = queryResolver.Business(otherCtx, business.ID)
+	require.Error(t, err)
+
+	businesses, err := queryResolver.Businesses(otherCtx, nil, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	for _, edge := range businesses.Edges {
+		assert.NotEqual(t, business.ID, edge.Node.ID)
+	}
+
+	_, err = mutationResolver.DeleteBusiness(otherCtx, business.ID)
+	require.Error(t, err)
+
+	_, err = queryResolver.DashboardSummary(otherCtx, business.ID)
+	require.Error(t, err)
+
+	_, err = queryResolver.User(otherCtx, userIDs[0])
+	require.Error(t, err, "Users of other tenants must not be visible")
+
+	// --- 3. MEMBERSHIP GRANTS ACCESS ---
+	_, err = mutationResolver.AddBusinessMember(ownerCtx, business.ID, userIDs[1], model.UserRoleEmployee)
+	require.NoError(t, err)
+
+	found, err := queryResolver.Business(otherCtx, business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, business.ID, found.ID)
+
+	summary, err := queryResolver.DashboardSummary(otherCtx, business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, business.ID, summary.BusinessID)
+
+	colleague, err := queryResolver.User(otherCtx, userIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "tenant.owner@example.com", colleague.Email)
+
+	_, err = mutationResolver.DeleteBusiness(otherCtx, business.ID)
+	require.Error(t, err, "Employees must not be able to delete the business")
+
+	_, err = mutationResolver.RemoveBusinessMember(ownerCtx, business.ID, userIDs[0])
+	require.Error(t, err, "The owner's membership cannot be removed")
+}
+
+func TestQueryResolver_MeAutoProvision(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db, AutoProvision: true}
+	queryResolver := resolver.Query()
+	defer db.Exec("DELETE FROM users WHERE firebase_uid LIKE 'test-firebase-uid-provision-%'")
+
+	// --- 2. UNVERIFIED OR MISSING EMAILS ARE NOT PROVISIONED ---
+	for _, authUser := range []*auth.User{
+		{UID: "test-firebase-uid-provision-unverified", Email: "provision@example.com"},
+		{UID: "test-firebase-uid-provision-no-email", EmailVerified: true},
+	} {
+		me, err := queryResolver.Me(auth.NewContext(context.Background(), authUser))
+		require.NoError(t, err)
+		assert.Nil(t, me, authUser.UID)
+	}
+
+	// --- 3. A VERIFIED ACCOUNT IS PROVISIONED ONCE ---
+	ctx := auth.NewContext(context.Background(), &auth.User{
+		UID: "test-firebase-uid-provision-first", Email: "provision@example.com", Name: "Pro Vision", EmailVerified: true,
+	})
+	me, err := queryResolver.Me(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, me)
+	assert.Equal(t, "provision@example.com", me.Email)
+	assert.Equal(t, model.UserRoleEmployee, me.Role)
+
+	again, err := queryResolver.Me(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, me.ID, again.ID)
+
+	// --- 4. ANOTHER ACCOUNT WITH THE SAME EMAIL GETS A CONFLICT ---
+	_, err = queryResolver.Me(auth.NewContext(context.Background(), &auth.User{
+		UID: "test-firebase-uid-provision-second", Email: "provision@example.com", EmailVerified: true,
+	}))
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr)
+	assert.Equal(t, graph.ErrCodeConflict, gqlErr.Extensions["code"])
+	assert.Contains(t, err.Error(), "already belongs to another account")
+}
+
+func TestMutationResolver_RenewalWorkflow(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	f := newLicenseFixture(t, db, "renewal")
+	ctx, jurisdictionID, business := f.Ctx, f.JurisdictionID, f.Business
+
+	var templateID string
+	err := db.Get(&templateID, `
+		INSERT INTO renewal_templates (jurisdiction_id, license_type, name)
+		VALUES ($1, 'RETAIL', 'Retail renewal') RETURNING id
+	`, jurisdictionID)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		INSERT INTO renewal_template_items (template_id, position, description, due_days_before)
+		VALUES ($1, 1, 'Submit renewal application', 60), ($1, 2, 'Pay renewal fee', 30)
+	`, templateID)
+	require.NoError(t, err)
+
+	license, err := mutationResolver.CreateLicense(ctx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "RT-0001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     "2025-07-01",
+		ExpirationDate: "2026-07-01",
+		Status:         model.LicenseStatusActive,
+	})
+	require.NoError(t, err)
+
+	// --- 2. ENTERING RENEWAL_IN_PROGRESS OPENS THE CHECKLIST ---
+	inProgress := model.LicenseStatusRenewalInProgress
+	license, err = mutationResolver.UpdateLicense(ctx, license.ID, model.UpdateLicenseInput{Status: &inProgress})
+	require.NoError(t, err)
+
+	renewal, err := resolver.License().CurrentRenewal(ctx, license)
+	require.NoError(t, err)
+	require.NotNil(t, renewal)
+	assert.Equal(t, "2026-07-01", renewal.PreviousExpirationDate)
+	requirements, err := resolver.Renewal().Requirements(ctx, renewal)
+	require.NoError(t, err)
+	require.Len(t, requirements, 2)
+	assert.Equal(t, "Submit renewal application", requirements[0].Description)
+	assert.Equal(t, "2026-05-02", *requirements[0].Deadline)
+
+	// --- 3. THE LICENSE STAYS IN RENEWAL UNTIL EVERYTHING IS DONE ---
+	_, err = mutationResolver.RecordRenewalPermit(ctx, renewal.ID, model.RenewalPermitInput{IssuedDate: "2026-07-01", ExpirationDate: "2026-06-30"})
+	require.Error(t, err, "The renewed license must expire later than before")
+
+	renewal, err = mutationResolver.RecordRenewalPermit(ctx, renewal.ID, model.RenewalPermitInput{IssuedDate: "2026-07-01", ExpirationDate: "2027-07-01"})
+	require.NoError(t, err)
+	assert.Equal(t, model.RenewalStatusInProgress, renewal.Status)
+
+	_, err = mutationResolver.CompleteRenewalRequirement(ctx, requirements[0].ID)
+	require.NoError(t, err)
+	license, err = queryResolver.License(ctx, license.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LicenseStatusRenewalInProgress, license.Status)
+
+	// --- 4. COMPLETING THE LAST REQUIREMENT REACTIVATES THE LICENSE ---
+	_, err = mutationResolver.CompleteRenewalRequirement(ctx, requirements[1].ID)
+	require.NoError(t, err)
+	license, err = queryResolver.License(ctx, license.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LicenseStatusActive, license.Status)
+	assert.Equal(t, "2027-07-01", license.ExpirationDate)
+
+	renewal, err = queryResolver.Renewal(ctx, renewal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.RenewalStatusCompleted, renewal.Status)
+}
+
+func TestMutationResolver_LicenseNumberFormats(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	f := newLicenseFixture(t, db, "numbering")
+	ctx, jurisdictionID, business := f.Ctx, f.JurisdictionID, f.Business
+
+	// --- 2. FORMATS ARE CHECKED WHEN SET ---
+	prefix, pattern, example, badExample := "C10-", `^C10-\d{7}$`, "C10-0000123", "C11-0000123"
+	_, err := mutationResolver.SetLicenseNumberFormats(ctx, jurisdictionID, []*model.LicenseNumberFormatInput{
+		{LicenseTypes: []model.LicenseType{model.LicenseTypeRetail}, Prefix: &prefix, Pattern: &pattern, Example: &badExample},
+	})
+	require.Error(t, err, "The example must have the format")
+
+	jurisdiction, err := mutationResolver.SetLicenseNumberFormats(ctx, jurisdictionID, []*model.LicenseNumberFormatInput{
+		{LicenseTypes: []model.LicenseType{model.LicenseTypeRetail}, Prefix: &prefix, Pattern: &pattern, Example: &example},
+	})
+	require.NoError(t, err)
+	require.Len(t, jurisdiction.LicenseNumberFormats, 1)
+	assert.Equal(t, example, *jurisdiction.LicenseNumberFormats[0].Example)
+
+	// --- 3. THE QUERY REPORTS WHAT IS WRONG ---
+	validation, err := queryResolver.ValidateLicenseNumber(ctx, jurisdictionID, model.LicenseTypeRetail, " C11-12345 ")
+	require.NoError(t, err)
+	assert.False(t, validation.Valid)
+	assert.Equal(t, "C11-12345", validation.LicenseNumber)
+	assert.Len(t, validation.Errors, 2)
+
+	validation, err = queryResolver.ValidateLicenseNumber(ctx, jurisdictionID, model.LicenseTypeCultivation, "anything")
+	require.NoError(t, err)
+	assert.True(t, validation.Valid, "Types without a format accept any number")
+	assert.Nil(t, validation.Format)
+
+	// --- 4. MUTATIONS ENFORCE THE FORMAT ---
+	input := model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "C11-0000001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     "2026-01-01",
+		ExpirationDate: "2027-01-01",
+		Status:         model.LicenseStatusActive,
+	}
+	_, err = mutationResolver.CreateLicense(ctx, input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `must start with "C10-"`)
+
+	input.LicenseNumber = "C10-0000001"
+	license, err := mutationResolver.CreateLicense(ctx, input)
+	require.NoError(t, err)
+
+	short, renumbered := "C10-1", "C10-0000002"
+	_, err = mutationResolver.UpdateLicense(ctx, license.ID, model.UpdateLicenseInput{LicenseNumber: &short})
+	require.Error(t, err)
+	_, err = mutationResolver.UpdateLicense(ctx, license.ID, model.UpdateLicenseInput{LicenseNumber: &renumbered})
+	require.NoError(t, err)
+}
+
+func TestMutationResolver_CalendarFeed(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db, PublicURL: "https://api.example.com"}
+	mutationResolver := resolver.Mutation()
+
+	f := newLicenseFixture(t, db, "calendar")
+	ctx, jurisdictionID, business := f.Ctx, f.JurisdictionID, f.Business
+
+	_, err := mutationResolver.CreateLicense(ctx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "CAL-0001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     time.Now().AddDate(-1, 0, 0).Format(time.DateOnly),
+		ExpirationDate: time.Now().AddDate(0, 6, 0).Format(time.DateOnly),
+		Status:         model.LicenseStatusActive,
+	})
+	require.NoError(t, err)
+	_, err = mutationResolver.CreateLicense(ctx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "CAL-0002",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     time.Now().AddDate(-1, 0, -10).Format(time.DateOnly),
+		ExpirationDate: time.Now().AddDate(0, 0, -10).Format(time.DateOnly),
+		Status:         model.LicenseStatusExpired,
+	})
+	require.NoError(t, err)
+
+	// --- 2. THE FEED URL SERVES THE BUSINESS'S DEADLINES ---
+	subscription, err := mutationResolver.CreateCalendarFeed(ctx, business.ID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "BudSafe - Calendar Dispensary", subscription.Feed.Name)
+	require.True(t, strings.HasPrefix(subscription.URL, "https://api.example.com/calendar/"))
+	path := strings.TrimPrefix(subscription.URL, "https://api.example.com/calendar")
+
+	handler := resolver.CalendarHandler()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "SUMMARY:License CAL-0001 expires")
+	assert.Contains(t, rec.Body.String(), "SUMMARY:License CAL-0002 expires", "Missed expirations stay in the feed")
+	assert.Contains(t, rec.Body.String(), "BEGIN:VALARM")
+
+	// --- 3. A REVOKED FEED STOPS SERVING ---
+	feed, err := mutationResolver.RevokeCalendarFeed(ctx, subscription.Feed.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, feed.RevokedAt)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestMutationResolver_NotificationPreferences(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	ctx := auth.NewContext(context.Background(), &auth.User{UID: "test-firebase-uid-preferences", Email: "preferences@example.com"})
+	user, err := mutationResolver.CreateUser(ctx, model.CreateUserInput{
+		Email:       "preferences@example.com",
+		FirstName:   "Pref",
+		LastName:    "Erences",
+		Role:        model.UserRoleBusinessOwner,
+		FirebaseUID: "test-firebase-uid-preferences",
+	})
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM users WHERE id = $1", user.ID)
+
+	// --- 2. DEFAULTS: EVERY CHANNEL, IMMEDIATELY ---
+	prefs, err := queryResolver.NotificationPreferences(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", prefs.Timezone)
+	assert.Equal(t, model.DigestModeImmediate, prefs.Digest)
+	require.Len(t, prefs.Channels, len(model.AllNotificationType))
+	assert.ElementsMatch(t, model.AllNotificationChannel, prefs.Channels[0].Channels)
+
+	// --- 3. UPDATE ---
+	start, end := "22:00", "07:00"
+	prefs, err = mutationResolver.UpdateNotificationPreferences(ctx, user.ID, model.NotificationPreferencesInput{
+		Timezone:        "America/Los_Angeles",
+		QuietHoursStart: &start,
+		QuietHoursEnd:   &end,
+		Digest:          model.DigestModeWeekly,
+		DigestHour:      9,
+		DigestDay:       model.WeekdayFriday,
+		Channels: []*model.NotificationChannelsInput{
+			{Type: model.NotificationTypeLicenseExpiring, Channels: []model.NotificationChannel{model.NotificationChannelEmail}},
+		},
+	})
+	require.NoError(t, err)
+
+	prefs, err = queryResolver.NotificationPreferences(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "America/Los_Angeles", prefs.Timezone)
+	assert.Equal(t, &start, prefs.QuietHoursStart)
+	assert.Equal(t, model.WeekdayFriday, prefs.DigestDay)
+	for _, c := range prefs.Channels {
+		if c.Type == model.NotificationTypeLicenseExpiring {
+			assert.Equal(t, []model.NotificationChannel{model.NotificationChannelEmail}, c.Channels)
+		} else {
+			assert.Len(t, c.Channels, 2, c.Type)
+		}
+	}
+
+	_, err = mutationResolver.UpdateNotificationPreferences(ctx, user.ID, model.NotificationPreferencesInput{
+		Timezone: "Nowhere/Special", Digest: model.DigestModeDaily, DigestDay: model.WeekdayMonday, DigestHour: 8,
+	})
+	assert.ErrorContains(t, err, "unknown time zone")
+
+	// --- 4. NOTIFICATIONS OF TYPES MUTED IN THE APP ARE STORED AS READ ---
+	var muted, shown bool
+	err = db.Get(&muted, `
+		INSERT INTO notifications (user_id, title, message, type)
+		VALUES ($1, 'Muted', 'Muted', 'LICENSE_EXPIRING') RETURNING is_read
+	`, user.ID)
+	require.NoError(t, err)
+	assert.True(t, muted)
+	err = db.Get(&shown, `
+		INSERT INTO notifications (user_id, title, message, type)
+		VALUES ($1, 'Shown', 'Shown', 'RENEWAL_DUE') RETURNING is_read
+	`, user.ID)
+	require.NoError(t, err)
+	assert.False(t, shown)
+}
+
+func TestMutationResolver_Webhooks(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db, AllowLocalWebhooks: true}
+	mutationResolver := resolver.Mutation()
+
+	f := newLicenseFixture(t, db, "webhook")
+	ctx, jurisdictionID, business := f.Ctx, f.JurisdictionID, f.Business
+
+	license, err := mutationResolver.CreateLicense(ctx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "HOOK-0001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     time.Now().AddDate(-1, 0, 0).Format(time.DateOnly),
+		ExpirationDate: time.Now().AddDate(1, 0, 0).Format(time.DateOnly),
+		Status:         model.LicenseStatusActive,
+	})
+	require.NoError(t, err)
+
+	var received []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-BudSafe-Event"))
+	}))
+	defer receiver.Close()
+
+	// --- 2. SUBSCRIBE ---
+	_, err = mutationResolver.CreateWebhookSubscription(ctx, model.CreateWebhookSubscriptionInput{
+		BusinessID: business.ID,
+		URL:        "http://erp.example.com/hook",
+		EventTypes: []model.WebhookEventType{model.WebhookEventTypeLicenseStatusChanged},
+	})
+	assert.ErrorContains(t, err, "https")
+
+	created, err := mutationResolver.CreateWebhookSubscription(ctx, model.CreateWebhookSubscriptionInput{
+		BusinessID: business.ID,
+		URL:        receiver.URL,
+		EventTypes: []model.WebhookEventType{model.WebhookEventTypeLicenseStatusChanged},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Secret)
+
+	// --- 3. A STATUS CHANGE IS QUEUED AND DELIVERED ---
+	suspended := model.LicenseStatusSuspended
+	_, err = mutationResolver.UpdateLicense(ctx, license.ID, model.UpdateLicenseInput{Status: &suspended})
+	require.NoError(t, err)
+
+	_, _, err = (&webhook.Dispatcher{DB: db, AllowLocalhost: true}).Send(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"license.status_changed"}, received)
+
+	deliveries, err := resolver.WebhookSubscription().Deliveries(ctx, created.Subscription, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries.Edges, 1)
+	delivery := deliveries.Edges[0].Node
+	assert.Equal(t, model.WebhookDeliveryStatusSucceeded, delivery.Status)
+	assert.Contains(t, delivery.Payload, `"previousStatus": "ACTIVE"`)
+
+	_, err = mutationResolver.RetryWebhookDelivery(ctx, delivery.ID)
+	assert.ErrorContains(t, err, "already succeeded")
+}
+
+func TestQueryResolver_AuditTrail(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	f := newLicenseFixture(t, db, "audit")
+	owner, jurisdictionID, business := f.Owner, f.JurisdictionID, f.Business
+	request := &audit.Request{ID: "audit-test-request", IP: "203.0.113.7"}
+	ownerCtx := audit.NewContext(f.Ctx, request)
+
+	managerCtx := auth.NewContext(context.Background(), &auth.User{UID: "test-firebase-uid-audit-manager", Email: "audit.manager@example.com"})
+	manager, err := mutationResolver.CreateUser(managerCtx, model.CreateUserInput{
+		Email:       "audit.manager@example.com",
+		FirstName:   "Audit",
+		LastName:    "Manager",
+		Role:        model.UserRoleComplianceManager,
+		FirebaseUID: "test-firebase-uid-audit-manager",
+	})
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM users WHERE id = $1", manager.ID)
+
+	license, err := mutationResolver.CreateLicense(ownerCtx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "AUD-0001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     time.Now().AddDate(-1, 0, 0).Format(time.DateOnly),
+		ExpirationDate: time.Now().AddDate(1, 0, 0).Format(time.DateOnly),
+		Status:         model.LicenseStatusActive,
+	})
+	require.NoError(t, err)
+
+	notes := "Inspected"
+	_, err = mutationResolver.UpdateLicense(ownerCtx, license.ID, model.UpdateLicenseInput{Notes: &notes})
+	require.NoError(t, err)
+
+	// --- 2. ACCESS ---
+	// Owners are not compliance managers, nor are non-members
+	_, err = queryResolver.AuditTrail(ownerCtx, "License", license.ID)
+	assert.Error(t, err)
+	_, err = queryResolver.AuditTrail(managerCtx, "License", license.ID)
+	assert.Error(t, err)
+	_, err = queryResolver.AuditTrail(managerCtx, "Widget", license.ID)
+	assert.ErrorContains(t, err, "unknown entity type")
+
+	_, err = mutationResolver.AddBusinessMember(ownerCtx, business.ID, manager.ID, model.UserRoleComplianceManager)
+	require.NoError(t, err)
+
+	// --- 3. TRAIL ---
+	_, err = audit.Seal(context.Background(), db)
+	require.NoError(t, err)
+	trail, err := queryResolver.AuditTrail(managerCtx, "License", license.ID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(trail), 2)
+
+	assert.Equal(t, model.AuditOperationCreate, trail[0].Operation)
+	assert.Nil(t, trail[0].Before)
+	require.NotNil(t, trail[0].ActorID)
+	assert.Equal(t, owner.ID, *trail[0].ActorID)
+	assert.Equal(t, "audit.owner@example.com", *trail[0].ActorEmail)
+	assert.Equal(t, "audit-test-request", *trail[0].RequestID)
+	assert.Equal(t, "203.0.113.7", *trail[0].IP)
+	assert.Equal(t, business.ID, *trail[0].BusinessID)
+
+	update := trail[len(trail)-1]
+	assert.Equal(t, model.AuditOperationUpdate, update.Operation)
+	require.NotNil(t, update.Hash)
+	assert.Equal(t, trail[len(trail)-2].Hash, update.PreviousHash)
+	before, err := resolver.AuditEvent().Before(managerCtx, update)
+	require.NoError(t, err)
+	after, err := resolver.AuditEvent().After(managerCtx, update)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"notes": nil}, before)
+	assert.Equal(t, map[string]any{"notes": "Inspected"}, after)
+
+	// Users are not business records; only ADMINs see their trail
+	_, err = queryResolver.AuditTrail(managerCtx, "User", owner.ID)
+	assert.Error(t, err)
+
+	// --- 4. TAMPERING ---
+	_, err = db.Exec("UPDATE audit_events SET ip = '198.51.100.1' WHERE id = $1", update.ID)
+	assert.ErrorContains(t, err, "append-only")
+	_, err = audit.Verify(context.Background(), db)
+	assert.NoError(t, err)
+}
+
+func TestMutationResolver_ImportLicenses(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+
+	f := newLicenseFixture(t, db, "import")
+	ctx, business := f.Ctx, f.Business
+
+	_, err := mutationResolver.CreateLocation(ctx, model.CreateLocationInput{
+		BusinessID: business.ID,
+		Address:    "12 Import Way",
+		City:       "Sacramento",
+		State:      "CA",
+		ZipCode:    "95814",
+	})
+	require.NoError(t, err)
+
+	upload := func(file string) graphql.Upload {
+		return graphql.Upload{File: strings.NewReader(file), Filename: "licenses.csv", Size: int64(len(file))}
+	}
+	countLicenses := func() int {
+		var n int
+		require.NoError(t, db.Get(&n, "SELECT COUNT(*) FROM licenses WHERE business_id = $1", business.ID))
+		return n
+	}
+
+	// --- 2. DRY RUN WITH ERRORS ---
+	file := "Business,License Number,Type,Jurisdiction,Location,Issued Date,Expiration Date\n" +
+		"Import Dispensary,IMP-0001,Retail,Import Test State,12 Import Way,1/1/2025,1/1/2026\n" +
+		"Import Dispensary,IMP-0001,Retail,Import Test State,,1/1/2025,1/1/2026\n" +
+		"Import Dispensary,IMP-0002,Cultivation,Nowhere,,2025-01-01,2024-01-01\n"
+	result, err := mutationResolver.ImportLicenses(ctx, upload(file), true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.RowCount)
+	assert.Equal(t, 1, result.ValidCount)
+	assert.Empty(t, result.Licenses)
+	require.Len(t, result.Errors, 3)
+	assert.Equal(t, 3, result.Errors[0].Row, "duplicate license number within the file")
+	assert.Equal(t, 4, result.Errors[1].Row)
+	assert.Equal(t, "jurisdictionId", *result.Errors[1].Field)
+	assert.Equal(t, 4, result.Errors[2].Row, "every problem with a row is reported")
+	assert.Equal(t, "expirationDate", *result.Errors[2].Field)
+	assert.Equal(t, 0, countLicenses())
+
+	// Without dry run, a file with errors imports nothing either
+	result, err = mutationResolver.ImportLicenses(ctx, upload(file), false)
+	require.NoError(t, err)
+	assert.Len(t, result.Errors, 3)
+	assert.Equal(t, 0, countLicenses())
+
+	// --- 3. IMPORT ---
+	file = "license number,license type,jurisdiction,location,issued,expires,status,notes\n" +
+		"IMP-0001,RETAIL,Import Test State,\"12 Import Way, Sacramento\",2025-01-01,2026-01-01,,First\n" +
+		"IMP-0002,CULTIVATION,Import Test State,,2025-01-01,2026-01-01,pending,\n"
+	result, err = mutationResolver.ImportLicenses(ctx, upload(file), false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	require.Len(t, result.Licenses, 2)
+	assert.Equal(t, "IMP-0001", result.Licenses[0].LicenseNumber)
+	assert.NotNil(t, result.Licenses[0].LocationID)
+	assert.Equal(t, model.LicenseStatusPending, result.Licenses[1].Status)
+	assert.Equal(t, 2, countLicenses())
+}
+
+func TestMutationResolver_DocumentVersions(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	resolver := &graph.Resolver{DB: db}
+	mutationResolver := resolver.Mutation()
+
+	ctx := auth.NewContext(context.Background(), &auth.User{UID: "test-firebase-uid-versions-owner", Email: "versions.owner@example.com"})
+	owner, err := mutationResolver.CreateUser(ctx, model.CreateUserInput{
+		Email:       "versions.owner@example.com",
+		FirstName:   "Vera",
+		LastName:    "Sion",
+		Role:        model.UserRoleBusinessOwner,
+		FirebaseUID: "test-firebase-uid-versions-owner",
+	})
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM users WHERE id = $1", owner.ID)
+
+	var jurisdictionID string
+	err = db.Get(&jurisdictionID, `
+		INSERT INTO jurisdictions (name, type, country, regulatory_body, license_types)
+		VALUES ('Versions Test State', 'US_STATE', 'US', 'Test Cannabis Board', '{RETAIL}')
+		RETURNING id
+	`)
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM jurisdictions WHERE id = $1", jurisdictionID)
+
+	business, err := mutationResolver.CreateBusiness(ctx, model.CreateBusinessInput{Name: "Versioned Dispensary", Type: model.BusinessTypeRetailer})
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM businesses WHERE id = $1", business.ID)
+
+	license, err := mutationResolver.CreateLicense(ctx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LicenseNumber:  "VER-0001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     "2025-07-01",
+		ExpirationDate: "2026-07-01",
+		Status:         model.LicenseStatusActive,
+	})
+	require.NoError(t, err)
+
+	fileURL, fileType := "https://example.com/insurance-v1.pdf", "application/pdf"
+	first, err := mutationResolver.CreateDocument(ctx, model.CreateDocumentInput{
+		Name: "Insurance certificate", FileURL: &fileURL, FileType: &fileType, LicenseID: &license.ID,
+	})
+	require.NoError(t, err)
+	var versions []*model.Document
+	for _, url := range []string{"https://example.com/insurance-v2.pdf", "https://example.com/insurance-v3.pdf"} {
+		version, err := mutationResolver.AddDocumentVersion(ctx, first.ID, model.DocumentVersionInput{FileURL: &url, FileType: &fileType})
+		require.NoError(t, err)
+		versions = append(versions, version)
+	}
+
+	// --- 2. DELETING A SUPERSEDED VERSION KEEPS THE CHAIN ---
+	_, err = mutationResolver.DeleteDocument(ctx, versions[0].ID)
+	require.NoError(t, err)
+
+	var supersededBy *string
+	require.NoError(t, db.Get(&supersededBy, "SELECT superseded_by_id FROM documents WHERE id = $1", first.ID))
+	require.NotNil(t, supersededBy)
+	assert.Equal(t, versions[1].ID, *supersededBy)
+
+	var current []string
+	require.NoError(t, db.Select(&current,
+		"SELECT id FROM documents WHERE series_id = $1 AND superseded_by_id IS NULL", first.SeriesID))
+	assert.Equal(t, []string{versions[1].ID}, current)
+
+	// --- 3. DELETING THE CURRENT VERSION RESTORES ITS PREDECESSOR ---
+	_, err = mutationResolver.DeleteDocument(ctx, versions[1].ID)
+	require.NoError(t, err)
+	current = nil
+	require.NoError(t, db.Select(&current,
+		"SELECT id FROM documents WHERE series_id = $1 AND superseded_by_id IS NULL", first.SeriesID))
+	assert.Equal(t, []string{first.ID}, current)
+}
+
+func TestMutationResolver_GenerateReport(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	files, err := storage.NewLocal(t.TempDir(), "http://files.test/files", []byte("test-secret"))
+	require.NoError(t, err)
+	resolver := &graph.Resolver{DB: db, Storage: files}
+	mutationResolver := resolver.Mutation()
+	queryResolver := resolver.Query()
+
+	f := newLicenseFixture(t, db, "report")
+	ctx, owner, jurisdictionID, business := f.Ctx, f.Owner, f.JurisdictionID, f.Business
+	other, err := mutationResolver.CreateBusiness(ctx, model.CreateBusinessInput{Name: "Other Dispensary", Type: model.BusinessTypeRetailer})
+	require.NoError(t, err)
+	defer db.Exec("DELETE FROM businesses WHERE id = $1", other.ID)
+
+	location, err := mutationResolver.CreateLocation(ctx, model.CreateLocationInput{
+		BusinessID: business.ID, Address: "1 Audit Ave", City: "Denver", State: "CO", ZipCode: "80202",
+	})
+	require.NoError(t, err)
+	otherLocation, err := mutationResolver.CreateLocation(ctx, model.CreateLocationInput{
+		BusinessID: other.ID, Address: "2 Elsewhere Rd", City: "Denver", State: "CO", ZipCode: "80202",
+	})
+	require.NoError(t, err)
+
+	license, err := mutationResolver.CreateLicense(ctx, model.CreateLicenseInput{
+		BusinessID:     business.ID,
+		LocationID:     &location.ID,
+		LicenseNumber:  "RPT-0001",
+		LicenseType:    model.LicenseTypeRetail,
+		JurisdictionID: jurisdictionID,
+		IssuedDate:     "2025-07-01",
+		ExpirationDate: "2026-07-01",
+		Status:         model.LicenseStatusActive,
+	})
+	require.NoError(t, err)
+	_, err = mutationResolver.CreateComplianceCheck(ctx, model.CreateComplianceCheckInput{
+		LicenseID: license.ID, Title: "Camera retention", DueDate: "2026-01-01", Status: model.ComplianceStatusNonCompliant,
+	})
+	require.NoError(t, err)
+	fileURL, fileType := "https://example.com/insurance.pdf", "application/pdf"
+	_, err = mutationResolver.CreateDocument(ctx, model.CreateDocumentInput{
+		Name: "Insurance certificate", FileURL: &fileURL, FileType: &fileType, LicenseID: &license.ID,
+	})
+	require.NoError(t, err)
+
+	// --- 2. REQUESTS ARE VALIDATED ---
+	_, err = mutationResolver.GenerateReport(ctx, business.ID, &otherLocation.ID, model.ReportFormatCSV)
+	require.Error(t, err, "The location must belong to the business")
+
+	_, err = (&graph.Resolver{DB: db}).Mutation().GenerateReport(ctx, business.ID, nil, model.ReportFormatCSV)
+	var gqlErr *gqlerror.Error
+	require.ErrorAs(t, err, &gqlErr, "Without document storage")
+	assert.Equal(t, graph.ErrCodeValidation, gqlErr.Extensions["code"])
+
+	// --- 3. REPORTS ARE GENERATED IN THE BACKGROUND ---
+	job, err := mutationResolver.GenerateReport(ctx, business.ID, nil, model.ReportFormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, model.ReportJobStatusPending, job.Status)
+	located, err := mutationResolver.GenerateReport(ctx, business.ID, &location.ID, model.ReportFormatXlsx)
+	require.NoError(t, err)
+
+	generator := &reporting.Generator{DB: db, Storage: files, Prefix: graph.DocumentsPrefix}
+	require.NoError(t, generator.Run(context.Background()))
+
+	job, err = queryResolver.ReportJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ReportJobStatusSucceeded, job.Status)
+	assert.NotNil(t, job.CompletedAt)
+
+	// --- 4. THE REPORT IS A DOCUMENT OF THE BUSINESS ---
+	document, err := resolver.ReportJob().Document(ctx, job)
+	require.NoError(t, err)
+	require.NotNil(t, document)
+	assert.Equal(t, business.ID, *document.BusinessID)
+	assert.Nil(t, document.LicenseID)
+	assert.Equal(t, "text/csv", document.FileType)
+	assert.Equal(t, owner.ID, document.UploadedByID)
+
+	contents, err := os.ReadFile(filepath.Join(files.Dir, *document.StorageKey))
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "RPT-0001")
+	assert.Contains(t, string(contents), "Camera retention")
+	assert.Contains(t, string(contents), "Insurance certificate")
+
+	jobs, err := queryResolver.ReportJobs(ctx, business.ID)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, located.ID, jobs[0].ID, "Newest first")
+	assert.Equal(t, model.ReportJobStatusSucceeded, jobs[0].Status)
+	assert.Equal(t, location.ID, *jobs[0].LocationID)
+}
+
+// statementLog is a database/sql connector that records the statements its
+// connections run, to count the queries an operation really makes
+type statementLog struct {
+	driver.Connector
+	mu         sync.Mutex
+	statements []string
+}
+
+func (l *statementLog) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := l.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggedConn{Conn: conn, log: l}, nil
+}
+
+func (l *statementLog) record(query string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.statements = append(l.statements, query)
+}
+
+type loggedConn struct {
+	driver.Conn
+	log *statementLog
+}
+
+func (c *loggedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func (c *loggedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.log.record(query)
+	return c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+}
+
+func (c *loggedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.log.record(query)
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func TestQueryResolver_DataLoaders(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	f := newLicenseFixture(t, db, "loader")
+	mutationResolver := (&graph.Resolver{DB: db}).Mutation()
+
+	var locationIDs []string
+	for _, address := range []string{"1 Batch St", "2 Batch St"} {
+		location, err := mutationResolver.CreateLocation(f.Ctx, model.CreateLocationInput{
+			BusinessID: f.Business.ID, Address: address, City: "Denver", State: "CO", ZipCode: "80202",
+		})
+		require.NoError(t, err)
+		locationIDs = append(locationIDs, location.ID)
+	}
+	for i := range 4 {
+		_, err := mutationResolver.CreateLicense(f.Ctx, model.CreateLicenseInput{
+			BusinessID:     f.Business.ID,
+			LocationID:     &locationIDs[i%2],
+			LicenseNumber:  fmt.Sprintf("LDR-000%d", i),
+			LicenseType:    model.LicenseTypeRetail,
+			JurisdictionID: f.JurisdictionID,
+			IssuedDate:     "2025-07-01",
+			ExpirationDate: "2026-07-01",
+			Status:         model.LicenseStatusActive,
+		})
+		require.NoError(t, err)
+	}
+
+	connector, err := pq.NewConnector(os.Getenv("DATABASE_URL"))
+	require.NoError(t, err)
+	statements := &statementLog{Connector: connector}
+	counted := sqlx.NewDb(sql.OpenDB(statements), "postgres")
+	defer counted.Close()
+
+	resolver := &graph.Resolver{DB: counted}
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver, Directives: resolver.Directives()}))
+	srv.AddTransport(transport.POST{})
+	srv.AroundOperations(graph.ViewerCache)
+	srv.AroundOperations(graph.DataLoaders(resolver))
+
+	// --- 2. A NESTED QUERY ---
+	body := `{"query": "{ licenses(first: 10) { edges { node { licenseNumber business { name locations { id } } location { id } jurisdiction { name } } } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body)).WithContext(f.Ctx)
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		Data struct {
+			Licenses struct {
+				Edges []struct {
+					Node struct {
+						LicenseNumber string
+						Business      struct {
+							Name      string
+							Locations []struct{ ID string }
+						}
+						Location     struct{ ID string }
+						Jurisdiction struct{ Name string }
+					}
+				}
+			}
+		}
+		Errors []any
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response), rec.Body.String())
+	require.Empty(t, response.Errors)
+	require.Len(t, response.Data.Licenses.Edges, 4)
+	for _, edge := range response.Data.Licenses.Edges {
+		assert.Equal(t, "Loader Dispensary", edge.Node.Business.Name)
+		assert.Len(t, edge.Node.Business.Locations, 2)
+		assert.Contains(t, locationIDs, edge.Node.Location.ID)
+		assert.Equal(t, "Loader Test State", edge.Node.Jurisdiction.Name)
+	}
+
+	// --- 3. ONE STATEMENT PER NESTED FIELD, IN A SCOPED TRANSACTION ---
+	batch := regexp.MustCompile(`FROM (\w+)\s+WHERE (\w+) = ANY`)
+	batches := map[string]int{}
+	scopes := 0
+	for _, statement := range statements.statements {
+		if m := batch.FindStringSubmatch(statement); m != nil {
+			batches[m[1]+"."+m[2]]++
+		}
+		if strings.Contains(statement, "set_config('app.user_id'") {
+			scopes++
+		}
+	}
+	assert.Equal(t, map[string]int{
+		"businesses.id":         1,
+		"locations.id":          1,
+		"locations.business_id": 1,
+		"jurisdictions.id":      1,
+	}, batches, statements.statements)
+	assert.GreaterOrEqual(t, scopes, 1+len(batches), "The page and every batch are scoped to the viewer")
+}
+
+// failingPackages is a tracking client that fails to count the packages of
+// one license
+type failingPackages struct {
+	tracking.Client
+	number string
+}
+
+func (c failingPackages) ActivePackages(ctx context.Context, licenseNumber string) (int, error) {
+	if licenseNumber == c.number {
+		return 0, errors.New("tracking system unavailable")
+	}
+	return c.Client.ActivePackages(ctx, licenseNumber)
+}
+
+func TestTrackingReconciler_Run(t *testing.T) {
+	// --- 1. SETUP ---
+	db := setupTestDB(t)
+	f := newLicenseFixture(t, db, "tracking")
+	mutationResolver := (&graph.Resolver{DB: db}).Mutation()
+
+	for _, l := range []struct {
+		number, expires string
+		status          model.LicenseStatus
+	}{
+		{"TRK-0001", "2027-01-01", model.LicenseStatusActive},
+		{"TRK-0002", "2027-01-01", model.LicenseStatusActive},
+		{"trk-0003", "2026-01-01", model.LicenseStatusExpired},
+		{"TRK-0004", "2026-01-01", model.LicenseStatusExpired},
+	} {
+		_, err := mutationResolver.CreateLicense(f.Ctx, model.CreateLicenseInput{
+			BusinessID:     f.Business.ID,
+			LicenseNumber:  l.number,
+			LicenseType:    model.LicenseTypeRetail,
+			JurisdictionID: f.JurisdictionID,
+			IssuedDate:     "2025-01-01",
+			ExpirationDate: l.expires,
+			Status:         l.status,
+		})
+		require.NoError(t, err)
+	}
+
+	mock := tracking.NewMock("vendor-key", "user-key", tracking.MockData{
+		Facilities: []tracking.Facility{
+			{License: tracking.FacilityLicense{Number: "TRK-0002", EndDate: "2027-06-30"}},
+			{License: tracking.FacilityLicense{Number: "TRK-0003", EndDate: "2026-01-01"}},
+			{License: tracking.FacilityLicense{Number: "TRK-0004", EndDate: "2026-01-01"}},
+		},
+		Packages: map[string]int{"TRK-0003": 5, "TRK-0004": 2},
+	})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+	reconciler := &tracking.Reconciler{
+		DB:           db,
+		Client:       failingPackages{Client: &tracking.APIClient{BaseURL: server.URL, VendorKey: "vendor-key", UserKey: "user-key"}, number: "TRK-0004"},
+		Jurisdiction: "Tracking Test State",
+	}
+	notifications := func() []string {
+		var messages []string
+		require.NoError(t, db.Select(&messages, `
+			SELECT message FROM notifications
+			WHERE user_id = $1 AND type = 'COMPLIANCE_ISSUE'
+			ORDER BY message
+		`, f.Owner.ID))
+		return messages
+	}
+
+	// --- 2. EACH MISMATCH IS NOTIFIED ---
+	// The inventory of trk-0003 is asked for as the system spells it, and
+	// the failed count of TRK-0004 only skips that check
+	require.NoError(t, reconciler.Run(context.Background()))
+	assert.Equal(t, []string{
+		"License TRK-0001 is ACTIVE here but has no facility in the state tracking system.",
+		"License TRK-0002 expires on 2027-01-01 here but on 2027-06-30 in the state tracking system.",
+		"License trk-0003 is EXPIRED but holds 5 active package(s) in the state tracking system.",
+	}, notifications())
+
+	// --- 3. ONCE FOR AS LONG AS IT PERSISTS UNCHANGED ---
+	require.NoError(t, reconciler.Run(context.Background()))
+	assert.Len(t, notifications(), 3)
+
+	mock.AddFacility(tracking.Facility{License: tracking.FacilityLicense{Number: "TRK-0002", EndDate: "2027-09-30"}})
+	require.NoError(t, reconciler.Run(context.Background()))
+	messages := notifications()
+	require.Len(t, messages, 4)
+	assert.Contains(t, messages, "License TRK-0002 expires on 2027-01-01 here but on 2027-09-30 in the state tracking system.")
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"budsafe/backend/graph/model"
+	"budsafe/backend/pubsub"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// subscribe forwards the rows named by hub events on topic to the returned
+// channel until ctx is done. load returns nil for rows the viewer may not see.
+func subscribe[T any](ctx context.Context, r *Resolver, topic string, filter pubsub.Filter, load func(ctx context.Context, id string) (*T, error)) (<-chan *T, error) {
+	if r.Hub == nil {
+		return nil, errors.New("subscriptions are not available on this server")
+	}
+
+	events := r.Hub.Subscribe(ctx, topic, filter)
+	out := make(chan *T, 1)
+	go func() {
+		defer close(out)
+		for event := range events {
+			item, err := load(ctx, event.ID)
+			if err != nil {
+				log.Printf("subscription %s: failed to load %s: %v", topic, event.ID, err)
+				continue
+			}
+			if item == nil {
+				continue
+			}
+			select {
+			case out <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// businessFilter matches events for businessID, or all events when it is nil
+func businessFilter(businessID *string) pubsub.Filter {
+	if businessID == nil {
+		return nil
+	}
+	return func(event pubsub.Event) bool { return event.BusinessID == *businessID }
+}
+
+// loadVisible loads one row with query, which must select by id and filter
+// with app_can_access_business, returning nil when the viewer cannot see it.
+func loadVisible[T any](ctx context.Context, r *Resolver, query, id string) (*T, error) {
+	var row T
+	err := r.withReadTx(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, &row, query, id)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// loadNotification leaves out read notifications, which new ones only are
+// when their type is muted in the app
+func (r *Resolver) loadNotification(ctx context.Context, id string) (*model.Notification, error) {
+	return loadVisible[model.Notification](ctx, r,
+		`SELECT `+notificationColumns+` FROM notifications WHERE id = $1 AND NOT is_read`, id)
+}
+
+func (r *Resolver) loadVisibleLicense(ctx context.Context, id string) (*model.License, error) {
+	return loadVisible[model.License](ctx, r,
+		`SELECT `+licenseColumns+` FROM licenses WHERE id = $1 AND app_can_access_business(business_id)`, id)
+}
+
+func (r *Resolver) loadVisibleComplianceCheck(ctx context.Context, id string) (*model.ComplianceCheck, error) {
+	return loadVisible[model.ComplianceCheck](ctx, r, `
+		SELECT `+complianceCheckColumns+`
+		FROM compliance_checks
+		WHERE id = $1 AND EXISTS (
+			SELECT 1 FROM licenses l
+			WHERE l.id = compliance_checks.license_id AND app_can_access_business(l.business_id)
+		)`, id)
+}
@@ -0,0 +1,200 @@
+package graph
+
+import (
+	"budsafe/backend/auth"
+	"budsafe/backend/graph/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// noProfileUserID scopes signed-in callers without a users row to no businesses
+const noProfileUserID = "00000000-0000-0000-0000-000000000000"
+
+// applyScope sets the transaction-local app.user_id and app.user_role
+// settings that the row-level security policies and app_can_access_business()
+// read (see migrations/0002_business_members.up.sql).
+//
+// GraphQL operations are always scoped, even when unauthenticated. Calls
+// made outside an operation without an authenticated user (background jobs,
+// the CLI) are trusted and left unscoped.
+func (r *Resolver) applyScope(ctx context.Context, tx *sqlx.Tx) error {
+	_, inOperation := ctx.Value(viewerCacheKey{}).(*viewerCache)
+	if auth.ForContext(ctx) == nil && !inOperation {
+		return nil
+	}
+
+	userID, role := noProfileUserID, ""
+	viewer, err := r.viewer(ctx)
+	if err != nil {
+		return err
+	}
+	if viewer != nil {
+		userID, role = viewer.ID, string(viewer.Role)
+	}
+
+	return scopeTo(ctx, tx, userID, role)
+}
+
+// scopeTo scopes the transaction to the user with the given role
+func scopeTo(ctx context.Context, tx *sqlx.Tx, userID, role string) error {
+	_, err := tx.ExecContext(ctx,
+		"SELECT set_config('app.user_id', $1, true), set_config('app.user_role', $2, true)",
+		userID, role)
+	return dbError(err, "scope transaction")
+}
+
+// withReadTx runs fn in a read-only transaction scoped to the viewer
+func (r *Resolver) withReadTx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := r.applyScope(ctx, tx); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return dbError(tx.Commit(), "commit transaction")
+}
+
+// requireBusinessRole fails unless the viewer is a member of the business
+// with one of roles (any role when roles is empty). ADMIN passes everywhere.
+// Non-members get NOT_FOUND so that other tenants' ids are not confirmed.
+func (r *Resolver) requireBusinessRole(ctx context.Context, q sqlx.QueryerContext, businessID string, roles ...model.UserRole) error {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return err
+	}
+	if viewer.Role == model.UserRoleAdmin {
+		return requireExists(ctx, q, "businesses", "business", businessID)
+	}
+
+	var role model.UserRole
+	err = sqlx.GetContext(ctx, q, &role,
+		"SELECT role FROM business_members WHERE business_id = $1 AND user_id = $2",
+		businessID, viewer.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFoundError("business", businessID)
+	}
+	if err != nil {
+		return dbError(err, "look up business membership")
+	}
+	if len(roles) > 0 && !slices.Contains(roles, role) {
+		return forbiddenError("requires one of the roles %v in business %s", roles, businessID)
+	}
+	return nil
+}
+
+// requireColleague fails unless the viewer is the user, an ADMIN, or shares
+// a business with them. Others get NOT_FOUND so that user ids are not
+// confirmed.
+func (r *Resolver) requireColleague(ctx context.Context, q sqlx.QueryerContext, userID string) error {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return err
+	}
+	if viewer.ID == userID || viewer.Role == model.UserRoleAdmin {
+		return nil
+	}
+
+	var shared bool
+	err = sqlx.GetContext(ctx, q, &shared, `
+		SELECT EXISTS(
+			SELECT 1 FROM business_members a
+			JOIN business_members b ON b.business_id = a.business_id
+			WHERE a.user_id = $1 AND b.user_id = $2)
+	`, viewer.ID, userID)
+	if err != nil {
+		return dbError(err, "look up business membership")
+	}
+	if !shared {
+		return notFoundError("user", userID)
+	}
+	return nil
+}
+
+// businessOfQueries find the business that owns a row of each tenant table
+var businessOfQueries = map[string]string{
+	"licenses":  "SELECT business_id FROM licenses WHERE id = $1",
+	"locations": "SELECT business_id FROM locations WHERE id = $1",
+	"compliance_checks": `
+		SELECT l.business_id FROM compliance_checks c
+		JOIN licenses l ON l.id = c.license_id
+		WHERE c.id = $1`,
+	"compliance_schedules": `
+		SELECT l.business_id FROM compliance_schedules s
+		JOIN licenses l ON l.id = s.license_id
+		WHERE s.id = $1`,
+	"renewals": `
+		SELECT l.business_id FROM renewals rn
+		JOIN licenses l ON l.id = rn.license_id
+		WHERE rn.id = $1`,
+	"renewal_requirements": `
+		SELECT l.business_id FROM renewal_requirements rr
+		JOIN licenses l ON l.id = rr.license_id
+		WHERE rr.id = $1`,
+	"webhook_subscriptions": "SELECT business_id FROM webhook_subscriptions WHERE id = $1",
+	"webhook_deliveries": `
+		SELECT s.business_id FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.id = $1`,
+	"documents":   "SELECT business_id FROM documents WHERE id = $1",
+	"report_jobs": "SELECT business_id FROM report_jobs WHERE id = $1",
+}
+
+// requireEntityRole resolves the business owning the row id of table and
+// checks the viewer's role in it, returning the business id.
+func (r *Resolver) requireEntityRole(ctx context.Context, q sqlx.QueryerContext, table, entity, id string, roles ...model.UserRole) (string, error) {
+	var businessID string
+	if err := sqlx.GetContext(ctx, q, &businessID, businessOfQueries[table], id); err != nil {
+		return "", getOrNotFound(err, entity, id)
+	}
+	if err := r.requireBusinessRole(ctx, q, businessID, roles...); err != nil {
+		var gqlErr *gqlerror.Error
+		if errors.As(err, &gqlErr) && gqlErr.Extensions["code"] == ErrCodeNotFound {
+			// Report the entity that was asked for, not its business
+			return "", notFoundError(entity, id)
+		}
+		return "", err
+	}
+	return businessID, nil
+}
+
+// requireMember fails validation unless userID belongs to the business.
+// field names the input that supplied the user.
+func requireMember(ctx context.Context, q sqlx.QueryerContext, businessID, userID, field string) error {
+	var exists bool
+	err := sqlx.GetContext(ctx, q, &exists,
+		"SELECT EXISTS(SELECT 1 FROM business_members WHERE business_id = $1 AND user_id = $2)",
+		businessID, userID)
+	if err != nil {
+		return dbError(err, "look up business membership")
+	}
+	if !exists {
+		return validationError(field, "user %s is not a member of business %s", userID, businessID)
+	}
+	return nil
+}
+
+// requireNotOwner rejects removing the business owner's membership, or
+// changing it to anything but BUSINESS_OWNER (pass "" for removal).
+func requireNotOwner(ctx context.Context, q sqlx.QueryerContext, businessID, userID string, role model.UserRole) error {
+	var ownerID string
+	err := sqlx.GetContext(ctx, q, &ownerID, "SELECT owner_id FROM businesses WHERE id = $1", businessID)
+	if err != nil {
+		return getOrNotFound(err, "business", businessID)
+	}
+	if ownerID == userID && role != model.UserRoleBusinessOwner {
+		return conflictError("user %s owns business %s and must remain a BUSINESS_OWNER member", userID, businessID)
+	}
+	return nil
+}
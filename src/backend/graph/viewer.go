@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"budsafe/backend/auth"
+	"budsafe/backend/graph/model"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type viewerCacheKey struct{}
+
+// viewerCache memoizes the viewer lookup for the lifetime of one operation
+type viewerCache struct {
+	once sync.Once
+	user *model.User
+	err  error
+}
+
+// ViewerCache is a gqlgen operation middleware (handler.AroundOperations)
+// that lets every directive and resolver in an operation share one lookup
+// of the caller's users row.
+func ViewerCache(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(context.WithValue(ctx, viewerCacheKey{}, &viewerCache{}))
+}
+
+// viewer returns the users row for the authenticated Firebase account, or nil
+// if the request is unauthenticated or the account has no profile yet.
+// With AutoProvision the profile is created on first use, for accounts with
+// a verified email.
+func (r *Resolver) viewer(ctx context.Context) (*model.User, error) {
+	cache, ok := ctx.Value(viewerCacheKey{}).(*viewerCache)
+	if !ok {
+		return r.loadViewer(ctx)
+	}
+	cache.once.Do(func() {
+		cache.user, cache.err = r.loadViewer(ctx)
+	})
+	return cache.user, cache.err
+}
+
+func (r *Resolver) loadViewer(ctx context.Context) (*model.User, error) {
+	authUser := auth.ForContext(ctx)
+	if authUser == nil {
+		return nil, nil
+	}
+
+	var user model.User
+	err := r.DB.GetContext(ctx, &user, `SELECT `+userColumns+` FROM users WHERE firebase_uid = $1`, authUser.UID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if r.AutoProvision && authUser.Email != "" && authUser.EmailVerified {
+			return r.provisionViewer(ctx, authUser)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, dbError(err, "look up current user")
+	}
+	return &user, nil
+}
+
+// provisionViewer creates the users row for a first-time Firebase account
+// from its token claims. New accounts get the least privileged role; an
+// owner or ADMIN grants more afterwards. An email that already belongs to
+// another account is a CONFLICT.
+func (r *Resolver) provisionViewer(ctx context.Context, authUser *auth.User) (*model.User, error) {
+	firstName, lastName := splitName(authUser.Name)
+
+	var user model.User
+	err := r.DB.GetContext(ctx, &user, `
+		INSERT INTO users (email, first_name, last_name, role, firebase_uid)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT DO NOTHING
+		RETURNING `+userColumns,
+		authUser.Email, firstName, lastName, model.UserRoleEmployee, authUser.UID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// A concurrent request provisioned the account first, or the email
+		// is taken
+		err = r.DB.GetContext(ctx, &user, `SELECT `+userColumns+` FROM users WHERE firebase_uid = $1`, authUser.UID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, conflictError("the email %s already belongs to another account; sign in with that account or ask an administrator to link this one", authUser.Email)
+		}
+	}
+	if err != nil {
+		return nil, dbError(err, "provision user")
+	}
+	return &user, nil
+}
+
+// splitName splits a display name into first and last name at the first
+// space. Either part is nil when missing.
+func splitName(name string) (first, last *string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	parts := strings.SplitN(name, " ", 2)
+	first = &parts[0]
+	if len(parts) == 2 {
+		rest := strings.TrimSpace(parts[1])
+		last = &rest
+	}
+	return first, last
+}
+
+// requireViewer is like viewer but fails when there is no authenticated profile
+func (r *Resolver) requireViewer(ctx context.Context) (*model.User, error) {
+	if auth.ForContext(ctx) == nil {
+		return nil, unauthenticatedError("authentication required")
+	}
+	user, err := r.viewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, unauthenticatedError("no user profile exists for this account; call createUser first")
+	}
+	return user, nil
+}
+
+// requireSelfOrAdmin fails unless the viewer is the given user or an ADMIN
+func (r *Resolver) requireSelfOrAdmin(ctx context.Context, userID string) (*model.User, error) {
+	viewer, err := r.requireViewer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if viewer.ID != userID && viewer.Role != model.UserRoleAdmin {
+		return nil, forbiddenError("you can only access your own user data")
+	}
+	return viewer, nil
+}
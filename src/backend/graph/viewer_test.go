@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitName(t *testing.T) {
+	first, last := splitName("  Mary Jane  Watson ")
+	if assert.NotNil(t, first) && assert.NotNil(t, last) {
+		assert.Equal(t, "Mary", *first)
+		assert.Equal(t, "Jane  Watson", *last)
+	}
+
+	first, last = splitName("Cher")
+	if assert.NotNil(t, first) {
+		assert.Equal(t, "Cher", *first)
+	}
+	assert.Nil(t, last)
+
+	first, last = splitName("")
+	assert.Nil(t, first)
+	assert.Nil(t, last)
+}
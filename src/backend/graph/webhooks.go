@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"budsafe/backend/graph/model"
+	"budsafe/backend/webhook"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// webhookEventNames name the event types as payloads and the database do
+var webhookEventNames = map[model.WebhookEventType]string{
+	model.WebhookEventTypeLicenseStatusChanged:  webhook.EventLicenseStatusChanged,
+	model.WebhookEventTypeComplianceCheckFailed: webhook.EventComplianceCheckFailed,
+	model.WebhookEventTypeNotificationCreated:   webhook.EventNotificationCreated,
+}
+
+// webhookEventType is the enum value of an event name
+func webhookEventType(name string) model.WebhookEventType {
+	for typ, n := range webhookEventNames {
+		if n == name {
+			return typ
+		}
+	}
+	return model.WebhookEventType(strings.ToUpper(name))
+}
+
+// webhookEventNamesOf checks the event types of a subscription input and
+// returns their names, each once
+func webhookEventNamesOf(types []model.WebhookEventType) (pq.StringArray, error) {
+	if len(types) == 0 {
+		return nil, validationError("eventTypes", "eventTypes must not be empty")
+	}
+	names := pq.StringArray{}
+	for _, typ := range types {
+		name, ok := webhookEventNames[typ]
+		if !ok {
+			return nil, validationError("eventTypes", "unknown event type %s", typ)
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// checkWebhookURL fails validation of url unless webhooks can be sent to it
+func (r *Resolver) checkWebhookURL(url string) error {
+	if err := webhook.CheckURL(url, r.AllowLocalWebhooks); err != nil {
+		return validationError("url", "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"testing"
+
+	"budsafe/backend/graph/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookEventNames(t *testing.T) {
+	for _, typ := range model.AllWebhookEventType {
+		name, ok := webhookEventNames[typ]
+		require.True(t, ok, typ)
+		assert.Equal(t, typ, webhookEventType(name))
+	}
+
+	names, err := webhookEventNamesOf([]model.WebhookEventType{
+		model.WebhookEventTypeComplianceCheckFailed,
+		model.WebhookEventTypeLicenseStatusChanged,
+		model.WebhookEventTypeComplianceCheckFailed,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"compliance_check.failed", "license.status_changed"}, []string(names))
+
+	_, err = webhookEventNamesOf(nil)
+	assert.Error(t, err)
+}
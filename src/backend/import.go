@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"budsafe/backend/auth"
+	"budsafe/backend/graph"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+const importUsage = `usage: budsafe import-licenses -as <email> [-dry-run] <file.csv|file.xlsx>
+
+Creates a license for each row of the file, all or none, acting as the
+user with the email. See LicenseImportResult in the GraphQL schema for
+the columns.`
+
+// runImportLicenses implements the `budsafe import-licenses` subcommand
+func runImportLicenses(args []string) error {
+	flags := flag.NewFlagSet("import-licenses", flag.ContinueOnError)
+	as := flags.String("as", "", "email of the user the licenses are imported as")
+	dryRun := flags.Bool("dry-run", false, "check every row without importing")
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), importUsage) }
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *as == "" || flags.NArg() != 1 {
+		return errors.New(importUsage)
+	}
+
+	path := flags.Arg(0)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	db := connectDB()
+	defer db.Close()
+	ctx := context.Background()
+
+	// Act as the user, so that their permissions apply and the audit log
+	// names them
+	var uid string
+	if err := db.GetContext(ctx, &uid, "SELECT firebase_uid FROM users WHERE lower(email) = lower($1)", *as); err != nil {
+		return fmt.Errorf("no user with email %s: %w", *as, err)
+	}
+	ctx = auth.NewContext(ctx, &auth.User{UID: uid, Email: *as})
+
+	resolver := &graph.Resolver{DB: db}
+	result, err := resolver.Mutation().ImportLicenses(ctx, graphql.Upload{
+		File:     file,
+		Filename: filepath.Base(path),
+		Size:     info.Size(),
+	}, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ROW\tFIELD\tERROR")
+		for _, e := range result.Errors {
+			field := "-"
+			if e.Field != nil {
+				field = *e.Field
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", e.Row, field, e.Message)
+		}
+		w.Flush()
+		return fmt.Errorf("%d of %d row(s) have errors; nothing was imported", result.RowCount-result.ValidCount, result.RowCount)
+	}
+	if *dryRun {
+		log.Printf("All %d row(s) can be imported", result.RowCount)
+		return nil
+	}
+	log.Printf("Imported %d license(s)", len(result.Licenses))
+	return nil
+}
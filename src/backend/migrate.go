@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"budsafe/backend/migrations"
+)
+
+const migrateUsage = `usage: budsafe migrate <command> [flags]
+
+commands:
+  up                apply all pending migrations
+  down [-steps N]   roll back the last N migrations (default 1)
+  status            list migrations and whether they are applied
+  new <name>        create an empty up/down migration pair in ./migrations`
+
+// runMigrate implements the `budsafe migrate` subcommand
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	command, args := args[0], args[1:]
+	if command == "new" {
+		if len(args) != 1 {
+			return errors.New("usage: budsafe migrate new <name>")
+		}
+		paths, err := migrations.Create("migrations", args[0])
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			log.Printf("Created %s", path)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+
+	switch command {
+	case "up":
+		migrator := newMigrator()
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("Applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Println("Database is already up to date")
+		}
+		return nil
+
+	case "down":
+		flags := flag.NewFlagSet("down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		if *steps < 1 {
+			return errors.New("-steps must be at least 1")
+		}
+		migrator := newMigrator()
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			log.Printf("Rolled back %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			log.Println("No applied migrations to roll back")
+		}
+		return nil
+
+	case "status":
+		migrator := newMigrator()
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT\tCHECKSUM")
+		for _, s := range statuses {
+			appliedAt, check := "pending", "-"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+				check = "ok"
+				if s.Mismatch {
+					check = "MISMATCH"
+				}
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, appliedAt, check)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n\n%s", command, migrateUsage)
+	}
+}
+
+func newMigrator() *migrations.Migrator {
+	migrator, err := migrations.New(connectDB())
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator
+}
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS documents;
+DROP TABLE IF EXISTS renewal_requirements;
+DROP TABLE IF EXISTS compliance_checks;
+DROP TABLE IF EXISTS licenses;
+DROP TABLE IF EXISTS regulations;
+DROP TABLE IF EXISTS jurisdictions;
+DROP TABLE IF EXISTS locations;
+DROP TABLE IF EXISTS businesses;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,158 @@
+-- Baseline schema for the tables queried by the graph package.
+--
+-- Column names follow what the resolvers already select, which does not
+-- always match the GraphQL field names:
+--   licenses.type                     -> License.licenseType
+--   compliance_checks.check_type      -> ComplianceCheck.title
+--   compliance_checks.next_check_date -> ComplianceCheck.dueDate
+--   compliance_checks.checked_by_id   -> ComplianceCheck.userId
+--   renewal_requirements.due_date     -> RenewalRequirement.deadline
+--   renewal_requirements.completed_at -> RenewalRequirement.isCompleted (set = completed)
+
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE users (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    firebase_uid TEXT UNIQUE,
+    email        TEXT NOT NULL UNIQUE,
+    first_name   TEXT,
+    last_name    TEXT,
+    role         TEXT NOT NULL DEFAULT 'EMPLOYEE'
+                 CHECK (role IN ('ADMIN', 'BUSINESS_OWNER', 'COMPLIANCE_MANAGER', 'EMPLOYEE')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE businesses (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name        TEXT NOT NULL,
+    type        TEXT NOT NULL
+                CHECK (type IN ('CULTIVATOR', 'PROCESSOR', 'DISTRIBUTOR', 'RETAILER', 'TESTING_LAB', 'DELIVERY', 'INTEGRATED')),
+    description TEXT,
+    owner_id    UUID NOT NULL REFERENCES users (id),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX businesses_owner_id_idx ON businesses (owner_id);
+
+CREATE TABLE locations (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    address     TEXT NOT NULL,
+    city        TEXT NOT NULL,
+    state       TEXT NOT NULL,
+    zip_code    TEXT NOT NULL,
+    is_primary  BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX locations_business_id_idx ON locations (business_id);
+-- At most one primary location per business
+CREATE UNIQUE INDEX locations_one_primary_idx ON locations (business_id) WHERE is_primary;
+
+CREATE TABLE jurisdictions (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name               TEXT NOT NULL UNIQUE,
+    type               TEXT NOT NULL CHECK (type IN ('US_STATE', 'CANADIAN_PROVINCE', 'COUNTRY')),
+    country            TEXT NOT NULL,
+    regulatory_body    TEXT NOT NULL,
+    regulatory_website TEXT,
+    license_types      TEXT[] NOT NULL DEFAULT '{}',
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE regulations (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    jurisdiction_id   UUID NOT NULL REFERENCES jurisdictions (id) ON DELETE CASCADE,
+    title             TEXT NOT NULL,
+    description       TEXT NOT NULL,
+    category          TEXT NOT NULL
+                      CHECK (category IN ('LICENSING', 'TESTING', 'PACKAGING', 'LABELING', 'TRACKING', 'SECURITY', 'TRANSPORTATION', 'ADVERTISING', 'TAXATION')),
+    effective_date    DATE NOT NULL,
+    requirements      JSONB,
+    documentation_url TEXT,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX regulations_jurisdiction_id_idx ON regulations (jurisdiction_id);
+
+CREATE TABLE licenses (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id     UUID NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    jurisdiction_id UUID NOT NULL REFERENCES jurisdictions (id),
+    location_id     UUID REFERENCES locations (id) ON DELETE SET NULL,
+    license_number  TEXT NOT NULL,
+    type            TEXT NOT NULL
+                    CHECK (type IN ('CULTIVATION', 'MANUFACTURING', 'DISTRIBUTION', 'RETAIL', 'DELIVERY', 'TESTING', 'MICROBUSINESS', 'RESEARCH', 'TRANSPORTATION', 'NURSERY')),
+    status          TEXT NOT NULL
+                    CHECK (status IN ('ACTIVE', 'PENDING', 'EXPIRED', 'REVOKED', 'SUSPENDED', 'RENEWAL_IN_PROGRESS')),
+    issued_date     DATE NOT NULL,
+    expiration_date DATE NOT NULL,
+    renewal_date    DATE,
+    fee_amount      NUMERIC(12, 2),
+    notes           TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (expiration_date >= issued_date),
+    UNIQUE (jurisdiction_id, license_number)
+);
+CREATE INDEX licenses_business_id_idx ON licenses (business_id);
+CREATE INDEX licenses_location_id_idx ON licenses (location_id);
+CREATE INDEX licenses_expiration_date_idx ON licenses (expiration_date);
+
+CREATE TABLE compliance_checks (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id      UUID NOT NULL REFERENCES licenses (id) ON DELETE CASCADE,
+    check_type      TEXT NOT NULL,
+    status          TEXT NOT NULL
+                    CHECK (status IN ('COMPLIANT', 'NON_COMPLIANT', 'PENDING_REVIEW', 'NEEDS_ATTENTION', 'NOT_APPLICABLE')),
+    checked_at      TIMESTAMPTZ,
+    next_check_date TIMESTAMPTZ NOT NULL,
+    notes           TEXT,
+    checked_by_id   UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX compliance_checks_license_id_idx ON compliance_checks (license_id);
+
+CREATE TABLE renewal_requirements (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id   UUID NOT NULL REFERENCES licenses (id) ON DELETE CASCADE,
+    description  TEXT NOT NULL,
+    due_date     DATE,
+    completed_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX renewal_requirements_license_id_idx ON renewal_requirements (license_id);
+
+CREATE TABLE documents (
+    id                     UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name                   TEXT NOT NULL,
+    description            TEXT,
+    file_url               TEXT NOT NULL,
+    file_type              TEXT NOT NULL,
+    uploaded_by_id         UUID NOT NULL REFERENCES users (id),
+    license_id             UUID REFERENCES licenses (id) ON DELETE CASCADE,
+    renewal_requirement_id UUID REFERENCES renewal_requirements (id) ON DELETE CASCADE,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX documents_license_id_idx ON documents (license_id);
+CREATE INDEX documents_renewal_requirement_id_idx ON documents (renewal_requirement_id);
+
+CREATE TABLE notifications (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id             UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    title               TEXT NOT NULL,
+    message             TEXT NOT NULL,
+    type                TEXT NOT NULL
+                        CHECK (type IN ('LICENSE_EXPIRING', 'RENEWAL_DUE', 'COMPLIANCE_ISSUE', 'DOCUMENT_REQUIRED', 'REGULATION_UPDATE')),
+    related_entity_id   TEXT,
+    related_entity_type TEXT,
+    is_read             BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC);
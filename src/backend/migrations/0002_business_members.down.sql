@@ -0,0 +1,33 @@
+DROP POLICY IF EXISTS notifications_recipient ON notifications;
+ALTER TABLE notifications NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE notifications DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS documents_tenant ON documents;
+ALTER TABLE documents NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE documents DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS renewal_requirements_tenant ON renewal_requirements;
+ALTER TABLE renewal_requirements NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE renewal_requirements DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS compliance_checks_tenant ON compliance_checks;
+ALTER TABLE compliance_checks NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE compliance_checks DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS licenses_tenant ON licenses;
+ALTER TABLE licenses NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE licenses DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS locations_tenant ON locations;
+ALTER TABLE locations NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE locations DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS businesses_tenant ON businesses;
+ALTER TABLE businesses NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE businesses DISABLE ROW LEVEL SECURITY;
+
+DROP FUNCTION IF EXISTS app_can_access_business(UUID);
+DROP FUNCTION IF EXISTS app_is_admin();
+DROP FUNCTION IF EXISTS app_current_user_id();
+
+DROP TABLE IF EXISTS business_members;
@@ -0,0 +1,96 @@
+-- Business membership and row-level tenant isolation.
+--
+-- Every user <-> business relationship lives in business_members with a
+-- per-business role. The graph package runs each request in a transaction
+-- that sets app.user_id and app.user_role (see Resolver.withTx), and the
+-- policies below restrict rows to businesses the caller is a member of.
+--
+-- Connections that never set app.user_id (migrations, background jobs)
+-- are treated as trusted and see every row. Note that superusers and roles
+-- with BYPASSRLS skip these policies entirely, so the application should
+-- connect as an ordinary role; the resolvers also filter explicitly with
+-- app_can_access_business() so isolation holds either way.
+
+CREATE TABLE business_members (
+    business_id UUID NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role        TEXT NOT NULL CHECK (role IN ('BUSINESS_OWNER', 'COMPLIANCE_MANAGER', 'EMPLOYEE')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (business_id, user_id)
+);
+CREATE INDEX business_members_user_id_idx ON business_members (user_id);
+
+-- Existing owners become members of their businesses
+INSERT INTO business_members (business_id, user_id, role)
+SELECT id, owner_id, 'BUSINESS_OWNER' FROM businesses;
+
+CREATE FUNCTION app_current_user_id() RETURNS UUID
+LANGUAGE sql STABLE AS $$
+    SELECT NULLIF(current_setting('app.user_id', true), '')::uuid
+$$;
+
+CREATE FUNCTION app_is_admin() RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT COALESCE(current_setting('app.user_role', true), '') = 'ADMIN'
+$$;
+
+CREATE FUNCTION app_can_access_business(target UUID) RETURNS BOOLEAN
+LANGUAGE sql STABLE AS $$
+    SELECT app_current_user_id() IS NULL
+        OR app_is_admin()
+        OR EXISTS (
+            SELECT 1 FROM business_members m
+            WHERE m.business_id = target AND m.user_id = app_current_user_id()
+        )
+$$;
+
+ALTER TABLE businesses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE businesses FORCE ROW LEVEL SECURITY;
+-- The owner check lets a new business be inserted (and returned) before its membership row exists
+CREATE POLICY businesses_tenant ON businesses
+    USING (owner_id = app_current_user_id() OR app_can_access_business(id))
+    WITH CHECK (owner_id = app_current_user_id() OR app_can_access_business(id));
+
+ALTER TABLE locations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE locations FORCE ROW LEVEL SECURITY;
+CREATE POLICY locations_tenant ON locations
+    USING (app_can_access_business(business_id))
+    WITH CHECK (app_can_access_business(business_id));
+
+ALTER TABLE licenses ENABLE ROW LEVEL SECURITY;
+ALTER TABLE licenses FORCE ROW LEVEL SECURITY;
+CREATE POLICY licenses_tenant ON licenses
+    USING (app_can_access_business(business_id))
+    WITH CHECK (app_can_access_business(business_id));
+
+-- Child tables of licenses are visible when their license is; the subqueries
+-- are themselves filtered by the licenses policy.
+ALTER TABLE compliance_checks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE compliance_checks FORCE ROW LEVEL SECURITY;
+CREATE POLICY compliance_checks_tenant ON compliance_checks
+    USING (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id));
+
+ALTER TABLE renewal_requirements ENABLE ROW LEVEL SECURITY;
+ALTER TABLE renewal_requirements FORCE ROW LEVEL SECURITY;
+CREATE POLICY renewal_requirements_tenant ON renewal_requirements
+    USING (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id));
+
+ALTER TABLE documents ENABLE ROW LEVEL SECURITY;
+ALTER TABLE documents FORCE ROW LEVEL SECURITY;
+CREATE POLICY documents_tenant ON documents
+    USING (
+        EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id)
+        OR EXISTS (SELECT 1 FROM renewal_requirements rr WHERE rr.id = renewal_requirement_id)
+    )
+    WITH CHECK (
+        EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id)
+        OR EXISTS (SELECT 1 FROM renewal_requirements rr WHERE rr.id = renewal_requirement_id)
+    );
+
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notifications FORCE ROW LEVEL SECURITY;
+CREATE POLICY notifications_recipient ON notifications
+    USING (app_current_user_id() IS NULL OR app_is_admin() OR user_id = app_current_user_id());
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS compliance_checks_notify_status_changed ON compliance_checks;
+DROP FUNCTION IF EXISTS notify_compliance_status_changed();
+
+DROP TRIGGER IF EXISTS licenses_notify_status_changed ON licenses;
+DROP FUNCTION IF EXISTS notify_license_status_changed();
+
+DROP TRIGGER IF EXISTS notifications_notify_added ON notifications;
+DROP FUNCTION IF EXISTS notify_notification_added();
@@ -0,0 +1,49 @@
+-- NOTIFY triggers feeding the GraphQL subscriptions (see package pubsub).
+--
+-- Each trigger publishes {"id", "user_id" | "business_id"} on the channel
+-- named after its topic. Notifications are delivered when the writing
+-- transaction commits, to every backend replica that LISTENs.
+
+CREATE FUNCTION notify_notification_added() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM pg_notify('notification_added',
+        json_build_object('id', NEW.id, 'user_id', NEW.user_id)::text);
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER notifications_notify_added
+AFTER INSERT ON notifications
+FOR EACH ROW EXECUTE FUNCTION notify_notification_added();
+
+CREATE FUNCTION notify_license_status_changed() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM pg_notify('license_status_changed',
+        json_build_object('id', NEW.id, 'business_id', NEW.business_id)::text);
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER licenses_notify_status_changed
+AFTER UPDATE OF status ON licenses
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION notify_license_status_changed();
+
+CREATE FUNCTION notify_compliance_status_changed() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM pg_notify('compliance_status_changed',
+        json_build_object(
+            'id', NEW.id,
+            'business_id', (SELECT business_id FROM licenses WHERE id = NEW.license_id)
+        )::text);
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER compliance_checks_notify_status_changed
+AFTER UPDATE OF status ON compliance_checks
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION notify_compliance_status_changed();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS notifications_user_id_dedupe_key_key;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS dedupe_key;
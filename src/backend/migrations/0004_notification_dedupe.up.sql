@@ -0,0 +1,6 @@
+-- Background jobs tag the notifications they generate with a dedupe_key so
+-- that re-running a scan never notifies the same user twice about the same
+-- event. Notifications created through the API leave it NULL.
+ALTER TABLE notifications ADD COLUMN dedupe_key TEXT;
+
+CREATE UNIQUE INDEX notifications_user_id_dedupe_key_key ON notifications (user_id, dedupe_key);
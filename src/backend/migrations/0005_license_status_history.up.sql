@@ -0,0 +1,23 @@
+-- Append-only record of every license status change. changed_by_id is NULL
+-- for changes made by the system (e.g. the expiry job); from_status is NULL
+-- for the status a license was created with.
+CREATE TABLE license_status_history (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id    UUID NOT NULL REFERENCES licenses (id) ON DELETE CASCADE,
+    from_status   TEXT,
+    to_status     TEXT NOT NULL,
+    reason        TEXT,
+    changed_by_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    changed_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX license_status_history_license_id_idx ON license_status_history (license_id, changed_at);
+
+ALTER TABLE license_status_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE license_status_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY license_status_history_tenant ON license_status_history
+    USING (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id));
+
+-- Existing licenses start their history with their current status
+INSERT INTO license_status_history (license_id, to_status, changed_at)
+SELECT id, status, created_at FROM licenses;
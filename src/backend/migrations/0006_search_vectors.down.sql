@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS locations_state_idx;
+
+ALTER TABLE licenses DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE businesses DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,12 @@
+-- Full-text search for the businesses and licenses list filters.
+-- License numbers and notes use the 'simple' configuration so that
+-- identifiers are not stemmed.
+ALTER TABLE businesses ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', name || ' ' || COALESCE(description, ''))) STORED;
+CREATE INDEX businesses_search_vector_idx ON businesses USING GIN (search_vector);
+
+ALTER TABLE licenses ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', license_number || ' ' || COALESCE(notes, ''))) STORED;
+CREATE INDEX licenses_search_vector_idx ON licenses USING GIN (search_vector);
+
+CREATE INDEX locations_state_idx ON locations (upper(state));
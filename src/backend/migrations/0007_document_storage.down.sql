@@ -0,0 +1,9 @@
+-- Keep uploaded documents as rows pointing at their storage key
+UPDATE documents SET file_url = 'storage:' || storage_key WHERE file_url IS NULL;
+
+ALTER TABLE documents
+    DROP CONSTRAINT IF EXISTS documents_file_source_check,
+    DROP COLUMN IF EXISTS checksum_sha256,
+    DROP COLUMN IF EXISTS file_size,
+    DROP COLUMN IF EXISTS storage_key,
+    ALTER COLUMN file_url SET NOT NULL;
@@ -0,0 +1,9 @@
+-- Uploaded document files live in a storage backend under storage_key and
+-- are downloaded through signed URLs. file_url remains for documents that
+-- link to an external file; every document has exactly one of the two.
+ALTER TABLE documents
+    ALTER COLUMN file_url DROP NOT NULL,
+    ADD COLUMN storage_key     TEXT UNIQUE,
+    ADD COLUMN file_size       BIGINT CHECK (file_size >= 0),
+    ADD COLUMN checksum_sha256 TEXT CHECK (checksum_sha256 ~ '^[0-9a-f]{64}$'),
+    ADD CONSTRAINT documents_file_source_check CHECK ((file_url IS NULL) <> (storage_key IS NULL));
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS documents_default_series ON documents;
+DROP FUNCTION IF EXISTS documents_default_series();
+
+DROP INDEX IF EXISTS documents_current_valid_until_idx;
+DROP INDEX IF EXISTS documents_series_id_version_key;
+
+ALTER TABLE documents
+    DROP CONSTRAINT IF EXISTS documents_validity_check,
+    DROP COLUMN IF EXISTS superseded_by_id,
+    DROP COLUMN IF EXISTS valid_until,
+    DROP COLUMN IF EXISTS valid_from,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS series_id;
@@ -0,0 +1,30 @@
+-- Replaced evidence (a renewed insurance certificate, a new certificate of
+-- analysis) is kept as numbered versions of one logical document. The
+-- versions share series_id, the id of the first version, and each one
+-- points at its successor; the current version has superseded_by_id NULL.
+ALTER TABLE documents
+    ADD COLUMN series_id        UUID,
+    ADD COLUMN version          INT NOT NULL DEFAULT 1 CHECK (version > 0),
+    ADD COLUMN valid_from       DATE,
+    ADD COLUMN valid_until      DATE,
+    ADD COLUMN superseded_by_id UUID REFERENCES documents (id) ON DELETE SET NULL,
+    ADD CONSTRAINT documents_validity_check CHECK (valid_until >= valid_from);
+
+UPDATE documents SET series_id = id;
+ALTER TABLE documents ALTER COLUMN series_id SET NOT NULL;
+
+CREATE UNIQUE INDEX documents_series_id_version_key ON documents (series_id, version);
+CREATE INDEX documents_current_valid_until_idx ON documents (valid_until) WHERE superseded_by_id IS NULL;
+
+-- A document created without a series starts its own
+CREATE FUNCTION documents_default_series() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.series_id := COALESCE(NEW.series_id, NEW.id);
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER documents_default_series
+BEFORE INSERT ON documents
+FOR EACH ROW EXECUTE FUNCTION documents_default_series();
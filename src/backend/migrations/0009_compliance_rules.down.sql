@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS compliance_checks_license_id_regulation_id_rule_clause_key;
+
+ALTER TABLE compliance_checks
+    DROP CONSTRAINT IF EXISTS compliance_checks_rule_check,
+    DROP COLUMN IF EXISTS rule_clause,
+    DROP COLUMN IF EXISTS regulation_id;
+
+ALTER TABLE documents DROP COLUMN IF EXISTS category;
@@ -0,0 +1,16 @@
+-- Documents are tagged with a category so that regulation requirements can
+-- ask for evidence of a kind ("a SECURITY document less than a year old").
+ALTER TABLE documents ADD COLUMN category TEXT
+    CHECK (category IN ('SECURITY', 'INSURANCE', 'CERTIFICATE_OF_ANALYSIS', 'SITE_PLAN',
+                        'OPERATING_PROCEDURES', 'TRAINING', 'PERMIT', 'OTHER'));
+
+-- Compliance checks produced by the rules engine (package rules) record the
+-- regulation and clause they evaluate; there is one per license and clause.
+-- Checks entered by people leave both NULL.
+ALTER TABLE compliance_checks
+    ADD COLUMN regulation_id UUID REFERENCES regulations (id) ON DELETE CASCADE,
+    ADD COLUMN rule_clause   TEXT,
+    ADD CONSTRAINT compliance_checks_rule_check CHECK ((regulation_id IS NULL) = (rule_clause IS NULL));
+
+CREATE UNIQUE INDEX compliance_checks_license_id_regulation_id_rule_clause_key
+    ON compliance_checks (license_id, regulation_id, rule_clause);
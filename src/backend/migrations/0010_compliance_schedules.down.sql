@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS compliance_checks_schedule_id_occurrence_at_key;
+
+ALTER TABLE compliance_checks
+    DROP CONSTRAINT IF EXISTS compliance_checks_occurrence_check,
+    DROP COLUMN IF EXISTS occurrence_at,
+    DROP COLUMN IF EXISTS schedule_id;
+
+DROP TABLE IF EXISTS compliance_schedules;
@@ -0,0 +1,34 @@
+-- Recurring compliance checks. A schedule repeats a check on a license by an
+-- iCalendar RRULE counted from starts_at; the recurrence package materializes
+-- one compliance check per occurrence, the next once the previous is
+-- completed or the occurrence comes within lead_days.
+CREATE TABLE compliance_schedules (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id     UUID NOT NULL REFERENCES licenses (id) ON DELETE CASCADE,
+    title          TEXT NOT NULL,
+    notes          TEXT,
+    rrule          TEXT NOT NULL,
+    starts_at      TIMESTAMPTZ NOT NULL,
+    lead_days      INTEGER NOT NULL DEFAULT 0 CHECK (lead_days >= 0),
+    assigned_to_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    active         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX compliance_schedules_license_id_idx ON compliance_schedules (license_id);
+
+ALTER TABLE compliance_schedules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE compliance_schedules FORCE ROW LEVEL SECURITY;
+CREATE POLICY compliance_schedules_tenant ON compliance_schedules
+    USING (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id));
+
+-- occurrence_at is the occurrence a check was materialized for; its due
+-- date starts out the same but may be moved.
+ALTER TABLE compliance_checks
+    ADD COLUMN schedule_id   UUID REFERENCES compliance_schedules (id) ON DELETE SET NULL,
+    ADD COLUMN occurrence_at TIMESTAMPTZ,
+    ADD CONSTRAINT compliance_checks_occurrence_check CHECK (schedule_id IS NULL OR occurrence_at IS NOT NULL);
+
+CREATE UNIQUE INDEX compliance_checks_schedule_id_occurrence_at_key
+    ON compliance_checks (schedule_id, occurrence_at);
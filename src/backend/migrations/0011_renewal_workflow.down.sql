@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS renewal_requirements_renewal_id_idx;
+ALTER TABLE renewal_requirements
+    DROP COLUMN IF EXISTS document_category,
+    DROP COLUMN IF EXISTS renewal_id;
+
+DROP TABLE IF EXISTS renewals;
+DROP TABLE IF EXISTS renewal_template_items;
+DROP TABLE IF EXISTS renewal_templates;
@@ -0,0 +1,61 @@
+-- Renewal workflow. A license entering RENEWAL_IN_PROGRESS opens a renewal
+-- whose checklist of renewal requirements is copied from the renewal
+-- template of its jurisdiction and license type. Once every requirement is
+-- complete and the new permit has been recorded on the renewal, the license
+-- returns to ACTIVE with the new permit's dates.
+
+-- Templates are reference data maintained by admins, like regulations. A
+-- template without a license type applies to every type in its jurisdiction.
+CREATE TABLE renewal_templates (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    jurisdiction_id UUID NOT NULL REFERENCES jurisdictions (id) ON DELETE CASCADE,
+    license_type    TEXT,
+    name            TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX renewal_templates_jurisdiction_id_license_type_key
+    ON renewal_templates (jurisdiction_id, COALESCE(license_type, ''));
+
+-- due_days_before is counted back from the expiration date of the license
+-- being renewed; document_category, when set, makes a current document of
+-- that category a condition of completing the requirement.
+CREATE TABLE renewal_template_items (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id       UUID NOT NULL REFERENCES renewal_templates (id) ON DELETE CASCADE,
+    position          INTEGER NOT NULL,
+    description       TEXT NOT NULL,
+    due_days_before   INTEGER CHECK (due_days_before >= 0),
+    document_category TEXT,
+    UNIQUE (template_id, position)
+);
+
+CREATE TABLE renewals (
+    id                      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    license_id              UUID NOT NULL REFERENCES licenses (id) ON DELETE CASCADE,
+    template_id             UUID REFERENCES renewal_templates (id) ON DELETE SET NULL,
+    status                  TEXT NOT NULL DEFAULT 'IN_PROGRESS'
+                            CHECK (status IN ('IN_PROGRESS', 'COMPLETED', 'CANCELLED')),
+    previous_expiration_date DATE NOT NULL,
+    new_license_number      TEXT,
+    new_issued_date         DATE,
+    new_expiration_date     DATE,
+    permit_recorded_at      TIMESTAMPTZ,
+    completed_at            TIMESTAMPTZ,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (new_expiration_date >= new_issued_date)
+);
+-- A license has at most one renewal in progress
+CREATE UNIQUE INDEX renewals_license_id_in_progress_key ON renewals (license_id) WHERE status = 'IN_PROGRESS';
+
+ALTER TABLE renewals ENABLE ROW LEVEL SECURITY;
+ALTER TABLE renewals FORCE ROW LEVEL SECURITY;
+CREATE POLICY renewals_tenant ON renewals
+    USING (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id));
+
+ALTER TABLE renewal_requirements
+    ADD COLUMN renewal_id        UUID REFERENCES renewals (id) ON DELETE SET NULL,
+    ADD COLUMN document_category TEXT;
+CREATE INDEX renewal_requirements_renewal_id_idx ON renewal_requirements (renewal_id);
@@ -0,0 +1 @@
+ALTER TABLE jurisdictions DROP COLUMN IF EXISTS license_number_formats;
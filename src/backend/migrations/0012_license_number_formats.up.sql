@@ -0,0 +1,8 @@
+-- License number formats. Next to the license types a jurisdiction issues,
+-- it records how their license numbers are formed: a JSON list of formats
+-- (prefix, pattern and check digit) validated by package numbering. A
+-- format listing license types applies to those types, one without to the
+-- rest. License numbers of a jurisdiction without formats are not checked.
+ALTER TABLE jurisdictions
+    ADD COLUMN license_number_formats JSONB NOT NULL DEFAULT '[]'
+        CHECK (jsonb_typeof(license_number_formats) = 'array');
@@ -0,0 +1,24 @@
+-- Calendar feeds. A user subscribes their calendar app to a business's
+-- compliance deadlines at /calendar/{token}.ics. The token is only shown
+-- when the feed is created; token_hash is its SHA-256. A revoked feed stops
+-- serving, and so does one whose user is no longer a member of the business.
+CREATE TABLE calendar_feeds (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    business_id  UUID NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    token_hash   TEXT NOT NULL UNIQUE,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX calendar_feeds_user_id_business_id_idx ON calendar_feeds (user_id, business_id);
+
+-- Feeds are private to their user; the feed handler looks tokens up
+-- unscoped and then reads the business as the feed's user.
+ALTER TABLE calendar_feeds ENABLE ROW LEVEL SECURITY;
+ALTER TABLE calendar_feeds FORCE ROW LEVEL SECURITY;
+CREATE POLICY calendar_feeds_owner ON calendar_feeds
+    USING (app_current_user_id() IS NULL OR app_is_admin() OR user_id = app_current_user_id())
+    WITH CHECK (app_current_user_id() IS NULL OR app_is_admin() OR user_id = app_current_user_id());
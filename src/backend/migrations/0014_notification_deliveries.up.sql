@@ -0,0 +1,29 @@
+-- Email delivery of notifications. The email package queues one delivery
+-- per notification and channel and records each attempt: PENDING until the
+-- first, RETRYING after a temporary failure until next_attempt_at, and then
+-- SENT, BOUNCED when the server rejected the message for good, or FAILED
+-- once out of attempts.
+CREATE TABLE notification_deliveries (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    notification_id UUID NOT NULL REFERENCES notifications (id) ON DELETE CASCADE,
+    channel         TEXT NOT NULL CHECK (channel IN ('EMAIL')),
+    recipient       TEXT NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'PENDING'
+                    CHECK (status IN ('PENDING', 'RETRYING', 'SENT', 'BOUNCED', 'FAILED')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error      TEXT,
+    sent_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (notification_id, channel)
+);
+CREATE INDEX notification_deliveries_due_idx ON notification_deliveries (next_attempt_at)
+    WHERE status IN ('PENDING', 'RETRYING');
+
+-- Deliveries are visible with their notification
+ALTER TABLE notification_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notification_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY notification_deliveries_recipient ON notification_deliveries
+    USING (EXISTS (SELECT 1 FROM notifications n WHERE n.id = notification_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM notifications n WHERE n.id = notification_id));
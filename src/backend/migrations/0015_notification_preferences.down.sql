@@ -0,0 +1,4 @@
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS digest;
+DROP TRIGGER IF EXISTS notifications_apply_preferences ON notifications;
+DROP FUNCTION IF EXISTS notifications_apply_preferences();
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,53 @@
+-- Notification preferences (see package notify). A user without a row gets
+-- every notification in the app and by email, immediately, in UTC.
+--
+-- channels maps notification types to the channels they are delivered on,
+-- e.g. {"LICENSE_EXPIRING": ["IN_APP"]}; types that are not listed go to
+-- every channel. Emails due in quiet hours wait until they end, and digest
+-- users get their emails batched at digest_hour, on digest_weekday
+-- (0 = Sunday) for WEEKLY digests.
+CREATE TABLE notification_preferences (
+    user_id           UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    timezone          TEXT NOT NULL DEFAULT 'UTC',
+    quiet_hours_start TIME,
+    quiet_hours_end   TIME,
+    digest            TEXT NOT NULL DEFAULT 'IMMEDIATE'
+                      CHECK (digest IN ('IMMEDIATE', 'DAILY', 'WEEKLY')),
+    digest_hour       SMALLINT NOT NULL DEFAULT 8 CHECK (digest_hour BETWEEN 0 AND 23),
+    digest_weekday    SMALLINT NOT NULL DEFAULT 1 CHECK (digest_weekday BETWEEN 0 AND 6),
+    channels          JSONB NOT NULL DEFAULT '{}' CHECK (jsonb_typeof(channels) = 'object'),
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((quiet_hours_start IS NULL) = (quiet_hours_end IS NULL))
+);
+
+ALTER TABLE notification_preferences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notification_preferences FORCE ROW LEVEL SECURITY;
+CREATE POLICY notification_preferences_owner ON notification_preferences
+    USING (app_current_user_id() IS NULL OR app_is_admin() OR user_id = app_current_user_id())
+    WITH CHECK (app_current_user_id() IS NULL OR app_is_admin() OR user_id = app_current_user_id());
+
+-- A notification of a type the user turned off in the app is stored as
+-- read, so that it neither counts as unread nor reaches subscriptions, but
+-- can still be emailed.
+CREATE FUNCTION notifications_apply_preferences() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM notification_preferences p
+        WHERE p.user_id = NEW.user_id
+          AND p.channels ? NEW.type
+          AND NOT COALESCE((p.channels -> NEW.type) ? 'IN_APP', FALSE)
+    ) THEN
+        NEW.is_read := TRUE;
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER notifications_apply_preferences
+BEFORE INSERT ON notifications
+FOR EACH ROW EXECUTE FUNCTION notifications_apply_preferences();
+
+-- Digest deliveries are sent together, in one email per user
+ALTER TABLE notification_deliveries ADD COLUMN digest BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS notifications_webhook_created ON notifications;
+DROP TRIGGER IF EXISTS compliance_checks_webhook_failed ON compliance_checks;
+DROP TRIGGER IF EXISTS licenses_webhook_status_changed ON licenses;
+DROP FUNCTION IF EXISTS webhook_notification_created();
+DROP FUNCTION IF EXISTS webhook_compliance_check_failed();
+DROP FUNCTION IF EXISTS webhook_license_status_changed();
+DROP FUNCTION IF EXISTS enqueue_webhook(UUID, TEXT, JSONB);
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,163 @@
+-- Outbound webhooks (see package webhook). A business subscribes a URL to
+-- event types; the secret signs each payload with HMAC-SHA256, so it is
+-- kept in the clear and only shown when it is created or rotated.
+CREATE TABLE webhook_subscriptions (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id   UUID NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    url           TEXT NOT NULL,
+    secret        TEXT NOT NULL,
+    event_types   TEXT[] NOT NULL
+                  CHECK (cardinality(event_types) > 0 AND event_types <@ ARRAY[
+                      'license.status_changed', 'compliance_check.failed', 'notification.created']),
+    description   TEXT,
+    active        BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX webhook_subscriptions_business_id_idx ON webhook_subscriptions (business_id);
+
+-- One delivery per event and subscription, durable until it SUCCEEDED or
+-- FAILED for good. A delivery is RETRYING after a failed attempt until
+-- next_attempt_at. payload is the exact JSON body that is signed and sent.
+CREATE TABLE webhook_deliveries (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id        UUID NOT NULL,
+    event_type      TEXT NOT NULL,
+    payload         JSONB NOT NULL,
+    status          TEXT NOT NULL DEFAULT 'PENDING'
+                    CHECK (status IN ('PENDING', 'RETRYING', 'SUCCEEDED', 'FAILED')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    response_status INTEGER,
+    last_error      TEXT,
+    delivered_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at)
+    WHERE status IN ('PENDING', 'RETRYING');
+CREATE INDEX webhook_deliveries_subscription_id_created_at_idx
+    ON webhook_deliveries (subscription_id, created_at DESC);
+
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY webhook_subscriptions_tenant ON webhook_subscriptions
+    USING (app_can_access_business(business_id))
+    WITH CHECK (app_can_access_business(business_id));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY webhook_deliveries_tenant ON webhook_deliveries
+    USING (EXISTS (SELECT 1 FROM webhook_subscriptions s WHERE s.id = subscription_id))
+    WITH CHECK (EXISTS (SELECT 1 FROM webhook_subscriptions s WHERE s.id = subscription_id));
+
+-- enqueue_webhook queues an event for the active subscriptions of a
+-- business to its type, in the transaction of the change that caused it.
+-- The payload is {"id", "type", "createdAt", "businessId", "data"}.
+CREATE FUNCTION enqueue_webhook(target UUID, event_type TEXT, data JSONB) RETURNS VOID
+LANGUAGE plpgsql AS $$
+DECLARE
+    event_id UUID := gen_random_uuid();
+BEGIN
+    IF target IS NULL THEN
+        RETURN;
+    END IF;
+    INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+    SELECT s.id, event_id, event_type, jsonb_build_object(
+        'id', event_id,
+        'type', event_type,
+        'createdAt', NOW(),
+        'businessId', target,
+        'data', data)
+    FROM webhook_subscriptions s
+    WHERE s.business_id = target AND s.active AND event_type = ANY (s.event_types);
+END
+$$;
+
+CREATE FUNCTION webhook_license_status_changed() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM enqueue_webhook(NEW.business_id, 'license.status_changed', jsonb_build_object(
+        'licenseId', NEW.id,
+        'licenseNumber', NEW.license_number,
+        'previousStatus', OLD.status,
+        'status', NEW.status));
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER licenses_webhook_status_changed
+AFTER UPDATE OF status ON licenses
+FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+EXECUTE FUNCTION webhook_license_status_changed();
+
+-- A compliance check fails when it becomes NON_COMPLIANT
+CREATE FUNCTION webhook_compliance_check_failed() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.status = NEW.status THEN
+        RETURN NULL;
+    END IF;
+    PERFORM enqueue_webhook(
+        (SELECT business_id FROM licenses WHERE id = NEW.license_id),
+        'compliance_check.failed',
+        jsonb_build_object(
+            'complianceCheckId', NEW.id,
+            'licenseId', NEW.license_id,
+            'checkType', NEW.check_type,
+            'status', NEW.status,
+            'notes', NEW.notes));
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER compliance_checks_webhook_failed
+AFTER INSERT OR UPDATE OF status ON compliance_checks
+FOR EACH ROW WHEN (NEW.status = 'NON_COMPLIANT')
+EXECUTE FUNCTION webhook_compliance_check_failed();
+
+-- Notifications belong to users; they reach the webhooks of the business
+-- owning their related entity, and no others.
+CREATE FUNCTION webhook_notification_created() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    entity_id UUID;
+    target    UUID;
+BEGIN
+    IF NEW.related_entity_id !~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$' THEN
+        RETURN NULL;
+    END IF;
+    entity_id := NEW.related_entity_id::uuid;
+    target := CASE NEW.related_entity_type
+        WHEN 'License' THEN (SELECT business_id FROM licenses WHERE id = entity_id)
+        WHEN 'ComplianceCheck' THEN (
+            SELECT l.business_id FROM compliance_checks c
+            JOIN licenses l ON l.id = c.license_id WHERE c.id = entity_id)
+        WHEN 'RenewalRequirement' THEN (
+            SELECT l.business_id FROM renewal_requirements rr
+            JOIN licenses l ON l.id = rr.license_id WHERE rr.id = entity_id)
+        WHEN 'Document' THEN (
+            SELECT l.business_id FROM documents d
+            LEFT JOIN renewal_requirements rr ON rr.id = d.renewal_requirement_id
+            JOIN licenses l ON l.id = COALESCE(d.license_id, rr.license_id)
+            WHERE d.id = entity_id)
+    END;
+    PERFORM enqueue_webhook(target, 'notification.created', jsonb_build_object(
+        'notificationId', NEW.id,
+        'userId', NEW.user_id,
+        'type', NEW.type,
+        'title', NEW.title,
+        'message', NEW.message,
+        'relatedEntityId', NEW.related_entity_id,
+        'relatedEntityType', NEW.related_entity_type));
+    RETURN NULL;
+END
+$$;
+
+CREATE TRIGGER notifications_webhook_created
+AFTER INSERT ON notifications
+FOR EACH ROW WHEN (NEW.related_entity_id IS NOT NULL)
+EXECUTE FUNCTION webhook_notification_created();
@@ -0,0 +1,24 @@
+DROP TRIGGER IF EXISTS notification_preferences_audit ON notification_preferences;
+DROP TRIGGER IF EXISTS webhook_subscriptions_audit ON webhook_subscriptions;
+DROP TRIGGER IF EXISTS calendar_feeds_audit ON calendar_feeds;
+DROP TRIGGER IF EXISTS documents_audit ON documents;
+DROP TRIGGER IF EXISTS renewal_template_items_audit ON renewal_template_items;
+DROP TRIGGER IF EXISTS renewal_templates_audit ON renewal_templates;
+DROP TRIGGER IF EXISTS renewal_requirements_audit ON renewal_requirements;
+DROP TRIGGER IF EXISTS renewals_audit ON renewals;
+DROP TRIGGER IF EXISTS compliance_schedules_audit ON compliance_schedules;
+DROP TRIGGER IF EXISTS compliance_checks_audit ON compliance_checks;
+DROP TRIGGER IF EXISTS licenses_audit ON licenses;
+DROP TRIGGER IF EXISTS regulations_audit ON regulations;
+DROP TRIGGER IF EXISTS jurisdictions_audit ON jurisdictions;
+DROP TRIGGER IF EXISTS locations_audit ON locations;
+DROP TRIGGER IF EXISTS business_members_audit ON business_members;
+DROP TRIGGER IF EXISTS businesses_audit ON businesses;
+DROP TRIGGER IF EXISTS users_audit ON users;
+DROP FUNCTION IF EXISTS audit_seal(INT);
+DROP FUNCTION IF EXISTS audit_row();
+DROP FUNCTION IF EXISTS audit_event_digest_input(audit_events);
+DROP FUNCTION IF EXISTS audit_netstring(TEXT);
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_immutable();
+DROP TABLE IF EXISTS audit_chain;
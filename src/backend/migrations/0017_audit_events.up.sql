@@ -0,0 +1,245 @@
+-- Append-only audit log of every change to the records mutations write
+-- (see package audit). Row triggers record who changed which entity, how,
+-- and the changed columns before and after, together with the request the
+-- backend passed in the transaction-local settings app.request_id,
+-- app.client_ip, app.actor_email and app.mutation. Changes made outside a
+-- request, by background jobs and the CLI, have no actor.
+--
+-- Each event's hash is the SHA-256 of the previous event's hash and the
+-- event's own fields (see audit_event_digest_input), so editing, deleting
+-- or reordering events breaks the chain from there on. Audited writes only
+-- insert their events; audit_seal chains them afterwards, in the background
+-- (see audit.Sealer), so that writers never wait on each other for the
+-- chain. Until then an event has no prev_hash, hash or chain_seq, its
+-- position in the chain. audit_chain holds the head of the chain.
+CREATE TABLE audit_events (
+    seq          BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    id           UUID NOT NULL DEFAULT gen_random_uuid() UNIQUE,
+    occurred_at  TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+    actor_id     UUID,
+    actor_email  TEXT,
+    entity_type  TEXT NOT NULL,
+    entity_id    TEXT NOT NULL,
+    business_id  UUID,
+    operation    TEXT NOT NULL CHECK (operation IN ('CREATE', 'UPDATE', 'DELETE')),
+    mutation     TEXT,
+    before       JSONB,
+    after        JSONB,
+    request_id   TEXT,
+    ip           TEXT,
+    prev_hash    TEXT,
+    hash         TEXT UNIQUE,
+    chain_seq    BIGINT UNIQUE
+);
+CREATE INDEX audit_events_entity_idx ON audit_events (entity_type, entity_id, seq);
+CREATE INDEX audit_events_unsealed_idx ON audit_events (seq) WHERE hash IS NULL;
+
+CREATE TABLE audit_chain (
+    id        BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_hash TEXT NOT NULL,
+    last_seq  BIGINT NOT NULL
+);
+INSERT INTO audit_chain (last_hash, last_seq) VALUES (repeat('0', 64), 0);
+
+-- audit_netstring encodes one field of the hashed text as "<octet
+-- length>:<text>," and a null as "~,", so that text cannot move between
+-- neighbouring fields without changing the hash
+CREATE FUNCTION audit_netstring(s TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT CASE WHEN s IS NULL THEN '~,' ELSE octet_length(s) || ':' || s || ',' END
+$$;
+
+-- The text an event's hash covers. audit.Verify rebuilds it from the same
+-- expressions.
+CREATE FUNCTION audit_event_digest_input(e audit_events) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT audit_netstring(e.prev_hash) ||
+        audit_netstring(e.id::text) ||
+        audit_netstring(to_char(e.occurred_at AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')) ||
+        audit_netstring(e.actor_id::text) ||
+        audit_netstring(e.actor_email) ||
+        audit_netstring(e.entity_type) ||
+        audit_netstring(e.entity_id) ||
+        audit_netstring(e.business_id::text) ||
+        audit_netstring(e.operation) ||
+        audit_netstring(e.mutation) ||
+        audit_netstring(e.before::text) ||
+        audit_netstring(e.after::text) ||
+        audit_netstring(e.request_id) ||
+        audit_netstring(e.ip)
+$$;
+
+-- Audit events are never changed or removed, except that audit_seal fills
+-- in the chain fields of an unsealed event, once
+CREATE FUNCTION audit_events_immutable() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    chain_fields TEXT[] := ARRAY['prev_hash', 'hash', 'chain_seq'];
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.hash IS NULL AND NEW.hash IS NOT NULL
+           AND to_jsonb(NEW) - chain_fields = to_jsonb(OLD) - chain_fields THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    RAISE EXCEPTION 'audit_events is append-only';
+END
+$$;
+
+CREATE TRIGGER audit_events_no_update
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW EXECUTE FUNCTION audit_events_immutable();
+
+CREATE TRIGGER audit_events_no_truncate
+BEFORE TRUNCATE ON audit_events
+FOR EACH STATEMENT EXECUTE FUNCTION audit_events_immutable();
+
+-- audit_row records a change of the row. Its trigger arguments are the
+-- entity type and the columns that identify the row (id by default).
+-- Bookkeeping columns (updated_at, a calendar feed's last_used_at) and
+-- generated ones are left out of the diff, secrets are replaced by a
+-- fingerprint, and updates that change nothing else are not recorded.
+-- The event is inserted unsealed.
+CREATE FUNCTION audit_row() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    ignored    TEXT[] := ARRAY['updated_at', 'last_used_at', 'search_vector'];
+    old_row    JSONB;
+    new_row    JSONB;
+    row_data   JSONB;
+    before_row JSONB;
+    after_row  JSONB;
+    col        TEXT;
+    entity     TEXT := '';
+    target     UUID;
+    entry      audit_events;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD) - ignored;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW) - ignored;
+    END IF;
+    FOREACH col IN ARRAY ARRAY['secret', 'token_hash'] LOOP
+        IF old_row ? col THEN
+            old_row := jsonb_set(old_row, ARRAY[col], to_jsonb('redacted:' || left(md5(old_row ->> col), 8)));
+        END IF;
+        IF new_row ? col THEN
+            new_row := jsonb_set(new_row, ARRAY[col], to_jsonb('redacted:' || left(md5(new_row ->> col), 8)));
+        END IF;
+    END LOOP;
+
+    IF TG_OP = 'UPDATE' THEN
+        SELECT jsonb_object_agg(o.key, o.value), jsonb_object_agg(o.key, new_row -> o.key)
+        INTO before_row, after_row
+        FROM jsonb_each(old_row) o
+        WHERE o.value IS DISTINCT FROM new_row -> o.key;
+        IF before_row IS NULL THEN
+            RETURN NULL;
+        END IF;
+    ELSE
+        before_row := old_row;
+        after_row := new_row;
+    END IF;
+
+    row_data := COALESCE(new_row, old_row);
+    IF TG_NARGS > 1 THEN
+        FOR i IN 1 .. TG_NARGS - 1 LOOP
+            entity := entity || CASE WHEN i > 1 THEN ':' ELSE '' END || (row_data ->> TG_ARGV[i]);
+        END LOOP;
+    ELSE
+        entity := row_data ->> 'id';
+    END IF;
+    target := CASE
+        WHEN TG_TABLE_NAME = 'businesses' THEN (row_data ->> 'id')::uuid
+        WHEN row_data ? 'business_id' THEN (row_data ->> 'business_id')::uuid
+        WHEN row_data ->> 'license_id' IS NOT NULL THEN
+            (SELECT business_id FROM licenses WHERE id = (row_data ->> 'license_id')::uuid)
+        WHEN row_data ->> 'renewal_requirement_id' IS NOT NULL THEN (
+            SELECT l.business_id FROM renewal_requirements rr
+            JOIN licenses l ON l.id = rr.license_id
+            WHERE rr.id = (row_data ->> 'renewal_requirement_id')::uuid)
+    END;
+
+    entry.id := gen_random_uuid();
+    entry.occurred_at := clock_timestamp();
+    entry.actor_id := NULLIF(NULLIF(current_setting('app.user_id', true), ''), '00000000-0000-0000-0000-000000000000')::uuid;
+    entry.actor_email := NULLIF(current_setting('app.actor_email', true), '');
+    entry.entity_type := TG_ARGV[0];
+    entry.entity_id := entity;
+    entry.business_id := target;
+    entry.operation := CASE TG_OP WHEN 'INSERT' THEN 'CREATE' ELSE TG_OP END;
+    entry.mutation := NULLIF(current_setting('app.mutation', true), '');
+    entry.before := before_row;
+    entry.after := after_row;
+    entry.request_id := NULLIF(current_setting('app.request_id', true), '');
+    entry.ip := NULLIF(current_setting('app.client_ip', true), '');
+
+    INSERT INTO audit_events (id, occurred_at, actor_id, actor_email, entity_type, entity_id, business_id,
+                              operation, mutation, before, after, request_id, ip)
+    VALUES (entry.id, entry.occurred_at, entry.actor_id, entry.actor_email, entry.entity_type, entry.entity_id,
+            entry.business_id, entry.operation, entry.mutation, entry.before, entry.after, entry.request_id,
+            entry.ip);
+    RETURN NULL;
+END
+$$;
+
+-- audit_seal chains up to max_events unsealed events after the chain's
+-- head, in order of seq, and returns how many it sealed. Events whose
+-- transaction has not committed yet are sealed by a later call.
+CREATE FUNCTION audit_seal(max_events INT) RETURNS INT
+LANGUAGE plpgsql AS $$
+DECLARE
+    head   audit_chain;
+    e      audit_events;
+    sealed INT := 0;
+BEGIN
+    SELECT * INTO head FROM audit_chain FOR UPDATE;
+    FOR e IN SELECT * FROM audit_events WHERE hash IS NULL ORDER BY seq LIMIT max_events LOOP
+        e.prev_hash := head.last_hash;
+        e.hash := encode(sha256(convert_to(audit_event_digest_input(e), 'UTF8')), 'hex');
+        head.last_hash := e.hash;
+        head.last_seq := head.last_seq + 1;
+        UPDATE audit_events SET prev_hash = e.prev_hash, hash = e.hash, chain_seq = head.last_seq
+        WHERE seq = e.seq;
+        sealed := sealed + 1;
+    END LOOP;
+    UPDATE audit_chain SET last_hash = head.last_hash, last_seq = head.last_seq;
+    RETURN sealed;
+END
+$$;
+
+CREATE TRIGGER users_audit AFTER INSERT OR UPDATE OR DELETE ON users
+FOR EACH ROW EXECUTE FUNCTION audit_row('User');
+CREATE TRIGGER businesses_audit AFTER INSERT OR UPDATE OR DELETE ON businesses
+FOR EACH ROW EXECUTE FUNCTION audit_row('Business');
+CREATE TRIGGER business_members_audit AFTER INSERT OR UPDATE OR DELETE ON business_members
+FOR EACH ROW EXECUTE FUNCTION audit_row('BusinessMember', 'business_id', 'user_id');
+CREATE TRIGGER locations_audit AFTER INSERT OR UPDATE OR DELETE ON locations
+FOR EACH ROW EXECUTE FUNCTION audit_row('Location');
+CREATE TRIGGER jurisdictions_audit AFTER INSERT OR UPDATE OR DELETE ON jurisdictions
+FOR EACH ROW EXECUTE FUNCTION audit_row('Jurisdiction');
+CREATE TRIGGER regulations_audit AFTER INSERT OR UPDATE OR DELETE ON regulations
+FOR EACH ROW EXECUTE FUNCTION audit_row('Regulation');
+CREATE TRIGGER licenses_audit AFTER INSERT OR UPDATE OR DELETE ON licenses
+FOR EACH ROW EXECUTE FUNCTION audit_row('License');
+CREATE TRIGGER compliance_checks_audit AFTER INSERT OR UPDATE OR DELETE ON compliance_checks
+FOR EACH ROW EXECUTE FUNCTION audit_row('ComplianceCheck');
+CREATE TRIGGER compliance_schedules_audit AFTER INSERT OR UPDATE OR DELETE ON compliance_schedules
+FOR EACH ROW EXECUTE FUNCTION audit_row('ComplianceSchedule');
+CREATE TRIGGER renewals_audit AFTER INSERT OR UPDATE OR DELETE ON renewals
+FOR EACH ROW EXECUTE FUNCTION audit_row('Renewal');
+CREATE TRIGGER renewal_requirements_audit AFTER INSERT OR UPDATE OR DELETE ON renewal_requirements
+FOR EACH ROW EXECUTE FUNCTION audit_row('RenewalRequirement');
+CREATE TRIGGER renewal_templates_audit AFTER INSERT OR UPDATE OR DELETE ON renewal_templates
+FOR EACH ROW EXECUTE FUNCTION audit_row('RenewalTemplate');
+CREATE TRIGGER renewal_template_items_audit AFTER INSERT OR UPDATE OR DELETE ON renewal_template_items
+FOR EACH ROW EXECUTE FUNCTION audit_row('RenewalTemplateItem');
+CREATE TRIGGER documents_audit AFTER INSERT OR UPDATE OR DELETE ON documents
+FOR EACH ROW EXECUTE FUNCTION audit_row('Document');
+CREATE TRIGGER calendar_feeds_audit AFTER INSERT OR UPDATE OR DELETE ON calendar_feeds
+FOR EACH ROW EXECUTE FUNCTION audit_row('CalendarFeed');
+CREATE TRIGGER webhook_subscriptions_audit AFTER INSERT OR UPDATE OR DELETE ON webhook_subscriptions
+FOR EACH ROW EXECUTE FUNCTION audit_row('WebhookSubscription');
+CREATE TRIGGER notification_preferences_audit AFTER INSERT OR UPDATE OR DELETE ON notification_preferences
+FOR EACH ROW EXECUTE FUNCTION audit_row('NotificationPreferences', 'user_id');
@@ -0,0 +1,21 @@
+DROP TABLE IF EXISTS report_jobs;
+
+-- Documents of a business alone have nothing left to scope them by
+DELETE FROM documents WHERE license_id IS NULL AND renewal_requirement_id IS NULL;
+
+DROP POLICY IF EXISTS documents_tenant ON documents;
+CREATE POLICY documents_tenant ON documents
+    USING (
+        EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id)
+        OR EXISTS (SELECT 1 FROM renewal_requirements rr WHERE rr.id = renewal_requirement_id)
+    )
+    WITH CHECK (
+        EXISTS (SELECT 1 FROM licenses l WHERE l.id = license_id)
+        OR EXISTS (SELECT 1 FROM renewal_requirements rr WHERE rr.id = renewal_requirement_id)
+    );
+
+DROP TRIGGER IF EXISTS documents_default_business ON documents;
+DROP FUNCTION IF EXISTS documents_default_business();
+
+DROP INDEX IF EXISTS documents_business_id_idx;
+ALTER TABLE documents DROP COLUMN IF EXISTS business_id;
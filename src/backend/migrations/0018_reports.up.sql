@@ -0,0 +1,65 @@
+-- Compliance reports (see package reporting). A report is requested as a
+-- job and generated in the background; the rendered file is kept as a
+-- document of the business, which is not attached to a license or renewal
+-- requirement. Documents therefore name their business themselves.
+ALTER TABLE documents ADD COLUMN business_id UUID REFERENCES businesses (id) ON DELETE CASCADE;
+
+UPDATE documents d SET business_id = (
+    SELECT l.business_id FROM licenses l
+    LEFT JOIN renewal_requirements rr ON rr.id = d.renewal_requirement_id
+    WHERE l.id = COALESCE(d.license_id, rr.license_id));
+
+CREATE INDEX documents_business_id_idx ON documents (business_id);
+
+-- An attached document belongs to the business of its license
+CREATE FUNCTION documents_default_business() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF NEW.business_id IS NULL THEN
+        NEW.business_id := (
+            SELECT l.business_id FROM licenses l
+            LEFT JOIN renewal_requirements rr ON rr.id = NEW.renewal_requirement_id
+            WHERE l.id = COALESCE(NEW.license_id, rr.license_id));
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+CREATE TRIGGER documents_default_business
+BEFORE INSERT ON documents
+FOR EACH ROW EXECUTE FUNCTION documents_default_business();
+
+DROP POLICY documents_tenant ON documents;
+CREATE POLICY documents_tenant ON documents
+    USING (app_can_access_business(business_id))
+    WITH CHECK (app_can_access_business(business_id));
+
+-- A requested report, for the whole business or one of its locations. The
+-- generator claims PENDING jobs, and a job ends SUCCEEDED with its document
+-- or FAILED with the error.
+CREATE TABLE report_jobs (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id     UUID NOT NULL REFERENCES businesses (id) ON DELETE CASCADE,
+    location_id     UUID REFERENCES locations (id) ON DELETE CASCADE,
+    format          TEXT NOT NULL CHECK (format IN ('PDF', 'CSV', 'XLSX')),
+    status          TEXT NOT NULL DEFAULT 'PENDING'
+                    CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED')),
+    requested_by_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    document_id     UUID REFERENCES documents (id) ON DELETE SET NULL,
+    error           TEXT,
+    started_at      TIMESTAMPTZ,
+    completed_at    TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX report_jobs_business_id_created_at_idx ON report_jobs (business_id, created_at DESC);
+CREATE INDEX report_jobs_pending_idx ON report_jobs (created_at) WHERE status = 'PENDING';
+
+ALTER TABLE report_jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE report_jobs FORCE ROW LEVEL SECURITY;
+CREATE POLICY report_jobs_tenant ON report_jobs
+    USING (app_can_access_business(business_id))
+    WITH CHECK (app_can_access_business(business_id));
+
+CREATE TRIGGER report_jobs_audit AFTER INSERT OR UPDATE OR DELETE ON report_jobs
+FOR EACH ROW EXECUTE FUNCTION audit_row('ReportJob');
@@ -0,0 +1,85 @@
+package migrations_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"budsafe/backend/migrations"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_OrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.up.sql":   {Data: []byte("CREATE INDEX a ON b (c);")},
+		"0002_add_index.down.sql": {Data: []byte("DROP INDEX a;")},
+		"0001_init.up.sql":        {Data: []byte("CREATE TABLE b (c INT);")},
+		"0001_init.down.sql":      {Data: []byte("DROP TABLE b;")},
+	}
+
+	loaded, err := migrations.Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+
+	assert.Equal(t, int64(1), loaded[0].Version)
+	assert.Equal(t, "init", loaded[0].Name)
+	assert.Equal(t, int64(2), loaded[1].Version)
+	assert.Equal(t, "DROP INDEX a;", loaded[1].Down)
+	assert.Len(t, loaded[0].Checksum, 64)
+	assert.NotEqual(t, loaded[0].Checksum, loaded[1].Checksum)
+}
+
+func TestLoad_RejectsIncompleteOrConflictingFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"missing down": {
+			"0001_init.up.sql": {Data: []byte("SELECT 1;")},
+		},
+		"duplicate version": {
+			"0001_init.up.sql":    {Data: []byte("SELECT 1;")},
+			"0001_init.down.sql":  {Data: []byte("SELECT 1;")},
+			"0001_other.up.sql":   {Data: []byte("SELECT 1;")},
+			"0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"bad file name": {
+			"init.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := migrations.Load(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	loaded, err := migrations.Load(os.DirFS("."))
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+	assert.Equal(t, int64(1), loaded[0].Version)
+}
+
+func TestCreate_UsesNextVersion(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), []byte("SELECT 1;"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_existing.down.sql"), []byte("SELECT 1;"), 0o644))
+
+	paths, err := migrations.Create(dir, "add_widgets")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "0008_add_widgets.up.sql"),
+		filepath.Join(dir, "0008_add_widgets.down.sql"),
+	}, paths)
+
+	_, err = migrations.Create(dir, "Bad Name")
+	assert.Error(t, err)
+}
+
+func TestErrChecksumMismatch_ShortChecksums(t *testing.T) {
+	err := &migrations.ErrChecksumMismatch{Version: 3, Name: "widgets", Applied: "", Embedded: "abcdef0123456789"}
+	assert.Equal(t, "migration 0003_widgets was modified after being applied (applied checksum , embedded checksum abcdef012345)", err.Error())
+}
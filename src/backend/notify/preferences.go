@@ -0,0 +1,257 @@
+// Package notify decides how and when a user hears about their
+// notifications.
+//
+// A user's Preferences pick the channels of each notification type, quiet
+// hours during which nothing is emailed, and whether emails go out as each
+// notification arrives or batched into a daily or weekly digest. Users
+// without saved preferences get every notification in the app and by email,
+// immediately.
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Channels, matching the NotificationChannel enum
+const (
+	InApp = "IN_APP"
+	Email = "EMAIL"
+)
+
+// Digest modes, matching the DigestMode enum
+const (
+	Immediate = "IMMEDIATE"
+	Daily     = "DAILY"
+	Weekly    = "WEEKLY"
+)
+
+// Preferences are how and when one user is notified
+type Preferences struct {
+	// Timezone is the IANA name of the zone quiet hours and digests are in
+	Timezone string
+	// QuietHoursStart and QuietHoursEnd are local "HH:MM" times, both set
+	// or both empty. Quiet hours wrap past midnight when the start is
+	// later than the end.
+	QuietHoursStart string
+	QuietHoursEnd   string
+	Digest          string
+	// DigestHour is the local hour digests are sent at
+	DigestHour int
+	// DigestWeekday is the day weekly digests are sent on
+	DigestWeekday time.Weekday
+	// Channels maps notification types to the channels they are delivered
+	// on; a type that is not listed is delivered on every channel
+	Channels map[string][]string
+}
+
+// Default returns the preferences of users who have not saved any
+func Default() *Preferences {
+	return &Preferences{
+		Timezone:      "UTC",
+		Digest:        Immediate,
+		DigestHour:    8,
+		DigestWeekday: time.Monday,
+		Channels:      map[string][]string{},
+	}
+}
+
+// FieldError is a problem with one of the preferences, named as in the
+// NotificationPreferences type
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string { return e.Field + ": " + e.Message }
+
+func invalid(field, format string, args ...any) *FieldError {
+	return &FieldError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// Check reports the first problem with the preferences as a FieldError
+func (p *Preferences) Check() error {
+	if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "" || p.Timezone == "Local" {
+		return invalid("timezone", "unknown time zone %q", p.Timezone)
+	}
+	if (p.QuietHoursStart == "") != (p.QuietHoursEnd == "") {
+		return invalid("quietHoursEnd", "quiet hours need both a start and an end")
+	}
+	if p.QuietHoursStart != "" {
+		start, err := clock(p.QuietHoursStart)
+		if err != nil {
+			return invalid("quietHoursStart", "%v", err)
+		}
+		end, err := clock(p.QuietHoursEnd)
+		if err != nil {
+			return invalid("quietHoursEnd", "%v", err)
+		}
+		if start == end {
+			return invalid("quietHoursEnd", "quiet hours must not start and end at the same time")
+		}
+	}
+	if !slices.Contains([]string{Immediate, Daily, Weekly}, p.Digest) {
+		return invalid("digest", "unknown digest mode %q", p.Digest)
+	}
+	if p.DigestHour < 0 || p.DigestHour > 23 {
+		return invalid("digestHour", "digest hour %d is not between 0 and 23", p.DigestHour)
+	}
+	if p.DigestWeekday < time.Sunday || p.DigestWeekday > time.Saturday {
+		return invalid("digestDay", "unknown weekday %d", p.DigestWeekday)
+	}
+	for typ, channels := range p.Channels {
+		for i, channel := range channels {
+			if channel != InApp && channel != Email {
+				return invalid("channels", "unknown channel %q for %s", channel, typ)
+			}
+			if slices.Contains(channels[:i], channel) {
+				return invalid("channels", "channel %s is listed twice for %s", channel, typ)
+			}
+		}
+	}
+	return nil
+}
+
+// Wants reports whether notifications of type typ go to channel
+func (p *Preferences) Wants(typ, channel string) bool {
+	channels, ok := p.Channels[typ]
+	return !ok || slices.Contains(channels, channel)
+}
+
+// QuietUntil reports whether t is in quiet hours and, if so, when they end
+func (p *Preferences) QuietUntil(t time.Time) (time.Time, bool) {
+	start, errStart := clock(p.QuietHoursStart)
+	end, errEnd := clock(p.QuietHoursEnd)
+	if errStart != nil || errEnd != nil || start == end {
+		return time.Time{}, false
+	}
+	local := t.In(p.location())
+	now := local.Hour()*60 + local.Minute()
+	endsOn := func(days int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+days, end/60, end%60, 0, 0, local.Location())
+	}
+	switch {
+	case start < end && now >= start && now < end:
+		return endsOn(0), true
+	case start > end && now >= start:
+		return endsOn(1), true
+	case start > end && now < end:
+		return endsOn(0), true
+	}
+	return time.Time{}, false
+}
+
+// NextDigest is the first digest time after t; t itself when the user gets
+// immediate emails
+func (p *Preferences) NextDigest(t time.Time) time.Time {
+	if p.Digest != Daily && p.Digest != Weekly {
+		return t
+	}
+	local := t.In(p.location())
+	for days := 0; ; days++ {
+		next := time.Date(local.Year(), local.Month(), local.Day()+days, p.DigestHour, 0, 0, 0, local.Location())
+		if !next.After(t) || (p.Digest == Weekly && next.Weekday() != p.DigestWeekday) {
+			continue
+		}
+		return next
+	}
+}
+
+func (p *Preferences) location() *time.Location {
+	if loc, err := time.LoadLocation(p.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// clock parses a "HH:MM" time into minutes after midnight
+func clock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// columns are the notification_preferences columns Load reads
+const columns = `user_id, timezone,
+	COALESCE(to_char(quiet_hours_start, 'HH24:MI'), '') AS quiet_hours_start,
+	COALESCE(to_char(quiet_hours_end, 'HH24:MI'), '') AS quiet_hours_end,
+	digest, digest_hour, digest_weekday, channels`
+
+type row struct {
+	UserID          string `db:"user_id"`
+	Timezone        string `db:"timezone"`
+	QuietHoursStart string `db:"quiet_hours_start"`
+	QuietHoursEnd   string `db:"quiet_hours_end"`
+	Digest          string `db:"digest"`
+	DigestHour      int    `db:"digest_hour"`
+	DigestWeekday   int    `db:"digest_weekday"`
+	Channels        []byte `db:"channels"`
+}
+
+// Load returns the preferences of each of userIDs, the defaults for users
+// who have not saved any
+func Load(ctx context.Context, q sqlx.QueryerContext, userIDs ...string) (map[string]*Preferences, error) {
+	var rows []row
+	err := sqlx.SelectContext(ctx, q, &rows,
+		`SELECT `+columns+` FROM notification_preferences WHERE user_id = ANY($1)`, pq.Array(userIDs))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+
+	prefs := make(map[string]*Preferences, len(userIDs))
+	for _, id := range userIDs {
+		prefs[id] = Default()
+	}
+	for _, r := range rows {
+		p := &Preferences{
+			Timezone:        r.Timezone,
+			QuietHoursStart: r.QuietHoursStart,
+			QuietHoursEnd:   r.QuietHoursEnd,
+			Digest:          r.Digest,
+			DigestHour:      r.DigestHour,
+			DigestWeekday:   time.Weekday(r.DigestWeekday),
+			Channels:        map[string][]string{},
+		}
+		if err := json.Unmarshal(r.Channels, &p.Channels); err != nil {
+			return nil, fmt.Errorf("invalid notification channels of user %s: %w", r.UserID, err)
+		}
+		prefs[r.UserID] = p
+	}
+	return prefs, nil
+}
+
+// Save stores the preferences of userID
+func Save(ctx context.Context, q sqlx.ExecerContext, userID string, p *Preferences) error {
+	channels, err := json.Marshal(p.Channels)
+	if err != nil {
+		return err
+	}
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO notification_preferences
+		  (user_id, timezone, quiet_hours_start, quiet_hours_end, digest, digest_hour, digest_weekday, channels)
+		VALUES ($1, $2, NULLIF($3, '')::time, NULLIF($4, '')::time, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE SET
+		  timezone = EXCLUDED.timezone,
+		  quiet_hours_start = EXCLUDED.quiet_hours_start,
+		  quiet_hours_end = EXCLUDED.quiet_hours_end,
+		  digest = EXCLUDED.digest,
+		  digest_hour = EXCLUDED.digest_hour,
+		  digest_weekday = EXCLUDED.digest_weekday,
+		  channels = EXCLUDED.channels,
+		  updated_at = NOW()
+	`, userID, p.Timezone, p.QuietHoursStart, p.QuietHoursEnd, p.Digest, p.DigestHour, int(p.DigestWeekday), channels)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
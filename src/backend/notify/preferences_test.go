@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, Default().Check())
+
+	for name, change := range map[string]func(*Preferences){
+		"timezone":      func(p *Preferences) { p.Timezone = "Mars/Olympus" },
+		"half quiet":    func(p *Preferences) { p.QuietHoursStart = "22:00" },
+		"bad time":      func(p *Preferences) { p.QuietHoursStart, p.QuietHoursEnd = "25:00", "07:00" },
+		"empty quiet":   func(p *Preferences) { p.QuietHoursStart, p.QuietHoursEnd = "07:00", "07:00" },
+		"digest":        func(p *Preferences) { p.Digest = "HOURLY" },
+		"digest hour":   func(p *Preferences) { p.DigestHour = 24 },
+		"channel":       func(p *Preferences) { p.Channels["RENEWAL_DUE"] = []string{"SMS"} },
+		"twice listed":  func(p *Preferences) { p.Channels["RENEWAL_DUE"] = []string{Email, Email} },
+		"weekday range": func(p *Preferences) { p.DigestWeekday = 7 },
+	} {
+		p := Default()
+		change(p)
+		var fieldErr *FieldError
+		assert.ErrorAs(t, p.Check(), &fieldErr, name)
+	}
+}
+
+func TestWants(t *testing.T) {
+	p := Default()
+	p.Channels["LICENSE_EXPIRING"] = []string{InApp}
+	p.Channels["REGULATION_UPDATE"] = []string{}
+
+	assert.True(t, p.Wants("LICENSE_EXPIRING", InApp))
+	assert.False(t, p.Wants("LICENSE_EXPIRING", Email))
+	assert.False(t, p.Wants("REGULATION_UPDATE", InApp))
+	assert.True(t, p.Wants("RENEWAL_DUE", Email))
+}
+
+func TestQuietUntil(t *testing.T) {
+	p := Default()
+	p.Timezone = "America/Los_Angeles"
+	la, err := time.LoadLocation(p.Timezone)
+	require.NoError(t, err)
+
+	_, quiet := p.QuietUntil(time.Date(2026, 10, 18, 23, 0, 0, 0, la))
+	assert.False(t, quiet, "no quiet hours")
+
+	p.QuietHoursStart, p.QuietHoursEnd = "22:00", "07:00"
+	until, quiet := p.QuietUntil(time.Date(2026, 10, 18, 23, 0, 0, 0, la))
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 10, 19, 7, 0, 0, 0, la), until)
+
+	until, quiet = p.QuietUntil(time.Date(2026, 10, 19, 6, 59, 0, 0, la))
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 10, 19, 7, 0, 0, 0, la), until)
+
+	_, quiet = p.QuietUntil(time.Date(2026, 10, 19, 7, 0, 0, 0, la))
+	assert.False(t, quiet)
+
+	p.QuietHoursStart, p.QuietHoursEnd = "12:00", "13:30"
+	until, quiet = p.QuietUntil(time.Date(2026, 10, 19, 12, 15, 0, 0, la).UTC())
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2026, 10, 19, 13, 30, 0, 0, la), until)
+}
+
+func TestNextDigest(t *testing.T) {
+	p := Default()
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC) // a Sunday
+	assert.Equal(t, now, p.NextDigest(now), "immediate")
+
+	p.Digest = Daily
+	assert.Equal(t, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC), p.NextDigest(now))
+	p.DigestHour = 17
+	assert.Equal(t, time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC), p.NextDigest(now))
+
+	p.Digest = Weekly
+	p.DigestWeekday = time.Sunday
+	assert.Equal(t, time.Date(2026, 10, 18, 17, 0, 0, 0, time.UTC), p.NextDigest(now))
+	p.DigestHour = 8
+	assert.Equal(t, time.Date(2026, 10, 25, 8, 0, 0, 0, time.UTC), p.NextDigest(now))
+
+	// Across the end of daylight saving time the digest stays at 8:00
+	p.Timezone, p.Digest = "America/New_York", Daily
+	ny, err := time.LoadLocation(p.Timezone)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 2, 8, 0, 0, 0, ny),
+		p.NextDigest(time.Date(2026, 11, 1, 9, 0, 0, 0, ny)))
+}
@@ -0,0 +1,225 @@
+// Package numbering validates license numbers against the formats a
+// jurisdiction issues them in.
+//
+// A jurisdiction's formats are a JSON list stored next to its license
+// types, for example:
+//
+//	[
+//	  {"licenseTypes": ["RETAIL", "DELIVERY"], "prefix": "C10-",
+//	   "pattern": "^C10-\\d{7}-LIC$", "example": "C10-0000123-LIC"},
+//	  {"pattern": "^[A-Z]{3}\\d{6}$", "checkDigit": "LUHN", "example": "CCL000018"}
+//	]
+//
+// A format listing license types applies to licenses of those types, and a
+// format without license types to every other type. A license type no
+// format applies to accepts any number.
+package numbering
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Check digit algorithms. The check digit is the last character of the
+// number and is computed over the digits before it; other characters are
+// ignored.
+const (
+	// Luhn is the mod 10 algorithm of payment cards
+	Luhn = "LUHN"
+	// Mod11 weighs the digits 2, 3, 4, ... from the right; a remainder
+	// calling for 10 is written as X
+	Mod11 = "MOD11"
+)
+
+// Format is how the license numbers of some license types are formed. The
+// number must start with Prefix, match Pattern and end in a valid check
+// digit, each if given.
+type Format struct {
+	LicenseTypes []string `json:"licenseTypes,omitempty"`
+	Prefix       string   `json:"prefix,omitempty"`
+	Pattern      string   `json:"pattern,omitempty"`
+	CheckDigit   string   `json:"checkDigit,omitempty"`
+	// Example is a valid number shown to people entering one
+	Example     string `json:"example,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// Formats are the license number formats of a jurisdiction
+type Formats []*Format
+
+// Parse decodes and checks a jurisdiction's formats. Empty input has no
+// formats.
+func Parse(raw []byte) (Formats, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+	var formats Formats
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&formats); err != nil {
+		return nil, fmt.Errorf("invalid license number formats: %w", err)
+	}
+	if err := formats.Check(); err != nil {
+		return nil, err
+	}
+	return formats, nil
+}
+
+// Check validates the formats: each must constrain the number, its pattern
+// must compile, its example must be valid, and no license type may have two
+// formats.
+func (fs Formats) Check() error {
+	seen := map[string]bool{}
+	for i, f := range fs {
+		if f == nil {
+			return fmt.Errorf("format %d is empty", i+1)
+		}
+		if f.Prefix == "" && f.Pattern == "" && f.CheckDigit == "" {
+			return fmt.Errorf("format %d needs a prefix, pattern or check digit", i+1)
+		}
+		if f.Pattern != "" {
+			re, err := regexp.Compile(f.Pattern)
+			if err != nil {
+				return fmt.Errorf("format %d has an invalid pattern: %w", i+1, err)
+			}
+			f.re = re
+		}
+		switch f.CheckDigit {
+		case "", Luhn, Mod11:
+		default:
+			return fmt.Errorf("format %d has an unknown check digit %q", i+1, f.CheckDigit)
+		}
+
+		types := f.LicenseTypes
+		if len(types) == 0 {
+			types = []string{""}
+		}
+		for _, t := range types {
+			if seen[t] {
+				if t == "" {
+					return fmt.Errorf("format %d: only one format may apply to all license types", i+1)
+				}
+				return fmt.Errorf("format %d: license type %s already has a format", i+1, t)
+			}
+			seen[t] = true
+		}
+
+		if f.Example != "" {
+			if problems := f.Validate(f.Example); len(problems) > 0 {
+				return fmt.Errorf("format %d: example %q is invalid: %s", i+1, f.Example, problems[0])
+			}
+		}
+	}
+	return nil
+}
+
+// For returns the format that applies to licenseType, or nil if there is
+// none
+func (fs Formats) For(licenseType string) *Format {
+	var general *Format
+	for _, f := range fs {
+		if slices.Contains(f.LicenseTypes, licenseType) {
+			return f
+		}
+		if len(f.LicenseTypes) == 0 {
+			general = f
+		}
+	}
+	return general
+}
+
+// Validate checks number against the format for licenseType and returns
+// what is wrong with it, nothing if it is valid
+func (fs Formats) Validate(licenseType, number string) []string {
+	f := fs.For(licenseType)
+	if f == nil {
+		return nil
+	}
+	return f.Validate(number)
+}
+
+// Validate returns what is wrong with number, nothing if it is valid
+func (f *Format) Validate(number string) []string {
+	var problems []string
+	if f.Prefix != "" && !strings.HasPrefix(number, f.Prefix) {
+		problems = append(problems, fmt.Sprintf("license number must start with %q", f.Prefix))
+	}
+	if f.Pattern != "" {
+		if f.re == nil {
+			f.re = regexp.MustCompile(f.Pattern)
+		}
+		if !f.re.MatchString(number) {
+			problems = append(problems, "license number "+f.expected())
+		}
+	}
+	if f.CheckDigit != "" && !validCheckDigit(f.CheckDigit, number) {
+		problems = append(problems, fmt.Sprintf("license number has an invalid check digit (%s)", f.CheckDigit))
+	}
+	return problems
+}
+
+// expected describes the numbers the pattern accepts
+func (f *Format) expected() string {
+	switch {
+	case f.Description != "" && f.Example != "":
+		return fmt.Sprintf("must be %s, e.g. %s", f.Description, f.Example)
+	case f.Description != "":
+		return "must be " + f.Description
+	case f.Example != "":
+		return "does not match the expected format, e.g. " + f.Example
+	default:
+		return fmt.Sprintf("does not match the expected format %s", f.Pattern)
+	}
+}
+
+// validCheckDigit reports whether number ends in a valid check digit
+func validCheckDigit(algorithm, number string) bool {
+	if number == "" {
+		return false
+	}
+	check := number[len(number)-1]
+	var digits []int
+	for _, c := range number[:len(number)-1] {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) == 0 {
+		return false
+	}
+
+	switch algorithm {
+	case Luhn:
+		if check < '0' || check > '9' {
+			return false
+		}
+		sum := int(check - '0')
+		for i := range digits {
+			d := digits[len(digits)-1-i]
+			if i%2 == 0 {
+				if d *= 2; d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		return sum%10 == 0
+	case Mod11:
+		sum := 0
+		for i := range digits {
+			sum += digits[len(digits)-1-i] * (i + 2)
+		}
+		want := (11 - sum%11) % 11
+		if want == 10 {
+			return check == 'X' || check == 'x'
+		}
+		return check == byte('0'+want)
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package numbering
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const formats = `[
+  {"licenseTypes": ["RETAIL", "DELIVERY"], "prefix": "C10-",
+   "pattern": "^C10-\\d{7}-LIC$", "example": "C10-0000123-LIC"},
+  {"pattern": "^[A-Z]{3}\\d{6}$", "checkDigit": "LUHN", "example": "CCL000018"}
+]`
+
+func TestParse(t *testing.T) {
+	fs, err := Parse([]byte(formats))
+	require.NoError(t, err)
+	require.Len(t, fs, 2)
+
+	none, err := Parse(nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	for name, raw := range map[string]string{
+		"not a list":        `{"prefix": "C10-"}`,
+		"unknown field":     `[{"prefix": "C10-", "suffix": "-LIC"}]`,
+		"no constraint":     `[{"example": "C10-1"}]`,
+		"bad pattern":       `[{"pattern": "^C10-("}]`,
+		"bad check digit":   `[{"checkDigit": "MOD97"}]`,
+		"two general":       `[{"prefix": "A"}, {"prefix": "B"}]`,
+		"type twice":        `[{"licenseTypes": ["RETAIL"], "prefix": "A"}, {"licenseTypes": ["RETAIL"], "prefix": "B"}]`,
+		"example mismatch":  `[{"prefix": "C10-", "example": "C11-0000123"}]`,
+		"example bad digit": `[{"checkDigit": "LUHN", "example": "79927398710"}]`,
+	} {
+		_, err := Parse([]byte(raw))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	fs, err := Parse([]byte(formats))
+	require.NoError(t, err)
+
+	assert.Empty(t, fs.Validate("RETAIL", "C10-0012345-LIC"))
+	assert.Equal(t, []string{
+		`license number must start with "C10-"`,
+		"license number does not match the expected format, e.g. C10-0000123-LIC",
+	}, fs.Validate("DELIVERY", "C11-0012345-LIC"))
+
+	// Other license types fall back to the general format
+	assert.Empty(t, fs.Validate("CULTIVATION", "CCL000018"))
+	assert.Equal(t, []string{"license number has an invalid check digit (LUHN)"},
+		fs.Validate("CULTIVATION", "CCL000017"))
+
+	assert.Empty(t, Formats{}.Validate("RETAIL", "anything"))
+}
+
+func TestValidCheckDigit(t *testing.T) {
+	assert.True(t, validCheckDigit(Luhn, "79927398713"))
+	assert.True(t, validCheckDigit(Luhn, "7992-7398-713"))
+	assert.False(t, validCheckDigit(Luhn, "79927398710"))
+	assert.False(t, validCheckDigit(Luhn, "3"))
+
+	assert.True(t, validCheckDigit(Mod11, "0-306-40615-2"))
+	assert.True(t, validCheckDigit(Mod11, "0-8044-2957-X"))
+	assert.False(t, validCheckDigit(Mod11, "0-306-40615-3"))
+}
@@ -0,0 +1,152 @@
+// Package pubsub fans out Postgres NOTIFY events to in-process subscribers.
+//
+// Triggers installed by migrations/0003_change_events publish a small JSON
+// payload on one channel per topic whenever a watched row changes. Every
+// backend replica LISTENs on those channels, so a change written through any
+// replica reaches the subscribers of all of them.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Topics are the Postgres NOTIFY channels the triggers publish on
+const (
+	TopicNotificationAdded       = "notification_added"
+	TopicLicenseStatusChanged    = "license_status_changed"
+	TopicComplianceStatusChanged = "compliance_status_changed"
+)
+
+// Topics lists every topic the hub listens on
+var Topics = []string{
+	TopicNotificationAdded,
+	TopicLicenseStatusChanged,
+	TopicComplianceStatusChanged,
+}
+
+// subscriberBuffer is how many events a slow subscriber may fall behind
+// before further events for it are dropped
+const subscriberBuffer = 32
+
+// Event identifies a changed row. Subscribers load the row itself so that
+// payloads stay well under the NOTIFY size limit.
+type Event struct {
+	Topic      string `json:"-"`
+	ID         string `json:"id"`
+	UserID     string `json:"user_id,omitempty"`
+	BusinessID string `json:"business_id,omitempty"`
+}
+
+// Filter selects the events a subscriber receives
+type Filter func(Event) bool
+
+type subscriber struct {
+	ch     chan Event
+	filter Filter
+}
+
+// Hub distributes events to subscribers by topic
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[string]map[*subscriber]struct{}
+}
+
+// NewHub returns an empty hub. Call Listen to feed it from Postgres.
+func NewHub() *Hub {
+	return &Hub{subs: map[string]map[*subscriber]struct{}{}}
+}
+
+// Subscribe returns a channel receiving the events on topic that pass filter
+// (all of them when filter is nil). The channel is closed when ctx is done.
+func (h *Hub) Subscribe(ctx context.Context, topic string, filter Filter) <-chan Event {
+	sub := &subscriber{ch: make(chan Event, subscriberBuffer), filter: filter}
+
+	h.mu.Lock()
+	if h.subs[topic] == nil {
+		h.subs[topic] = map[*subscriber]struct{}{}
+	}
+	h.subs[topic][sub] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.subs[topic], sub)
+		h.mu.Unlock()
+		close(sub.ch)
+	}()
+
+	return sub.ch
+}
+
+// Publish delivers event to the matching subscribers of event.Topic without
+// blocking; a subscriber whose buffer is full misses the event.
+func (h *Hub) Publish(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subs[event.Topic] {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			log.Printf("pubsub: dropping %s event %s for a slow subscriber", event.Topic, event.ID)
+		}
+	}
+}
+
+// Dispatch decodes a NOTIFY payload received on topic and publishes it
+func (h *Hub) Dispatch(topic, payload string) error {
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return fmt.Errorf("invalid %s payload %q: %w", topic, payload, err)
+	}
+	event.Topic = topic
+	h.Publish(event)
+	return nil
+}
+
+// Listen LISTENs on every topic using a dedicated connection to dsn and
+// publishes the notifications it receives until ctx is done. The listener
+// reconnects on its own after connection loss.
+func (h *Hub) Listen(ctx context.Context, dsn string) error {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("pubsub: listener event %d: %v", ev, err)
+		}
+	})
+	defer listener.Close()
+
+	for _, topic := range Topics {
+		if err := listener.Listen(topic); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", topic, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case n := <-listener.Notify:
+			// A nil notification means the connection was re-established;
+			// anything sent while it was down is lost.
+			if n == nil {
+				continue
+			}
+			if err := h.Dispatch(n.Channel, n.Extra); err != nil {
+				log.Printf("pubsub: %v", err)
+			}
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
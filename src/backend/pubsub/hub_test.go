@@ -0,0 +1,65 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"budsafe/backend/pubsub"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, ch <-chan pubsub.Event) (pubsub.Event, bool) {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		return event, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return pubsub.Event{}, false
+	}
+}
+
+func TestHub_FiltersByTopicAndFilter(t *testing.T) {
+	hub := pubsub.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mine := hub.Subscribe(ctx, pubsub.TopicNotificationAdded, func(e pubsub.Event) bool { return e.UserID == "user-1" })
+	all := hub.Subscribe(ctx, pubsub.TopicNotificationAdded, nil)
+	licenses := hub.Subscribe(ctx, pubsub.TopicLicenseStatusChanged, nil)
+
+	require.NoError(t, hub.Dispatch(pubsub.TopicNotificationAdded, `{"id":"n-1","user_id":"user-2"}`))
+	require.NoError(t, hub.Dispatch(pubsub.TopicNotificationAdded, `{"id":"n-2","user_id":"user-1"}`))
+
+	event, _ := receive(t, mine)
+	assert.Equal(t, "n-2", event.ID)
+	assert.Equal(t, pubsub.TopicNotificationAdded, event.Topic)
+
+	event, _ = receive(t, all)
+	assert.Equal(t, "n-1", event.ID)
+	event, _ = receive(t, all)
+	assert.Equal(t, "n-2", event.ID)
+
+	assert.Empty(t, licenses)
+}
+
+func TestHub_ClosesOnCancel(t *testing.T) {
+	hub := pubsub.NewHub()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := hub.Subscribe(ctx, pubsub.TopicLicenseStatusChanged, nil)
+	cancel()
+
+	_, ok := receive(t, ch)
+	assert.False(t, ok)
+
+	// Publishing after the subscriber left must not panic
+	hub.Publish(pubsub.Event{Topic: pubsub.TopicLicenseStatusChanged, ID: "l-1"})
+}
+
+func TestHub_RejectsInvalidPayload(t *testing.T) {
+	assert.Error(t, pubsub.NewHub().Dispatch(pubsub.TopicNotificationAdded, "not json"))
+}
@@ -0,0 +1,125 @@
+package recurrence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Generator materializes the next compliance check of each active schedule.
+// A schedule has at most one open (PENDING_REVIEW) check at a time. Its next
+// check is created as soon as the previous one is completed, and its first
+// once the first occurrence is within the schedule's lead days. Schedules
+// of revoked or expired licenses are left alone.
+type Generator struct {
+	DB *sqlx.DB
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// Run materializes checks for every schedule. It is a scheduler job.
+func (g *Generator) Run(ctx context.Context) error {
+	tx, err := g.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := g.Materialize(ctx, tx, nil)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit compliance schedules: %w", err)
+	}
+	log.Printf("recurrence: created %d compliance check(s)", len(ids))
+	return nil
+}
+
+// scheduleRow is a schedule without an open check
+type scheduleRow struct {
+	ID           string     `db:"id"`
+	LicenseID    string     `db:"license_id"`
+	Title        string     `db:"title"`
+	Notes        *string    `db:"notes"`
+	RRule        string     `db:"rrule"`
+	StartsAt     time.Time  `db:"starts_at"`
+	LeadDays     int        `db:"lead_days"`
+	AssignedToID *string    `db:"assigned_to_id"`
+	Last         *time.Time `db:"last_occurrence"`
+}
+
+// Materialize creates the checks that are due for scheduleID, or for all
+// schedules if it is nil, and returns their ids. Schedules with an invalid
+// rule are logged and skipped.
+func (g *Generator) Materialize(ctx context.Context, tx *sqlx.Tx, scheduleID *string) ([]string, error) {
+	now := time.Now
+	if g.Now != nil {
+		now = g.Now
+	}
+
+	var schedules []scheduleRow
+	err := tx.SelectContext(ctx, &schedules, `
+		SELECT s.id, s.license_id, s.title, s.notes, s.rrule, s.starts_at, s.lead_days, s.assigned_to_id,
+		       last.occurrence_at AS last_occurrence
+		FROM compliance_schedules s
+		JOIN licenses l ON l.id = s.license_id
+		LEFT JOIN LATERAL (
+		    SELECT occurrence_at FROM compliance_checks c
+		    WHERE c.schedule_id = s.id
+		    ORDER BY occurrence_at DESC
+		    LIMIT 1
+		) last ON TRUE
+		WHERE s.active
+		  AND l.status NOT IN ('REVOKED', 'EXPIRED')
+		  AND NOT EXISTS (
+		    SELECT 1 FROM compliance_checks c
+		    WHERE c.schedule_id = s.id AND c.status = 'PENDING_REVIEW'
+		  )
+		  AND ($1::uuid IS NULL OR s.id = $1::uuid)
+		ORDER BY s.id
+		FOR UPDATE OF s
+	`, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load compliance schedules: %w", err)
+	}
+
+	var ids []string
+	for _, s := range schedules {
+		rule, err := Parse(s.RRule, s.StartsAt)
+		if err != nil {
+			log.Printf("recurrence: skipping schedule %s (%s): %v", s.ID, s.Title, err)
+			continue
+		}
+		due, ok := rule.NextDue(s.Last, now())
+		if !ok {
+			continue
+		}
+		// Until a first check exists, wait for the lead window
+		if s.Last == nil && due.AddDate(0, 0, -s.LeadDays).After(now()) {
+			continue
+		}
+
+		var id string
+		err = tx.GetContext(ctx, &id, `
+			INSERT INTO compliance_checks (license_id, schedule_id, occurrence_at, check_type, status,
+			                               next_check_date, notes, checked_by_id)
+			VALUES ($1, $2, $3, $4, 'PENDING_REVIEW', $3, $5, $6)
+			ON CONFLICT (schedule_id, occurrence_at) DO NOTHING
+			RETURNING id
+		`, s.LicenseID, s.ID, due, s.Title, s.Notes, s.AssignedToID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create scheduled compliance check: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,105 @@
+// Package recurrence repeats compliance checks on a schedule.
+//
+// A schedule's recurrence is an iCalendar RRULE (RFC 5545) such as
+// "FREQ=DAILY;INTERVAL=30" or "FREQ=MONTHLY;BYMONTHDAY=1", counted from the
+// schedule's start. Generator turns the occurrences into compliance checks
+// one at a time.
+package recurrence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Rule is a parsed recurrence anchored at its first occurrence
+type Rule struct {
+	rrule *rrule.RRule
+}
+
+// Parse validates an RRULE and anchors it at start. The rule may carry an
+// "RRULE:" prefix but not its own DTSTART, and must repeat at most daily:
+// compliance checks are not materialized by the hour.
+func Parse(rule string, start time.Time) (*Rule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" {
+		return nil, fmt.Errorf("rrule is empty")
+	}
+	opts, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule: %w", err)
+	}
+	if !opts.Dtstart.IsZero() {
+		return nil, fmt.Errorf("invalid rrule: DTSTART is given by the schedule's start")
+	}
+	switch opts.Freq {
+	case rrule.HOURLY, rrule.MINUTELY, rrule.SECONDLY:
+		return nil, fmt.Errorf("invalid rrule: FREQ=%s repeats more than daily", opts.Freq)
+	}
+	opts.Dtstart = start.UTC()
+	r, err := rrule.NewRRule(*opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rrule: %w", err)
+	}
+	return &Rule{rrule: r}, nil
+}
+
+// String returns the rule in its normalized form, without DTSTART
+func (r *Rule) String() string {
+	opts := r.rrule.OrigOptions
+	opts.Dtstart = time.Time{}
+	return opts.RRuleString()
+}
+
+// First returns the first occurrence, which is the start unless the rule
+// skips it. ok is false if the rule has none.
+func (r *Rule) First() (t time.Time, ok bool) {
+	t = r.rrule.After(r.rrule.GetDTStart(), true)
+	return t, !t.IsZero()
+}
+
+// After returns the first occurrence strictly after t. ok is false once the
+// rule has ended (COUNT or UNTIL).
+func (r *Rule) After(t time.Time) (next time.Time, ok bool) {
+	next = r.rrule.After(t, false)
+	return next, !next.IsZero()
+}
+
+// Between returns at most limit occurrences in [from, to]
+func (r *Rule) Between(from, to time.Time, limit int) []time.Time {
+	var out []time.Time
+	next := r.rrule.Iterator()
+	for len(out) < limit {
+		t, ok := next()
+		if !ok || t.After(to) {
+			break
+		}
+		if !t.Before(from) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// NextDue returns the occurrence to materialize after last, the occurrence
+// of the previous check (nil if there is none): the one following last, or
+// if several have come due since, the latest of them, so a schedule that
+// fell behind yields a single overdue check instead of a backlog.
+func (r *Rule) NextDue(last *time.Time, now time.Time) (time.Time, bool) {
+	var next time.Time
+	var ok bool
+	if last == nil {
+		next, ok = r.First()
+	} else {
+		next, ok = r.After(*last)
+	}
+	if !ok {
+		return time.Time{}, false
+	}
+	if latest := r.rrule.Before(now, true); latest.After(next) {
+		next = latest
+	}
+	return next, true
+}
@@ -0,0 +1,71 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func at(month time.Month, day int) time.Time {
+	return time.Date(2026, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=DAILY;INTERVAL=30", start)
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=DAILY;INTERVAL=30", rule.String())
+
+	for name, raw := range map[string]string{
+		"empty":      "",
+		"no freq":    "INTERVAL=30",
+		"unknown":    "FREQ=DAILY;EVERY=30",
+		"hourly":     "FREQ=HOURLY",
+		"dtstart":    "DTSTART:20260101T000000Z\nRRULE:FREQ=DAILY",
+		"bad number": "FREQ=DAILY;INTERVAL=x",
+	} {
+		_, err := Parse(raw, start)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYMONTHDAY=15", start)
+	require.NoError(t, err)
+
+	first, ok := rule.First()
+	require.True(t, ok)
+	assert.Equal(t, at(time.January, 15), first)
+
+	assert.Equal(t, []time.Time{at(time.March, 15), at(time.April, 15)},
+		rule.Between(at(time.March, 1), at(time.June, 1), 2))
+	assert.Empty(t, rule.Between(at(time.March, 16), at(time.April, 14), 5))
+}
+
+func TestNextDue(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;INTERVAL=30", start)
+	require.NoError(t, err)
+
+	due, ok := rule.NextDue(nil, at(time.January, 1))
+	require.True(t, ok)
+	assert.Equal(t, start, due)
+
+	last := start
+	due, ok = rule.NextDue(&last, at(time.January, 10))
+	require.True(t, ok)
+	assert.Equal(t, at(time.January, 31), due)
+
+	// A schedule that fell behind skips to the latest occurrence due
+	due, ok = rule.NextDue(&last, at(time.April, 5))
+	require.True(t, ok)
+	assert.Equal(t, at(time.April, 1), due)
+
+	finite, err := Parse("FREQ=WEEKLY;COUNT=2", start)
+	require.NoError(t, err)
+	last = at(time.January, 8)
+	_, ok = finite.NextDue(&last, at(time.January, 9))
+	assert.False(t, ok)
+}
@@ -0,0 +1,190 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"budsafe/backend/storage"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Job statuses, matching the ReportJobStatus enum
+const (
+	StatusPending   = "PENDING"
+	StatusRunning   = "RUNNING"
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+)
+
+// DefaultStaleAfter is how long a job may stay RUNNING before it is taken
+// to have been abandoned by a generator that stopped, and is run again
+const DefaultStaleAfter = 10 * time.Minute
+
+// Generator runs the requested report jobs. Each report is read in one
+// snapshot, rendered, stored and kept as a document of the business that
+// was uploaded by the requester. It relies on the scheduler to run on one
+// replica at a time.
+type Generator struct {
+	DB      *sqlx.DB
+	Storage storage.Storage
+	// Prefix is the storage key prefix of the report files
+	Prefix     string
+	StaleAfter time.Duration
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// job is a claimed report job
+type job struct {
+	ID            string  `db:"id"`
+	BusinessID    string  `db:"business_id"`
+	LocationID    *string `db:"location_id"`
+	Format        Format  `db:"format"`
+	RequestedByID string  `db:"requested_by_id"`
+}
+
+// Run generates the pending reports. It is a scheduler job; a report that
+// fails marks its job FAILED rather than failing the run.
+func (g *Generator) Run(ctx context.Context) error {
+	succeeded, failed := 0, 0
+	for {
+		j, err := g.claim(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := g.generate(ctx, j); err != nil {
+			log.Printf("reporting: report job %s failed: %v", j.ID, err)
+			if err := g.fail(ctx, j.ID, err); err != nil {
+				return err
+			}
+			failed++
+			continue
+		}
+		succeeded++
+	}
+	if succeeded+failed > 0 {
+		log.Printf("reporting: generated %d report(s), %d failed", succeeded, failed)
+	}
+	return nil
+}
+
+// claim marks the oldest pending job RUNNING and returns it
+func (g *Generator) claim(ctx context.Context) (job, error) {
+	staleAfter := g.StaleAfter
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	var j job
+	err := g.DB.GetContext(ctx, &j, `
+		UPDATE report_jobs
+		SET status = 'RUNNING', started_at = $1, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM report_jobs
+			WHERE status = 'PENDING' OR (status = 'RUNNING' AND started_at < $2)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, business_id, location_id, format, requested_by_id
+	`, g.now(), g.now().Add(-staleAfter))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return j, fmt.Errorf("failed to claim report job: %w", err)
+	}
+	return j, err
+}
+
+// generate renders the report of a claimed job and stores it as a
+// document, completing the job
+func (g *Generator) generate(ctx context.Context, j job) error {
+	report, err := g.load(ctx, j)
+	if err != nil {
+		return err
+	}
+	var file bytes.Buffer
+	if err := report.Render(&file, j.Format); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	obj, err := storage.Store(ctx, g.Storage, g.Prefix, j.Format.ContentType(), &file)
+	if err != nil {
+		return fmt.Errorf("failed to store report: %w", err)
+	}
+
+	tx, err := g.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		g.discard(obj.Key)
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var documentID string
+	err = tx.GetContext(ctx, &documentID, `
+		INSERT INTO documents (name, description, file_type, storage_key, file_size, checksum_sha256,
+		                       uploaded_by_id, business_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, report.Title(), fmt.Sprintf("Generated from report job %s", j.ID), obj.ContentType, obj.Key,
+		obj.Size, obj.SHA256, j.RequestedByID, j.BusinessID)
+	if err == nil {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE report_jobs
+			SET status = 'SUCCEEDED', document_id = $2, error = NULL, completed_at = $3, updated_at = NOW()
+			WHERE id = $1
+		`, j.ID, documentID, g.now())
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		g.discard(obj.Key)
+		return fmt.Errorf("failed to record report document: %w", err)
+	}
+	return nil
+}
+
+// load reads the report of a job in a read-only snapshot
+func (g *Generator) load(ctx context.Context, j job) (*Report, error) {
+	tx, err := g.DB.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	return Load(ctx, tx, j.BusinessID, j.LocationID, g.now())
+}
+
+// fail records why a job failed
+func (g *Generator) fail(ctx context.Context, id string, cause error) error {
+	_, err := g.DB.ExecContext(ctx, `
+		UPDATE report_jobs
+		SET status = 'FAILED', error = $2, completed_at = $3, updated_at = NOW()
+		WHERE id = $1
+	`, id, cause.Error(), g.now())
+	if err != nil {
+		return fmt.Errorf("failed to record report job failure: %w", err)
+	}
+	return nil
+}
+
+// discard removes a stored report whose document was not recorded.
+// Failures only leave an orphaned file, so they are logged.
+func (g *Generator) discard(key string) {
+	if err := g.Storage.Delete(context.Background(), key); err != nil {
+		log.Printf("reporting: failed to delete report file %s: %v", key, err)
+	}
+}
+
+func (g *Generator) now() time.Time {
+	if g.Now != nil {
+		return g.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,197 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// Render writes the report to w in the format
+func (r *Report) Render(w io.Writer, format Format) error {
+	switch format {
+	case FormatPDF:
+		return r.WritePDF(w)
+	case FormatCSV:
+		return r.WriteCSV(w)
+	case FormatXLSX:
+		return r.WriteXLSX(w)
+	}
+	return fmt.Errorf("unknown report format %q", format)
+}
+
+// WriteCSV writes the report as one CSV file: the summary, then each
+// section as its title, a header row and its rows, separated by empty lines
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{r.Title()})
+	for _, line := range r.summary() {
+		cw.Write(line[:])
+	}
+	for _, s := range r.sections() {
+		cw.Write(nil)
+		cw.Write([]string{s.title})
+		cw.Write(s.header)
+		cw.WriteAll(s.rows)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteXLSX writes the report as a workbook with a summary sheet and a
+// sheet per section
+func (r *Report) WriteXLSX(w io.Writer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	const summary = "Summary"
+	if err := f.SetSheetName("Sheet1", summary); err != nil {
+		return err
+	}
+	f.SetCellValue(summary, "A1", r.Title())
+	f.SetCellStyle(summary, "A1", "A1", bold)
+	for i, line := range r.summary() {
+		f.SetSheetRow(summary, fmt.Sprintf("A%d", i+3), &[]string{line[0], line[1]})
+		f.SetCellStyle(summary, fmt.Sprintf("A%d", i+3), fmt.Sprintf("A%d", i+3), bold)
+	}
+	f.SetColWidth(summary, "A", "A", 24)
+	f.SetColWidth(summary, "B", "B", 60)
+
+	for _, s := range r.sections() {
+		// Sheet names are limited to 31 characters
+		name := s.title[:min(len(s.title), 31)]
+		if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+		f.SetSheetRow(name, "A1", &s.header)
+		last, _ := excelize.CoordinatesToCellName(len(s.header), 1)
+		f.SetCellStyle(name, "A1", last, bold)
+		for i, row := range s.rows {
+			f.SetSheetRow(name, fmt.Sprintf("A%d", i+2), &row)
+		}
+		for i, width := range s.widths {
+			col, _ := excelize.ColumnNumberToName(i + 1)
+			f.SetColWidth(name, col, col, width*7)
+		}
+		f.SetPanes(name, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	}
+	return f.Write(w)
+}
+
+// PDF layout in millimetres and points
+const (
+	pdfMargin     = 12.0
+	pdfLineHeight = 4.5
+	pdfFontSize   = 8.0
+)
+
+// WritePDF writes the report as a landscape A4 document with a table per
+// section. Text outside the Windows-1252 character set is not rendered
+// faithfully by the core fonts.
+func (r *Report) WritePDF(w io.Writer) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetCreationDate(r.GeneratedAt)
+	pdf.SetTitle(r.Title(), true)
+	pdf.SetCreator("BudSafe", true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + 2)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.CellFormat(0, 4, tr(r.Title()), "", 0, "L", false, 0, "")
+		pdf.SetX(pdfMargin)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr(r.Title()), "", 1, "L", false, 0, "")
+	for _, line := range r.summary() {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(45, 6, tr(line[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(line[1]), "", 1, "L", false, 0, "")
+	}
+
+	_, pageHeight := pdf.GetPageSize()
+	for _, s := range r.sections() {
+		pdf.Ln(6)
+		// Keep a section's title with the start of its table
+		if pdf.GetY()+25 > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, tr(s.title), "", 1, "L", false, 0, "")
+		writePDFTable(pdf, tr, s)
+	}
+	return pdf.Output(w)
+}
+
+// writePDFTable draws a section as a table whose cells wrap, repeating the
+// header on each page the table continues on
+func writePDFTable(pdf *fpdf.Fpdf, tr func(string) string, s section) {
+	pageWidth, pageHeight := pdf.GetPageSize()
+	total := 0.0
+	for _, w := range s.widths {
+		total += w
+	}
+	widths := make([]float64, len(s.widths))
+	for i, w := range s.widths {
+		widths[i] = w / total * (pageWidth - 2*pdfMargin)
+	}
+
+	header := func() {
+		pdf.SetFillColor(225, 232, 225)
+		writePDFRow(pdf, tr, widths, s.header, "B", true)
+	}
+	header()
+	if len(s.rows) == 0 {
+		pdf.SetFont("Helvetica", "I", pdfFontSize)
+		pdf.CellFormat(0, 7, "None", "", 1, "L", false, 0, "")
+		return
+	}
+	for _, row := range s.rows {
+		pdf.SetFont("Helvetica", "", pdfFontSize)
+		if pdf.GetY()+pdfRowHeight(pdf, tr, widths, row) > pageHeight-pdfMargin {
+			pdf.AddPage()
+			header()
+		}
+		writePDFRow(pdf, tr, widths, row, "", false)
+	}
+}
+
+// pdfRowHeight is the height of a row with its cells wrapped to the widths
+func pdfRowHeight(pdf *fpdf.Fpdf, tr func(string) string, widths []float64, cells []string) float64 {
+	lines := 1
+	for i, cell := range cells {
+		lines = max(lines, len(pdf.SplitLines([]byte(tr(cell)), widths[i])))
+	}
+	return float64(lines)*pdfLineHeight + 1
+}
+
+// writePDFRow draws the cells side by side, wrapped as pdfRowHeight
+// measured them, and moves below them
+func writePDFRow(pdf *fpdf.Fpdf, tr func(string) string, widths []float64, cells []string, style string, fill bool) {
+	pdf.SetFont("Helvetica", style, pdfFontSize)
+	height := pdfRowHeight(pdf, tr, widths, cells)
+	border := "D"
+	if fill {
+		border = "FD"
+	}
+	x, y := pdf.GetXY()
+	for i, cell := range cells {
+		pdf.Rect(x, y, widths[i], height, border)
+		pdf.SetXY(x, y+0.5)
+		pdf.MultiCell(widths[i], pdfLineHeight, tr(cell), "", "L", false)
+		x += widths[i]
+	}
+	pdf.SetXY(pdfMargin, y+height)
+}
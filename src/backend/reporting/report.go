@@ -0,0 +1,292 @@
+// Package reporting renders point-in-time compliance reports for auditors.
+//
+// A report covers a business, or one of its locations: its licenses with
+// their status and expiry, the compliance checks still outstanding, the
+// progress of renewals in progress and an index of the current documents
+// attached to the licenses. Load reads it from the database, and the
+// report renders as PDF, CSV or XLSX. The Generator runs requested reports
+// in the background and keeps each file as a document of the business.
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Format is a file format of reports, matching the ReportFormat enum
+type Format string
+
+const (
+	FormatPDF  Format = "PDF"
+	FormatCSV  Format = "CSV"
+	FormatXLSX Format = "XLSX"
+)
+
+// ContentType is the MIME type of report files in the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatPDF:
+		return "application/pdf"
+	case FormatCSV:
+		return "text/csv"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "application/octet-stream"
+}
+
+// Extension is the file name extension of the format, with its dot
+func (f Format) Extension() string {
+	switch f {
+	case FormatPDF:
+		return ".pdf"
+	case FormatCSV:
+		return ".csv"
+	case FormatXLSX:
+		return ".xlsx"
+	}
+	return ""
+}
+
+// Report is the compliance state of a business or location at one time.
+// Dates are formatted as YYYY-MM-DD and empty when unknown.
+type Report struct {
+	Business string
+	// Location is the address of the location reported on, or empty for
+	// the whole business
+	Location    string
+	GeneratedAt time.Time
+	Licenses    []License
+	Checks      []Check
+	Renewals    []Renewal
+	Documents   []Document
+}
+
+// License is a license of the business with its standing
+type License struct {
+	Number         string `db:"license_number"`
+	Type           string `db:"type"`
+	Jurisdiction   string `db:"jurisdiction"`
+	Location       string `db:"location"`
+	Status         string `db:"status"`
+	IssuedDate     string `db:"issued_date"`
+	ExpirationDate string `db:"expiration_date"`
+	// DaysToExpiry is negative once the license has expired
+	DaysToExpiry int `db:"days_to_expiry"`
+}
+
+// Check is a compliance check that is not settled: non-compliant, in need
+// of attention or awaiting review
+type Check struct {
+	LicenseNumber string `db:"license_number"`
+	CheckType     string `db:"check_type"`
+	Status        string `db:"status"`
+	DueDate       string `db:"due_date"`
+	CheckedAt     string `db:"checked_at"`
+	Notes         string `db:"notes"`
+}
+
+// Renewal is a renewal in progress with its checklist
+type Renewal struct {
+	LicenseNumber          string `db:"license_number"`
+	StartedAt              string `db:"started_at"`
+	PreviousExpirationDate string `db:"previous_expiration_date"`
+	RequirementsDone       int    `db:"requirements_done"`
+	Requirements           int    `db:"requirements"`
+	// NextDueDate is the earliest deadline of the open requirements
+	NextDueDate    string `db:"next_due_date"`
+	PermitRecorded bool   `db:"permit_recorded"`
+}
+
+// Document is the current version of a document attached to a license or
+// to one of its renewal requirements
+type Document struct {
+	Name          string `db:"name"`
+	Category      string `db:"category"`
+	Version       int    `db:"version"`
+	LicenseNumber string `db:"license_number"`
+	ValidUntil    string `db:"valid_until"`
+	FileType      string `db:"file_type"`
+	Checksum      string `db:"checksum"`
+	UploadedAt    string `db:"uploaded_at"`
+}
+
+// Load reads the report of the business, or of one of its locations if
+// locationID is not nil, as of now. q should be a read-only transaction
+// with repeatable read isolation so that the sections agree.
+func Load(ctx context.Context, q sqlx.QueryerContext, businessID string, locationID *string, now time.Time) (*Report, error) {
+	report := &Report{GeneratedAt: now}
+	err := sqlx.GetContext(ctx, q, &report.Business, "SELECT name FROM businesses WHERE id = $1", businessID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load business: %w", err)
+	}
+	if locationID != nil {
+		err := sqlx.GetContext(ctx, q, &report.Location, `
+			SELECT address || ', ' || city || ', ' || state || ' ' || zip_code
+			FROM locations WHERE id = $1 AND business_id = $2
+		`, *locationID, businessID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load location: %w", err)
+		}
+	}
+	today := now.UTC().Format(time.DateOnly)
+
+	// Every section is limited to the licenses in scope
+	const inScope = `l.business_id = $1 AND ($2::uuid IS NULL OR l.location_id = $2)`
+
+	err = sqlx.SelectContext(ctx, q, &report.Licenses, `
+		SELECT l.license_number, l.type, j.name AS jurisdiction,
+		       COALESCE(loc.address || ', ' || loc.city || ', ' || loc.state, '') AS location,
+		       l.status, l.issued_date::text, l.expiration_date::text,
+		       l.expiration_date - $3::date AS days_to_expiry
+		FROM licenses l
+		JOIN jurisdictions j ON j.id = l.jurisdiction_id
+		LEFT JOIN locations loc ON loc.id = l.location_id
+		WHERE `+inScope+`
+		ORDER BY l.expiration_date, l.license_number
+	`, businessID, locationID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load licenses: %w", err)
+	}
+
+	err = sqlx.SelectContext(ctx, q, &report.Checks, `
+		SELECT l.license_number, c.check_type, c.status,
+		       to_char(c.next_check_date, 'YYYY-MM-DD') AS due_date,
+		       COALESCE(to_char(c.checked_at, 'YYYY-MM-DD'), '') AS checked_at,
+		       COALESCE(c.notes, '') AS notes
+		FROM compliance_checks c
+		JOIN licenses l ON l.id = c.license_id
+		WHERE `+inScope+`
+		  AND c.status IN ('NON_COMPLIANT', 'NEEDS_ATTENTION', 'PENDING_REVIEW')
+		ORDER BY array_position(ARRAY['NON_COMPLIANT', 'NEEDS_ATTENTION', 'PENDING_REVIEW'], c.status),
+		         c.next_check_date, l.license_number
+	`, businessID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load compliance checks: %w", err)
+	}
+
+	err = sqlx.SelectContext(ctx, q, &report.Renewals, `
+		SELECT l.license_number, to_char(rn.created_at, 'YYYY-MM-DD') AS started_at,
+		       rn.previous_expiration_date::text,
+		       COUNT(rr.id) FILTER (WHERE rr.completed_at IS NOT NULL) AS requirements_done,
+		       COUNT(rr.id) AS requirements,
+		       COALESCE(MIN(rr.due_date) FILTER (WHERE rr.completed_at IS NULL)::text, '') AS next_due_date,
+		       rn.permit_recorded_at IS NOT NULL AS permit_recorded
+		FROM renewals rn
+		JOIN licenses l ON l.id = rn.license_id
+		LEFT JOIN renewal_requirements rr ON rr.renewal_id = rn.id
+		WHERE `+inScope+` AND rn.status = 'IN_PROGRESS'
+		GROUP BY rn.id, l.license_number
+		ORDER BY rn.previous_expiration_date, l.license_number
+	`, businessID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load renewals: %w", err)
+	}
+
+	err = sqlx.SelectContext(ctx, q, &report.Documents, `
+		SELECT d.name, COALESCE(d.category, '') AS category, d.version, l.license_number,
+		       COALESCE(d.valid_until::text, '') AS valid_until, d.file_type,
+		       COALESCE(d.checksum_sha256, '') AS checksum,
+		       to_char(d.created_at, 'YYYY-MM-DD') AS uploaded_at
+		FROM documents d
+		LEFT JOIN renewal_requirements rr ON rr.id = d.renewal_requirement_id
+		JOIN licenses l ON l.id = COALESCE(d.license_id, rr.license_id)
+		WHERE `+inScope+` AND d.superseded_by_id IS NULL
+		ORDER BY l.license_number, d.name, d.created_at
+	`, businessID, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load documents: %w", err)
+	}
+	return report, nil
+}
+
+// Title names the report, e.g. "Compliance report: Green Leaf, 2026-10-18"
+func (r *Report) Title() string {
+	subject := r.Business
+	if r.Location != "" {
+		subject += " (" + r.Location + ")"
+	}
+	return fmt.Sprintf("Compliance report: %s, %s", subject, r.GeneratedAt.UTC().Format(time.DateOnly))
+}
+
+// section is a titled table of the report, the unit every format renders
+type section struct {
+	title  string
+	header []string
+	rows   [][]string
+	// widths are the relative column widths in the PDF
+	widths []float64
+}
+
+// sections lays the report out as tables
+func (r *Report) sections() []section {
+	licenses := section{
+		title:  "Licenses",
+		header: []string{"License number", "Type", "Jurisdiction", "Location", "Status", "Issued", "Expires", "Days to expiry"},
+		widths: []float64{3, 2.5, 2.5, 4, 2.5, 1.8, 1.8, 1.4},
+	}
+	for _, l := range r.Licenses {
+		licenses.rows = append(licenses.rows, []string{
+			l.Number, l.Type, l.Jurisdiction, l.Location, l.Status, l.IssuedDate, l.ExpirationDate, strconv.Itoa(l.DaysToExpiry),
+		})
+	}
+
+	checks := section{
+		title:  "Outstanding compliance checks",
+		header: []string{"License number", "Check", "Status", "Due", "Last checked", "Notes"},
+		widths: []float64{3, 4, 2.5, 1.8, 1.8, 6},
+	}
+	for _, c := range r.Checks {
+		checks.rows = append(checks.rows, []string{c.LicenseNumber, c.CheckType, c.Status, c.DueDate, c.CheckedAt, c.Notes})
+	}
+
+	renewals := section{
+		title:  "Renewals in progress",
+		header: []string{"License number", "Started", "Previous expiry", "Requirements done", "Next due", "Permit recorded"},
+		widths: []float64{3, 2, 2, 2, 2, 2},
+	}
+	for _, rn := range r.Renewals {
+		permit := "No"
+		if rn.PermitRecorded {
+			permit = "Yes"
+		}
+		renewals.rows = append(renewals.rows, []string{
+			rn.LicenseNumber, rn.StartedAt, rn.PreviousExpirationDate,
+			fmt.Sprintf("%d of %d", rn.RequirementsDone, rn.Requirements), rn.NextDueDate, permit,
+		})
+	}
+
+	documents := section{
+		title:  "Documents",
+		header: []string{"Name", "Category", "Version", "License number", "Valid until", "File type", "Uploaded", "SHA-256"},
+		widths: []float64{4, 2.5, 1.2, 3, 1.8, 2.5, 1.8, 4},
+	}
+	for _, d := range r.Documents {
+		documents.rows = append(documents.rows, []string{
+			d.Name, d.Category, strconv.Itoa(d.Version), d.LicenseNumber, d.ValidUntil, d.FileType, d.UploadedAt, d.Checksum,
+		})
+	}
+
+	return []section{licenses, checks, renewals, documents}
+}
+
+// summary is the heading block every format starts with
+func (r *Report) summary() [][2]string {
+	scope := "All locations"
+	if r.Location != "" {
+		scope = r.Location
+	}
+	return [][2]string{
+		{"Business", r.Business},
+		{"Location", scope},
+		{"Generated at", r.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")},
+		{"Licenses", strconv.Itoa(len(r.Licenses))},
+		{"Outstanding checks", strconv.Itoa(len(r.Checks))},
+		{"Renewals in progress", strconv.Itoa(len(r.Renewals))},
+		{"Documents", strconv.Itoa(len(r.Documents))},
+	}
+}
@@ -0,0 +1,128 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func testReport() *Report {
+	return &Report{
+		Business:    "Green Leaf Dispensary",
+		Location:    "12 Main St, Denver, CO 80202",
+		GeneratedAt: time.Date(2026, 10, 18, 15, 4, 0, 0, time.UTC),
+		Licenses: []License{{
+			Number: "402-00001", Type: "RETAIL", Jurisdiction: "Colorado", Location: "12 Main St, Denver, CO",
+			Status: "ACTIVE", IssuedDate: "2025-11-01", ExpirationDate: "2026-11-01", DaysToExpiry: 14,
+		}},
+		Checks: []Check{{
+			LicenseNumber: "402-00001", CheckType: "Security camera retention", Status: "NON_COMPLIANT",
+			DueDate: "2026-10-01", CheckedAt: "2026-09-30", Notes: "Footage kept for 30 days, \"40\" required",
+		}},
+		Renewals: []Renewal{{
+			LicenseNumber: "402-00001", StartedAt: "2026-09-01", PreviousExpirationDate: "2026-11-01",
+			RequirementsDone: 2, Requirements: 5, NextDueDate: "2026-10-20",
+		}},
+		Documents: []Document{{
+			Name: "Insurance certificate", Category: "INSURANCE", Version: 2, LicenseNumber: "402-00001",
+			ValidUntil: "2027-01-01", FileType: "application/pdf", UploadedAt: "2026-01-05",
+			Checksum: strings.Repeat("ab", 32),
+		}},
+	}
+}
+
+func TestTitle(t *testing.T) {
+	r := testReport()
+	assert.Equal(t, "Compliance report: Green Leaf Dispensary (12 Main St, Denver, CO 80202), 2026-10-18", r.Title())
+
+	r.Location = ""
+	assert.Equal(t, "Compliance report: Green Leaf Dispensary, 2026-10-18", r.Title())
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport().Render(&buf, FormatCSV))
+	assert.Contains(t, buf.String(), "\n\nLicenses\n")
+
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{testReport().Title()}, records[0])
+	assert.Contains(t, records, []string{"Location", "12 Main St, Denver, CO 80202"})
+	assert.Contains(t, records, []string{"Outstanding checks", "1"})
+	// Each section is its title, header and rows; empty lines are skipped
+	// by the reader
+	var titles []string
+	for i, record := range records {
+		if len(record) == 1 && i > 0 {
+			titles = append(titles, record[0])
+		}
+	}
+	assert.Equal(t, []string{"Licenses", "Outstanding compliance checks", "Renewals in progress", "Documents"}, titles)
+	assert.Contains(t, records, []string{
+		"402-00001", "Security camera retention", "NON_COMPLIANT", "2026-10-01", "2026-09-30",
+		"Footage kept for 30 days, \"40\" required",
+	})
+	assert.Contains(t, records, []string{"402-00001", "2026-09-01", "2026-11-01", "2 of 5", "2026-10-20", "No"})
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport().Render(&buf, FormatXLSX))
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Summary", "Licenses", "Outstanding compliance checks", "Renewals in progress", "Documents"}, f.GetSheetList())
+
+	title, err := f.GetCellValue("Summary", "A1")
+	require.NoError(t, err)
+	assert.Equal(t, testReport().Title(), title)
+
+	rows, err := f.GetRows("Licenses")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "License number", rows[0][0])
+	assert.Equal(t, []string{"402-00001", "RETAIL", "Colorado", "12 Main St, Denver, CO", "ACTIVE", "2025-11-01", "2026-11-01", "14"}, rows[1])
+
+	rows, err = f.GetRows("Documents")
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("ab", 32), rows[1][7])
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testReport().Render(&buf, FormatPDF))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	// Long tables continue on further pages
+	r := testReport()
+	for range 200 {
+		r.Documents = append(r.Documents, r.Documents[0])
+	}
+	var long bytes.Buffer
+	require.NoError(t, r.Render(&long, FormatPDF))
+	assert.Greater(t, bytes.Count(long.Bytes(), []byte("/Type /Page\n")), 3)
+
+	// Sections without rows still render
+	var empty bytes.Buffer
+	require.NoError(t, (&Report{Business: "Empty", GeneratedAt: r.GeneratedAt}).Render(&empty, FormatPDF))
+}
+
+func TestRenderRejectsUnknownFormat(t *testing.T) {
+	assert.Error(t, testReport().Render(&bytes.Buffer{}, Format("DOCX")))
+}
+
+func TestFormat(t *testing.T) {
+	assert.Equal(t, "application/pdf", FormatPDF.ContentType())
+	assert.Equal(t, ".xlsx", FormatXLSX.Extension())
+	assert.Equal(t, "text/csv", FormatCSV.ContentType())
+}
@@ -0,0 +1,176 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// recheckDays is when a clause without a known expiry is looked at again
+const recheckDays = 30
+
+// Facts are what a license's compliance is judged on
+type Facts struct {
+	LicenseType  string
+	BusinessType string
+	// State of the license's location, nil if it has none
+	State     *string
+	Documents []Document
+	// Checks are the compliance checks recorded by people; those the
+	// engine produces itself are left out
+	Checks []Check
+}
+
+// Document is the current version of a document attached to the license or
+// one of its renewal requirements
+type Document struct {
+	Name       string
+	Category   string
+	Issued     time.Time
+	ValidUntil *time.Time
+}
+
+// Check is a compliance check of the license
+type Check struct {
+	Title     string
+	Status    string
+	CheckedAt *time.Time
+}
+
+// Result is the outcome of one clause for one license
+type Result struct {
+	Clause      Clause
+	Status      string
+	Explanation string
+	// NextCheck is when the outcome is due to change: today for failures,
+	// otherwise the day the clause stops passing
+	NextCheck time.Time
+}
+
+// Covers reports whether the requirement applies to a license
+func (r *Requirement) Covers(f Facts) bool {
+	return (len(r.AppliesTo.LicenseTypes) == 0 || slices.Contains(r.AppliesTo.LicenseTypes, f.LicenseType)) &&
+		(len(r.AppliesTo.BusinessTypes) == 0 || slices.Contains(r.AppliesTo.BusinessTypes, f.BusinessType))
+}
+
+// Evaluate judges every clause against the facts as of today. It does not
+// check Covers.
+func (r *Requirement) Evaluate(f Facts, today time.Time) []Result {
+	today = day(today)
+	results := make([]Result, 0, len(r.Clauses))
+	for _, c := range r.Clauses {
+		var res Result
+		switch c.Kind {
+		case KindDocument:
+			res = evalDocument(c, f, today)
+		case KindComplianceCheck:
+			res = evalComplianceCheck(c, f, today)
+		case KindLocation:
+			res = evalLocation(c, f, today)
+		}
+		res.Clause = c
+		results = append(results, res)
+	}
+	return results
+}
+
+func fail(c Clause, today time.Time, format string, args ...any) Result {
+	return Result{
+		Status:      c.severity(),
+		Explanation: fmt.Sprintf("Clause %q failed: ", c.ID) + fmt.Sprintf(format, args...),
+		NextCheck:   today,
+	}
+}
+
+// pass is a passing outcome that lasts until expires, or indefinitely if
+// expires is zero
+func pass(c Clause, today, expires time.Time, explanation string) Result {
+	if expires.IsZero() {
+		return Result{Status: StatusCompliant, Explanation: explanation, NextCheck: today.AddDate(0, 0, recheckDays)}
+	}
+	left := daysBetween(today, expires)
+	if c.WarnDays > 0 && left <= c.WarnDays {
+		return Result{
+			Status:      StatusNeedsAttention,
+			Explanation: fmt.Sprintf("Clause %q fails in %d day(s): %s", c.ID, left, explanation),
+			NextCheck:   expires,
+		}
+	}
+	return Result{Status: StatusCompliant, Explanation: explanation, NextCheck: expires}
+}
+
+func evalDocument(c Clause, f Facts, today time.Time) Result {
+	var newest *Document
+	for i, d := range f.Documents {
+		if d.Category != c.Category || (d.ValidUntil != nil && d.ValidUntil.Before(today)) {
+			continue
+		}
+		if newest == nil || d.Issued.After(newest.Issued) {
+			newest = &f.Documents[i]
+		}
+	}
+	if newest == nil {
+		return fail(c, today, "no current %s document is on file", c.Category)
+	}
+
+	age := daysBetween(newest.Issued, today)
+	if c.MaxAgeDays > 0 && age > c.MaxAgeDays {
+		return fail(c, today, "the newest %s document %q is %d days old; it may be at most %d days old",
+			c.Category, newest.Name, age, c.MaxAgeDays)
+	}
+
+	// The document counts until it lapses or grows too old
+	var expires time.Time
+	if newest.ValidUntil != nil {
+		expires = newest.ValidUntil.AddDate(0, 0, 1)
+	}
+	if c.MaxAgeDays > 0 {
+		if tooOld := newest.Issued.AddDate(0, 0, c.MaxAgeDays+1); expires.IsZero() || tooOld.Before(expires) {
+			expires = tooOld
+		}
+	}
+	return pass(c, today, expires, fmt.Sprintf("%s document %q is on file", c.Category, newest.Name))
+}
+
+func evalComplianceCheck(c Clause, f Facts, today time.Time) Result {
+	var last *time.Time
+	for _, check := range f.Checks {
+		if check.Status != StatusCompliant || check.CheckedAt == nil {
+			continue
+		}
+		if last == nil || check.CheckedAt.After(*last) {
+			last = check.CheckedAt
+		}
+	}
+	if last == nil {
+		return fail(c, today, "no compliance check has been completed; one is required every %d days", c.EveryDays)
+	}
+
+	age := daysBetween(*last, today)
+	if age > c.EveryDays {
+		return fail(c, today, "the last compliant check was on %s, %d days ago; one is required every %d days",
+			last.Format(time.DateOnly), age, c.EveryDays)
+	}
+	return pass(c, today, last.AddDate(0, 0, c.EveryDays+1),
+		fmt.Sprintf("last compliant check on %s", last.Format(time.DateOnly)))
+}
+
+func evalLocation(c Clause, f Facts, today time.Time) Result {
+	if f.State == nil {
+		return fail(c, today, "the license is not tied to a location")
+	}
+	if len(c.States) > 0 && !slices.ContainsFunc(c.States, func(s string) bool { return strings.EqualFold(s, *f.State) }) {
+		return fail(c, today, "the location is in %s, not in %s", *f.State, strings.Join(c.States, ", "))
+	}
+	return pass(c, today, time.Time{}, fmt.Sprintf("location in %s", *f.State))
+}
+
+// day truncates t to midnight UTC
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(day(to).Sub(day(from)).Hours() / 24)
+}
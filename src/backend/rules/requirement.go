@@ -0,0 +1,153 @@
+// Package rules evaluates the machine-readable requirements of regulations
+// against licenses.
+//
+// A regulation's requirements JSON names the licenses it applies to and a
+// list of clauses, for example:
+//
+//	{
+//	  "appliesTo": {"licenseTypes": ["RETAIL"]},
+//	  "clauses": [
+//	    {"id": "security-plan", "kind": "document", "category": "SECURITY",
+//	     "maxAgeDays": 365, "warnDays": 30},
+//	    {"id": "quarterly-inspection", "kind": "complianceCheck", "everyDays": 90},
+//	    {"id": "premises", "kind": "location", "states": ["CA"]}
+//	  ]
+//	}
+//
+// The regulation's jurisdiction limits it to licenses issued there. Each
+// clause of an applicable regulation yields one compliance check per
+// license, kept up to date by Runner.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Clause kinds
+const (
+	// KindDocument requires a current document of a category, optionally
+	// issued within MaxAgeDays
+	KindDocument = "document"
+	// KindComplianceCheck requires a compliance check completed as
+	// COMPLIANT within the last EveryDays
+	KindComplianceCheck = "complianceCheck"
+	// KindLocation requires the license to be tied to a location,
+	// optionally in one of States
+	KindLocation = "location"
+)
+
+// Statuses of evaluated clauses, matching the ComplianceStatus enum
+const (
+	StatusCompliant      = "COMPLIANT"
+	StatusNonCompliant   = "NON_COMPLIANT"
+	StatusNeedsAttention = "NEEDS_ATTENTION"
+	StatusNotApplicable  = "NOT_APPLICABLE"
+)
+
+// Requirement is the parsed requirements document of a regulation
+type Requirement struct {
+	AppliesTo Scope    `json:"appliesTo"`
+	Clauses   []Clause `json:"clauses"`
+}
+
+// Scope narrows the licenses a regulation applies to; empty lists match all
+type Scope struct {
+	LicenseTypes  []string `json:"licenseTypes,omitempty"`
+	BusinessTypes []string `json:"businessTypes,omitempty"`
+}
+
+// Clause is one condition a license must meet
+type Clause struct {
+	// ID identifies the clause within its regulation; renaming it starts
+	// a new compliance check
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	Kind        string `json:"kind"`
+	// Severity is the status of a failed clause, NON_COMPLIANT by default
+	Severity string `json:"severity,omitempty"`
+	// WarnDays marks a passing clause NEEDS_ATTENTION when it will fail
+	// within this many days
+	WarnDays int `json:"warnDays,omitempty"`
+
+	Category   string   `json:"category,omitempty"`
+	MaxAgeDays int      `json:"maxAgeDays,omitempty"`
+	EveryDays  int      `json:"everyDays,omitempty"`
+	States     []string `json:"states,omitempty"`
+}
+
+// Parse decodes and validates a requirements document. Unknown fields are
+// rejected so that typos do not silently weaken a rule.
+func Parse(raw []byte) (*Requirement, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var req Requirement
+	if err := dec.Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *Requirement) validate() error {
+	if len(r.Clauses) == 0 {
+		return fmt.Errorf("invalid requirements: no clauses")
+	}
+	seen := map[string]bool{}
+	for i, c := range r.Clauses {
+		if c.ID == "" {
+			return fmt.Errorf("invalid requirements: clause %d has no id", i+1)
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("invalid requirements: duplicate clause id %q", c.ID)
+		}
+		seen[c.ID] = true
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("invalid requirements: clause %q: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+func (c Clause) validate() error {
+	if c.Severity != "" && !slices.Contains([]string{StatusNonCompliant, StatusNeedsAttention}, c.Severity) {
+		return fmt.Errorf("severity must be %s or %s", StatusNonCompliant, StatusNeedsAttention)
+	}
+	if c.WarnDays < 0 || c.MaxAgeDays < 0 || c.EveryDays < 0 {
+		return fmt.Errorf("day counts must not be negative")
+	}
+	switch c.Kind {
+	case KindDocument:
+		if c.Category == "" {
+			return fmt.Errorf("a document clause needs a category")
+		}
+	case KindComplianceCheck:
+		if c.EveryDays == 0 {
+			return fmt.Errorf("a complianceCheck clause needs everyDays")
+		}
+	case KindLocation:
+	default:
+		return fmt.Errorf("unknown kind %q", c.Kind)
+	}
+	return nil
+}
+
+// severity returns the status of the clause when it fails
+func (c Clause) severity() string {
+	if c.Severity != "" {
+		return c.Severity
+	}
+	return StatusNonCompliant
+}
+
+// title describes the clause for the compliance check it produces
+func (c Clause) title() string {
+	if c.Description != "" {
+		return c.Description
+	}
+	return c.ID
+}
@@ -0,0 +1,140 @@
+package rules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func datePtr(s string) *time.Time {
+	t := date(s)
+	return &t
+}
+
+func TestParse(t *testing.T) {
+	req, err := Parse([]byte(`{
+		"appliesTo": {"licenseTypes": ["RETAIL"]},
+		"clauses": [
+			{"id": "security-plan", "kind": "document", "category": "SECURITY", "maxAgeDays": 365},
+			{"id": "inspection", "kind": "complianceCheck", "everyDays": 90, "severity": "NEEDS_ATTENTION"},
+			{"id": "premises", "kind": "location"}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"RETAIL"}, req.AppliesTo.LicenseTypes)
+	require.Len(t, req.Clauses, 3)
+	assert.Equal(t, 365, req.Clauses[0].MaxAgeDays)
+
+	for name, raw := range map[string]string{
+		"no clauses":       `{"clauses": []}`,
+		"unknown field":    `{"clauses": [{"id": "a", "kind": "location", "state": "CA"}]}`,
+		"missing id":       `{"clauses": [{"kind": "location"}]}`,
+		"duplicate id":     `{"clauses": [{"id": "a", "kind": "location"}, {"id": "a", "kind": "location"}]}`,
+		"unknown kind":     `{"clauses": [{"id": "a", "kind": "insurance"}]}`,
+		"no category":      `{"clauses": [{"id": "a", "kind": "document"}]}`,
+		"no interval":      `{"clauses": [{"id": "a", "kind": "complianceCheck"}]}`,
+		"negative days":    `{"clauses": [{"id": "a", "kind": "document", "category": "SECURITY", "maxAgeDays": -1}]}`,
+		"invalid severity": `{"clauses": [{"id": "a", "kind": "location", "severity": "COMPLIANT"}]}`,
+		"not json":         `clauses`,
+	} {
+		_, err := Parse([]byte(raw))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestCovers(t *testing.T) {
+	req := &Requirement{AppliesTo: Scope{LicenseTypes: []string{"RETAIL", "DELIVERY"}, BusinessTypes: []string{"DISPENSARY"}}}
+	assert.True(t, req.Covers(Facts{LicenseType: "RETAIL", BusinessType: "DISPENSARY"}))
+	assert.False(t, req.Covers(Facts{LicenseType: "CULTIVATION", BusinessType: "DISPENSARY"}))
+	assert.False(t, req.Covers(Facts{LicenseType: "RETAIL", BusinessType: "CULTIVATOR"}))
+	assert.True(t, (&Requirement{}).Covers(Facts{LicenseType: "CULTIVATION"}))
+}
+
+func TestEvaluateDocument(t *testing.T) {
+	today := date("2026-06-01")
+	clause := Clause{ID: "security-plan", Kind: KindDocument, Category: "SECURITY", MaxAgeDays: 365, WarnDays: 30}
+	req := &Requirement{Clauses: []Clause{clause}}
+	evaluate := func(docs ...Document) Result {
+		results := req.Evaluate(Facts{Documents: docs}, today)
+		require.Len(t, results, 1)
+		assert.Equal(t, clause, results[0].Clause)
+		return results[0]
+	}
+
+	res := evaluate(Document{Name: "Insurance", Category: "INSURANCE", Issued: date("2026-05-01")})
+	assert.Equal(t, StatusNonCompliant, res.Status)
+	assert.Contains(t, res.Explanation, "no current SECURITY document")
+	assert.Equal(t, today, res.NextCheck)
+
+	res = evaluate(Document{Name: "Plan", Category: "SECURITY", Issued: date("2026-01-01")})
+	assert.Equal(t, StatusCompliant, res.Status)
+	assert.Equal(t, date("2027-01-02"), res.NextCheck)
+
+	// The newest document counts, and it becomes too old within warnDays
+	res = evaluate(
+		Document{Name: "Old plan", Category: "SECURITY", Issued: date("2024-01-01")},
+		Document{Name: "Plan", Category: "SECURITY", Issued: date("2025-06-15")},
+	)
+	assert.Equal(t, StatusNeedsAttention, res.Status)
+	assert.Equal(t, date("2026-06-16"), res.NextCheck)
+
+	res = evaluate(Document{Name: "Plan", Category: "SECURITY", Issued: date("2025-01-01")})
+	assert.Equal(t, StatusNonCompliant, res.Status)
+	assert.Contains(t, res.Explanation, "516 days old")
+
+	// A lapsed document does not count; one lapsing sooner than it ages out sets the next check
+	res = evaluate(Document{Name: "Plan", Category: "SECURITY", Issued: date("2026-01-01"), ValidUntil: datePtr("2026-05-31")})
+	assert.Equal(t, StatusNonCompliant, res.Status)
+	res = evaluate(Document{Name: "Plan", Category: "SECURITY", Issued: date("2026-01-01"), ValidUntil: datePtr("2026-06-10")})
+	assert.Equal(t, StatusNeedsAttention, res.Status)
+	assert.Equal(t, date("2026-06-11"), res.NextCheck)
+}
+
+func TestEvaluateComplianceCheck(t *testing.T) {
+	today := date("2026-06-01")
+	req := &Requirement{Clauses: []Clause{{ID: "inspection", Kind: KindComplianceCheck, EveryDays: 90, Severity: StatusNeedsAttention}}}
+
+	res := req.Evaluate(Facts{Checks: []Check{{Title: "Inspection", Status: StatusNonCompliant, CheckedAt: datePtr("2026-05-30")}}}, today)[0]
+	assert.Equal(t, StatusNeedsAttention, res.Status)
+	assert.Contains(t, res.Explanation, "no compliance check has been completed")
+
+	res = req.Evaluate(Facts{Checks: []Check{
+		{Title: "Inspection", Status: StatusCompliant, CheckedAt: datePtr("2026-01-10")},
+		{Title: "Inspection", Status: StatusCompliant, CheckedAt: datePtr("2026-04-01")},
+		{Title: "Pending", Status: "PENDING"},
+	}}, today)[0]
+	assert.Equal(t, StatusCompliant, res.Status)
+	assert.Equal(t, date("2026-07-01"), res.NextCheck)
+
+	res = req.Evaluate(Facts{Checks: []Check{{Title: "Inspection", Status: StatusCompliant, CheckedAt: datePtr("2026-02-01")}}}, today)[0]
+	assert.Equal(t, StatusNeedsAttention, res.Status)
+	assert.Contains(t, res.Explanation, "120 days ago")
+}
+
+func TestEvaluateLocation(t *testing.T) {
+	today := date("2026-06-01")
+	req := &Requirement{Clauses: []Clause{{ID: "premises", Kind: KindLocation, States: []string{"CA", "OR"}}}}
+	ca, nv := "ca", "NV"
+
+	res := req.Evaluate(Facts{}, today)[0]
+	assert.Equal(t, StatusNonCompliant, res.Status)
+	assert.Contains(t, res.Explanation, "not tied to a location")
+
+	res = req.Evaluate(Facts{State: &nv}, today)[0]
+	assert.Equal(t, StatusNonCompliant, res.Status)
+	assert.Contains(t, res.Explanation, "in NV, not in CA, OR")
+
+	res = req.Evaluate(Facts{State: &ca}, today)[0]
+	assert.Equal(t, StatusCompliant, res.Status)
+	assert.Equal(t, date("2026-07-01"), res.NextCheck)
+}
@@ -0,0 +1,242 @@
+package rules
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Runner evaluates the requirements of every regulation in effect and
+// records the outcomes as compliance checks, one per license and clause.
+// Re-running updates those checks in place; clauses that no longer apply
+// are marked NOT_APPLICABLE.
+type Runner struct {
+	DB *sqlx.DB
+	// Now defaults to time.Now
+	Now func() time.Time
+}
+
+// Run evaluates all businesses in one transaction. It is a scheduler job.
+func (r *Runner) Run(ctx context.Context) error {
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := r.Evaluate(ctx, tx, nil)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit compliance evaluation: %w", err)
+	}
+	log.Printf("rules: evaluated %d compliance check(s)", len(ids))
+	return nil
+}
+
+// regulationRow is a regulation with machine-readable requirements
+type regulationRow struct {
+	ID             string `db:"id"`
+	JurisdictionID string `db:"jurisdiction_id"`
+	Title          string `db:"title"`
+	Requirements   []byte `db:"requirements"`
+}
+
+// licenseRow is a license that regulations may apply to
+type licenseRow struct {
+	ID             string  `db:"id"`
+	JurisdictionID string  `db:"jurisdiction_id"`
+	Type           string  `db:"type"`
+	BusinessType   string  `db:"business_type"`
+	State          *string `db:"state"`
+}
+
+type documentRow struct {
+	LicenseID  string  `db:"license_id"`
+	Name       string  `db:"name"`
+	Category   string  `db:"category"`
+	Issued     string  `db:"issued"`
+	ValidUntil *string `db:"valid_until"`
+}
+
+type checkRow struct {
+	LicenseID string  `db:"license_id"`
+	Title     string  `db:"check_type"`
+	Status    string  `db:"status"`
+	CheckedAt *string `db:"checked_at"`
+}
+
+// Evaluate runs the regulations against the licenses of businessID, or of
+// all businesses if it is nil, and returns the ids of the compliance checks
+// it wrote. Regulations with invalid requirements are logged and skipped.
+func (r *Runner) Evaluate(ctx context.Context, tx *sqlx.Tx, businessID *string) ([]string, error) {
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+	today := day(now())
+
+	var regulations []regulationRow
+	err := tx.SelectContext(ctx, &regulations, `
+		SELECT id, jurisdiction_id, title, requirements
+		FROM regulations
+		WHERE requirements IS NOT NULL AND effective_date <= $1
+		ORDER BY id
+	`, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load regulations: %w", err)
+	}
+	parsed := map[string]*Requirement{}
+	byJurisdiction := map[string][]regulationRow{}
+	for _, reg := range regulations {
+		req, err := Parse(reg.Requirements)
+		if err != nil {
+			log.Printf("rules: skipping regulation %s (%s): %v", reg.ID, reg.Title, err)
+			continue
+		}
+		parsed[reg.ID] = req
+		byJurisdiction[reg.JurisdictionID] = append(byJurisdiction[reg.JurisdictionID], reg)
+	}
+
+	licenses, facts, err := loadFacts(ctx, tx, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, lic := range licenses {
+		for _, reg := range byJurisdiction[lic.JurisdictionID] {
+			req := parsed[reg.ID]
+			var results []Result
+			if req.Covers(facts[lic.ID]) {
+				results = req.Evaluate(facts[lic.ID], today)
+			}
+			written, err := record(ctx, tx, lic.ID, reg, results)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, written...)
+		}
+	}
+	return ids, nil
+}
+
+// loadFacts loads the licenses in scope with what their compliance is
+// judged on. Revoked and expired licenses are not evaluated.
+func loadFacts(ctx context.Context, tx *sqlx.Tx, businessID *string) ([]licenseRow, map[string]Facts, error) {
+	var licenses []licenseRow
+	err := tx.SelectContext(ctx, &licenses, `
+		SELECT l.id, l.jurisdiction_id, l.type, b.type AS business_type, loc.state
+		FROM licenses l
+		JOIN businesses b ON b.id = l.business_id
+		LEFT JOIN locations loc ON loc.id = l.location_id
+		WHERE l.status NOT IN ('REVOKED', 'EXPIRED')
+		  AND ($1::uuid IS NULL OR l.business_id = $1::uuid)
+		ORDER BY l.id
+	`, businessID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load licenses: %w", err)
+	}
+
+	facts := make(map[string]Facts, len(licenses))
+	ids := make(pq.StringArray, len(licenses))
+	for i, lic := range licenses {
+		ids[i] = lic.ID
+		facts[lic.ID] = Facts{LicenseType: lic.Type, BusinessType: lic.BusinessType, State: lic.State}
+	}
+
+	var documents []documentRow
+	err = tx.SelectContext(ctx, &documents, `
+		SELECT COALESCE(d.license_id, rr.license_id) AS license_id, d.name, d.category,
+		       COALESCE(d.valid_from, d.created_at::date)::text AS issued, d.valid_until::text
+		FROM documents d
+		LEFT JOIN renewal_requirements rr ON rr.id = d.renewal_requirement_id
+		WHERE d.superseded_by_id IS NULL
+		  AND d.category IS NOT NULL
+		  AND COALESCE(d.license_id, rr.license_id) = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load documents: %w", err)
+	}
+	for _, d := range documents {
+		doc := Document{Name: d.Name, Category: d.Category, Issued: parseDay(d.Issued)}
+		if d.ValidUntil != nil {
+			until := parseDay(*d.ValidUntil)
+			doc.ValidUntil = &until
+		}
+		f := facts[d.LicenseID]
+		f.Documents = append(f.Documents, doc)
+		facts[d.LicenseID] = f
+	}
+
+	var checks []checkRow
+	err = tx.SelectContext(ctx, &checks, `
+		SELECT license_id, check_type, status, checked_at::date::text AS checked_at
+		FROM compliance_checks
+		WHERE regulation_id IS NULL AND license_id = ANY($1::uuid[])
+	`, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load compliance checks: %w", err)
+	}
+	for _, c := range checks {
+		check := Check{Title: c.Title, Status: c.Status}
+		if c.CheckedAt != nil {
+			at := parseDay(*c.CheckedAt)
+			check.CheckedAt = &at
+		}
+		f := facts[c.LicenseID]
+		f.Checks = append(f.Checks, check)
+		facts[c.LicenseID] = f
+	}
+	return licenses, facts, nil
+}
+
+// record upserts the compliance check of each result and marks the
+// license's other checks from the regulation NOT_APPLICABLE
+func record(ctx context.Context, tx *sqlx.Tx, licenseID string, reg regulationRow, results []Result) ([]string, error) {
+	var ids []string
+	clauses := pq.StringArray{}
+	for _, res := range results {
+		var id string
+		err := tx.GetContext(ctx, &id, `
+			INSERT INTO compliance_checks (license_id, regulation_id, rule_clause, check_type, status,
+			                               checked_at, next_check_date, notes)
+			VALUES ($1, $2, $3, $4, $5, NOW(), $6, $7)
+			ON CONFLICT (license_id, regulation_id, rule_clause) DO UPDATE
+			SET check_type = EXCLUDED.check_type,
+			    status = EXCLUDED.status,
+			    checked_at = EXCLUDED.checked_at,
+			    next_check_date = EXCLUDED.next_check_date,
+			    notes = EXCLUDED.notes,
+			    updated_at = NOW()
+			RETURNING id
+		`, licenseID, reg.ID, res.Clause.ID, reg.Title+": "+res.Clause.title(), res.Status, res.NextCheck, res.Explanation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record compliance check: %w", err)
+		}
+		ids = append(ids, id)
+		clauses = append(clauses, res.Clause.ID)
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE compliance_checks
+		SET status = 'NOT_APPLICABLE', notes = 'The regulation no longer applies to this license', updated_at = NOW()
+		WHERE license_id = $1 AND regulation_id = $2 AND rule_clause <> ALL($3::text[])
+		  AND status <> 'NOT_APPLICABLE'
+	`, licenseID, reg.ID, clauses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retire compliance checks: %w", err)
+	}
+	return ids, nil
+}
+
+func parseDay(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
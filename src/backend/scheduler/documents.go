@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DocumentLapseScan asks business owners and compliance managers for new
+// evidence when the current version of a document lapses while the license
+// or renewal requirement it supports still needs it.
+type DocumentLapseScan struct {
+	DB *sqlx.DB
+}
+
+// Run performs one scan
+func (d *DocumentLapseScan) Run(ctx context.Context) error {
+	var lapsed []pendingReminder
+	err := d.DB.SelectContext(ctx, &lapsed, `
+		SELECT doc.id AS entity_id, m.user_id, doc.valid_until - CURRENT_DATE AS days_left,
+		       doc.valid_until::text AS date, l.license_number, doc.name AS subject
+		FROM documents doc
+		LEFT JOIN renewal_requirements rr ON rr.id = doc.renewal_requirement_id
+		JOIN licenses l ON l.id = COALESCE(doc.license_id, rr.license_id)
+		JOIN business_members m ON m.business_id = l.business_id
+		WHERE m.role IN ('BUSINESS_OWNER', 'COMPLIANCE_MANAGER')
+		  AND doc.superseded_by_id IS NULL
+		  AND doc.valid_until < CURRENT_DATE
+		  AND (
+		    (doc.license_id IS NOT NULL AND l.status IN ('ACTIVE', 'RENEWAL_IN_PROGRESS'))
+		    OR (rr.id IS NOT NULL AND rr.completed_at IS NULL AND l.status NOT IN ('REVOKED', 'EXPIRED'))
+		  )
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to scan lapsed documents: %w", err)
+	}
+
+	reminders := make([]reminder, 0, len(lapsed))
+	for _, p := range lapsed {
+		reminders = append(reminders, documentRequiredReminder(p))
+	}
+
+	tx, err := d.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	created, err := insertReminders(ctx, tx, reminders)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit document lapse scan: %w", err)
+	}
+
+	log.Printf("scheduler: %d lapsed document(s), created %d reminder(s)", len(lapsed), created)
+	return nil
+}
+
+func documentRequiredReminder(p pendingReminder) reminder {
+	return reminder{
+		UserID:            p.UserID,
+		Title:             fmt.Sprintf("%s has lapsed", p.Subject),
+		Message:           fmt.Sprintf("%q for license %s was valid until %s. Upload a current version.", p.Subject, p.LicenseNumber, p.Date),
+		Type:              "DOCUMENT_REQUIRED",
+		RelatedEntityID:   p.EntityID,
+		RelatedEntityType: "Document",
+		// Extending the validity of the same version reminds again when it lapses
+		DedupeKey: fmt.Sprintf("DOCUMENT_REQUIRED:%s:%s", p.EntityID, p.Date),
+	}
+}
@@ -0,0 +1,236 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultLeadDays are the days before an expiration or due date at which
+// reminders go out, in ascending order
+var DefaultLeadDays = []int{7, 30, 60, 90}
+
+// ParseLeadDays parses a comma-separated list of positive day counts such
+// as "90,60,30,7". An empty string yields DefaultLeadDays.
+func ParseLeadDays(s string) ([]int, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultLeadDays, nil
+	}
+	var days []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid lead time %q: must be a positive number of days", part)
+		}
+		days = append(days, n)
+	}
+	slices.Sort(days)
+	return slices.Compact(days), nil
+}
+
+// leadBucket returns the reminder a date daysLeft days away falls into:
+// the smallest of the ascending leadDays that is at least daysLeft. Scanning daily, each
+// bucket is entered once, so keying reminders by bucket sends one reminder
+// per lead time even if a scan is missed.
+func leadBucket(daysLeft int, leadDays []int) (int, bool) {
+	if daysLeft < 0 {
+		return 0, false
+	}
+	for _, lead := range leadDays {
+		if lead >= daysLeft {
+			return lead, true
+		}
+	}
+	return 0, false
+}
+
+// ExpiryScan expires lapsed licenses and reminds business owners and
+// compliance managers of upcoming license expirations and renewal deadlines.
+type ExpiryScan struct {
+	DB       *sqlx.DB
+	LeadDays []int
+}
+
+// pendingReminder is one recipient of a reminder about one entity
+type pendingReminder struct {
+	EntityID      string `db:"entity_id"`
+	UserID        string `db:"user_id"`
+	DaysLeft      int    `db:"days_left"`
+	Date          string `db:"date"`
+	LicenseNumber string `db:"license_number"`
+	Subject       string `db:"subject"`
+}
+
+// reminder is a notification row generated by a scan
+type reminder struct {
+	UserID            string `db:"user_id"`
+	Title             string `db:"title"`
+	Message           string `db:"message"`
+	Type              string `db:"type"`
+	RelatedEntityID   string `db:"related_entity_id"`
+	RelatedEntityType string `db:"related_entity_type"`
+	DedupeKey         string `db:"dedupe_key"`
+}
+
+// Run performs one scan in a single transaction
+func (e *ExpiryScan) Run(ctx context.Context) error {
+	if len(e.LeadDays) == 0 {
+		return fmt.Errorf("no lead times configured")
+	}
+	leadDays := slices.Sorted(slices.Values(e.LeadDays))
+
+	tx, err := e.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	expired, err := expireLicenses(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	maxLead := leadDays[len(leadDays)-1]
+	var reminders []reminder
+
+	var licenses []pendingReminder
+	err = tx.SelectContext(ctx, &licenses, `
+		SELECT l.id AS entity_id, m.user_id, l.expiration_date - CURRENT_DATE AS days_left,
+		       l.expiration_date::text AS date, l.license_number, b.name AS subject
+		FROM licenses l
+		JOIN businesses b ON b.id = l.business_id
+		JOIN business_members m ON m.business_id = l.business_id
+		WHERE m.role IN ('BUSINESS_OWNER', 'COMPLIANCE_MANAGER')
+		  AND l.status IN ('ACTIVE', 'RENEWAL_IN_PROGRESS')
+		  AND l.expiration_date BETWEEN CURRENT_DATE AND CURRENT_DATE + $1::int
+	`, maxLead)
+	if err != nil {
+		return fmt.Errorf("failed to scan expiring licenses: %w", err)
+	}
+	for _, p := range licenses {
+		if lead, ok := leadBucket(p.DaysLeft, leadDays); ok {
+			reminders = append(reminders, licenseExpiringReminder(p, lead))
+		}
+	}
+
+	var requirements []pendingReminder
+	err = tx.SelectContext(ctx, &requirements, `
+		SELECT rr.id AS entity_id, m.user_id, rr.due_date - CURRENT_DATE AS days_left,
+		       rr.due_date::text AS date, l.license_number, rr.description AS subject
+		FROM renewal_requirements rr
+		JOIN licenses l ON l.id = rr.license_id
+		JOIN business_members m ON m.business_id = l.business_id
+		WHERE m.role IN ('BUSINESS_OWNER', 'COMPLIANCE_MANAGER')
+		  AND rr.completed_at IS NULL
+		  AND l.status NOT IN ('REVOKED', 'EXPIRED')
+		  AND rr.due_date BETWEEN CURRENT_DATE AND CURRENT_DATE + $1::int
+	`, maxLead)
+	if err != nil {
+		return fmt.Errorf("failed to scan due renewal requirements: %w", err)
+	}
+	for _, p := range requirements {
+		if lead, ok := leadBucket(p.DaysLeft, leadDays); ok {
+			reminders = append(reminders, renewalDueReminder(p, lead))
+		}
+	}
+
+	created, err := insertReminders(ctx, tx, reminders)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expiry scan: %w", err)
+	}
+
+	log.Printf("scheduler: expired %d license(s), created %d reminder(s)", expired, created)
+	return nil
+}
+
+// expireLicenses marks licenses whose expiration date has passed as EXPIRED
+// and records the change in their status history
+func expireLicenses(ctx context.Context, tx *sqlx.Tx) (int64, error) {
+	result, err := tx.ExecContext(ctx, `
+		WITH lapsed AS (
+			SELECT id, status FROM licenses
+			WHERE expiration_date < CURRENT_DATE
+			  AND status IN ('ACTIVE', 'RENEWAL_IN_PROGRESS')
+			FOR UPDATE
+		), expired AS (
+			UPDATE licenses l
+			SET status = 'EXPIRED', updated_at = NOW()
+			FROM lapsed
+			WHERE l.id = lapsed.id
+			RETURNING l.id, lapsed.status AS from_status
+		)
+		INSERT INTO license_status_history (license_id, from_status, to_status, reason)
+		SELECT id, from_status, 'EXPIRED', 'Expiration date passed' FROM expired
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire licenses: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// insertReminders inserts the reminders not already sent and returns how
+// many were new
+func insertReminders(ctx context.Context, tx *sqlx.Tx, reminders []reminder) (int64, error) {
+	var created int64
+	for _, r := range reminders {
+		result, err := tx.NamedExecContext(ctx, `
+			INSERT INTO notifications (user_id, title, message, type, related_entity_id, related_entity_type, dedupe_key)
+			VALUES (:user_id, :title, :message, :type, :related_entity_id, :related_entity_type, :dedupe_key)
+			ON CONFLICT (user_id, dedupe_key) DO NOTHING
+		`, r)
+		if err != nil {
+			return created, fmt.Errorf("failed to create %s notification: %w", r.Type, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return created, err
+		}
+		created += n
+	}
+	return created, nil
+}
+
+func inDays(days int) string {
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	default:
+		return fmt.Sprintf("in %d days", days)
+	}
+}
+
+func licenseExpiringReminder(p pendingReminder, lead int) reminder {
+	return reminder{
+		UserID:            p.UserID,
+		Title:             fmt.Sprintf("License %s expires %s", p.LicenseNumber, inDays(p.DaysLeft)),
+		Message:           fmt.Sprintf("License %s for %s expires on %s. Start the renewal now to avoid a lapse.", p.LicenseNumber, p.Subject, p.Date),
+		Type:              "LICENSE_EXPIRING",
+		RelatedEntityID:   p.EntityID,
+		RelatedEntityType: "License",
+		// The date is part of the key so that a renewed license is reminded again
+		DedupeKey: fmt.Sprintf("LICENSE_EXPIRING:%s:%s:%d", p.EntityID, p.Date, lead),
+	}
+}
+
+func renewalDueReminder(p pendingReminder, lead int) reminder {
+	return reminder{
+		UserID:            p.UserID,
+		Title:             fmt.Sprintf("Renewal requirement due %s", inDays(p.DaysLeft)),
+		Message:           fmt.Sprintf("%q for license %s is due on %s.", p.Subject, p.LicenseNumber, p.Date),
+		Type:              "RENEWAL_DUE",
+		RelatedEntityID:   p.EntityID,
+		RelatedEntityType: "RenewalRequirement",
+		DedupeKey:         fmt.Sprintf("RENEWAL_DUE:%s:%s:%d", p.EntityID, p.Date, lead),
+	}
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLeadDays(t *testing.T) {
+	days, err := ParseLeadDays("30, 7,90,30")
+	require.NoError(t, err)
+	assert.Equal(t, []int{7, 30, 90}, days)
+
+	days, err = ParseLeadDays("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultLeadDays, days)
+
+	_, err = ParseLeadDays("30,soon")
+	assert.Error(t, err)
+	_, err = ParseLeadDays("0")
+	assert.Error(t, err)
+}
+
+func TestLeadBucket(t *testing.T) {
+	leads := []int{7, 30, 60, 90}
+
+	cases := []struct {
+		daysLeft int
+		bucket   int
+		ok       bool
+	}{
+		{91, 0, false},
+		{90, 90, true},
+		{45, 60, true},
+		{30, 30, true},
+		{8, 30, true},
+		{7, 7, true},
+		{0, 7, true},
+		{-1, 0, false},
+	}
+	for _, tc := range cases {
+		bucket, ok := leadBucket(tc.daysLeft, leads)
+		assert.Equal(t, tc.ok, ok, "daysLeft=%d", tc.daysLeft)
+		assert.Equal(t, tc.bucket, bucket, "daysLeft=%d", tc.daysLeft)
+	}
+}
+
+func TestLicenseExpiringReminder(t *testing.T) {
+	r := licenseExpiringReminder(pendingReminder{
+		EntityID:      "lic-1",
+		UserID:        "user-1",
+		DaysLeft:      1,
+		Date:          "2025-07-01",
+		LicenseNumber: "C11-0000123",
+		Subject:       "Green Leaf",
+	}, 7)
+
+	assert.Equal(t, "License C11-0000123 expires tomorrow", r.Title)
+	assert.Equal(t, "LICENSE_EXPIRING", r.Type)
+	assert.Equal(t, "LICENSE_EXPIRING:lic-1:2025-07-01:7", r.DedupeKey)
+}
+
+func TestDocumentRequiredReminder(t *testing.T) {
+	r := documentRequiredReminder(pendingReminder{
+		EntityID:      "doc-1",
+		UserID:        "user-1",
+		DaysLeft:      -1,
+		Date:          "2025-06-30",
+		LicenseNumber: "C11-0000123",
+		Subject:       "Liability insurance",
+	})
+
+	assert.Equal(t, "Liability insurance has lapsed", r.Title)
+	assert.Equal(t, "DOCUMENT_REQUIRED", r.Type)
+	assert.Equal(t, "Document", r.RelatedEntityType)
+	assert.Equal(t, "DOCUMENT_REQUIRED:doc-1:2025-06-30", r.DedupeKey)
+}
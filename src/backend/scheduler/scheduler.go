@@ -0,0 +1,183 @@
+// Package scheduler runs periodic background jobs on exactly one replica.
+//
+// Every replica runs a Scheduler, but only the one holding a Postgres
+// session-level advisory lock (the leader) executes jobs. If the leader
+// dies its connection closes, the lock is released, and another replica
+// takes over on its next leadership check.
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// leaderLockID is the advisory lock key held by the scheduler leader
+const leaderLockID int64 = 4_827_391_605_119
+
+// DefaultLeaderCheck is how often a follower retries for leadership and
+// the leader verifies its lock connection
+const DefaultLeaderCheck = 30 * time.Second
+
+// JobFunc is the body of a job. It is only called while this replica leads.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      JobFunc
+}
+
+// Scheduler runs registered jobs at fixed intervals while it is the leader
+type Scheduler struct {
+	DB          *sqlx.DB
+	LeaderCheck time.Duration
+
+	jobs []job
+
+	mu   sync.Mutex
+	conn *sqlx.Conn
+	// leader is non-nil while this replica leads, and is closed when that
+	// leadership term ends
+	leader chan struct{}
+}
+
+// New returns a Scheduler without jobs
+func New(db *sqlx.DB) *Scheduler {
+	return &Scheduler{DB: db, LeaderCheck: DefaultLeaderCheck}
+}
+
+// Every registers fn to run once per interval, and once right away when
+// this replica becomes leader. Register jobs before calling Run.
+func (s *Scheduler) Every(name string, interval time.Duration, fn JobFunc) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: fn})
+}
+
+// Run contends for leadership and runs the jobs until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, j := range s.jobs {
+		wg.Add(1)
+		go func(j job) {
+			defer wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+
+	ticker := time.NewTicker(s.LeaderCheck)
+	defer ticker.Stop()
+	for {
+		s.checkLeadership(ctx)
+		select {
+		case <-ctx.Done():
+			s.resign()
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loop waits for leadership, then runs j every interval while leading
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	for {
+		leading := s.waitLeader(ctx)
+		if leading == nil {
+			return
+		}
+
+		ticker := time.NewTicker(j.interval)
+		for running := true; running; {
+			s.runJob(ctx, j)
+			select {
+			case <-ctx.Done():
+				ticker.Stop()
+				return
+			case <-leading:
+				running = false
+			case <-ticker.C:
+			}
+		}
+		ticker.Stop()
+	}
+}
+
+func (s *Scheduler) runJob(ctx context.Context, j job) {
+	start := time.Now()
+	if err := j.run(ctx); err != nil {
+		log.Printf("scheduler: job %s failed after %s: %v", j.name, time.Since(start).Round(time.Millisecond), err)
+		return
+	}
+	log.Printf("scheduler: job %s finished in %s", j.name, time.Since(start).Round(time.Millisecond))
+}
+
+// waitLeader blocks until this replica leads and returns the channel of
+// the current leadership term, or returns nil when ctx is done first
+func (s *Scheduler) waitLeader(ctx context.Context) chan struct{} {
+	for {
+		s.mu.Lock()
+		leading := s.leader
+		s.mu.Unlock()
+		if leading != nil {
+			return leading
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// checkLeadership tries to acquire the lock as a follower, or verifies the
+// lock connection as the leader
+func (s *Scheduler) checkLeadership(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		if err := s.conn.PingContext(ctx); err == nil {
+			return
+		}
+		log.Printf("scheduler: lost leader lock connection")
+		s.stepDownLocked()
+	}
+
+	conn, err := s.DB.Connx(ctx)
+	if err != nil {
+		log.Printf("scheduler: failed to acquire connection: %v", err)
+		return
+	}
+	var acquired bool
+	if err := conn.GetContext(ctx, &acquired, "SELECT pg_try_advisory_lock($1)", leaderLockID); err != nil || !acquired {
+		if err != nil {
+			log.Printf("scheduler: failed to try leader lock: %v", err)
+		}
+		conn.Close()
+		return
+	}
+
+	log.Printf("scheduler: this replica is now the leader")
+	s.conn = conn
+	s.leader = make(chan struct{})
+}
+
+// resign releases the leader lock
+func (s *Scheduler) resign() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		s.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", leaderLockID)
+		s.stepDownLocked()
+	}
+}
+
+func (s *Scheduler) stepDownLocked() {
+	close(s.leader)
+	s.leader = nil
+	s.conn.Close()
+	s.conn = nil
+}
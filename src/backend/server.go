@@ -2,50 +2,83 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"budsafe/backend/audit"
 	"budsafe/backend/auth"
+	"budsafe/backend/email"
 	"budsafe/backend/graph"
 	"budsafe/backend/graph/generated"
+	"budsafe/backend/migrations"
+	"budsafe/backend/pubsub"
+	"budsafe/backend/recurrence"
+	"budsafe/backend/reporting"
+	"budsafe/backend/rules"
+	"budsafe/backend/scheduler"
+	"budsafe/backend/storage"
+	"budsafe/backend/tracking"
+	"budsafe/backend/webhook"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 func main() {
-	// Load environment variables from root .env.local
-	// IMPORTANT: Make sure your .env.local contains the GOOGLE_APPLICATION_CREDENTIALS variable
-	// pointing to your service account JSON file for local development.
-	// e.g., GOOGLE_APPLICATION_CREDENTIALS=../path/to/your/serviceAccountKey.json
-	rootDir := filepath.Join("../..", ".env.local")
-	if err := godotenv.Load(rootDir); err != nil {
-		log.Printf("Warning: Could not load .env.local file: %v", err)
-	}
+	loadEnv()
 
-	// Get database connection info
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		log.Fatal("DATABASE_URL environment variable is required")
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
-	
-	// Connect to database
-	db, err := sqlx.Connect("postgres", dbURL)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+	if len(os.Args) > 1 && os.Args[1] == "import-licenses" {
+		if err := runImportLicenses(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		if err := runAudit(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	db := connectDB()
 	defer db.Close()
 
-	// Test database connection
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to ping database: %v", err)
+	// Refuse to serve against a schema that is behind the binary
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to check database migrations: %v", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("Database schema is behind: %d pending migration(s), starting with %04d_%s. Run `budsafe migrate up` first.",
+			len(pending), pending[0].Version, pending[0].Name)
 	}
-	log.Println("Successfully connected to PostgreSQL database!")
 
 	// Initialize Firebase Auth client
 	authClient, err := auth.Init(context.Background())
@@ -55,8 +88,67 @@ func main() {
 	log.Println("Successfully initialized Firebase Auth client!")
 
 	// Create GraphQL server with database connection
-	resolver := &graph.Resolver{DB: db}
-	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{Resolvers: resolver}))
+	// Fan database change events out to GraphQL subscriptions
+	hub := pubsub.NewHub()
+	go func() {
+		if err := hub.Listen(context.Background(), os.Getenv("DATABASE_URL")); err != nil {
+			log.Fatalf("Change event listener stopped: %v", err)
+		}
+	}()
+
+	// Document file storage
+	files, filesHandler, err := newStorage(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to configure document storage: %v", err)
+	}
+	uploadPolicy := storage.Policy{}
+	if v := os.Getenv("MAX_UPLOAD_BYTES"); v != "" {
+		if uploadPolicy.MaxSize, err = strconv.ParseInt(v, 10, 64); err != nil || uploadPolicy.MaxSize <= 0 {
+			log.Fatalf("Invalid MAX_UPLOAD_BYTES %q", v)
+		}
+	}
+
+	// Background jobs; every replica contends, only the leader runs them
+	leadDays, err := scheduler.ParseLeadDays(os.Getenv("EXPIRY_LEAD_DAYS"))
+	if err != nil {
+		log.Fatalf("Invalid EXPIRY_LEAD_DAYS: %v", err)
+	}
+	jobs := scheduler.New(db)
+	jobs.Every("license-expiry", 24*time.Hour, (&scheduler.ExpiryScan{DB: db, LeadDays: leadDays}).Run)
+	jobs.Every("document-lapse", 24*time.Hour, (&scheduler.DocumentLapseScan{DB: db}).Run)
+	jobs.Every("compliance-rules", 24*time.Hour, (&rules.Runner{DB: db}).Run)
+	jobs.Every("compliance-schedules", time.Hour, (&recurrence.Generator{DB: db}).Run)
+	// Webhooks to localhost are for development only
+	allowLocalWebhooks := os.Getenv("WEBHOOK_ALLOW_LOCALHOST") == "true"
+	jobs.Every("webhook-delivery", 30*time.Second, (&webhook.Dispatcher{DB: db, AllowLocalhost: allowLocalWebhooks}).Run)
+	jobs.Every("audit-seal", 10*time.Second, (&audit.Sealer{DB: db}).Run)
+	jobs.Every("report-generation", 15*time.Second, (&reporting.Generator{DB: db, Storage: files, Prefix: graph.DocumentsPrefix}).Run)
+	if os.Getenv("SMTP_ADDR") != "" {
+		dispatcher, err := newEmailDispatcher(db)
+		if err != nil {
+			log.Fatalf("Failed to configure email delivery: %v", err)
+		}
+		jobs.Every("email-delivery", time.Minute, dispatcher.Run)
+	}
+	if os.Getenv("METRC_URL") != "" {
+		reconciler, err := newTrackingReconciler(db)
+		if err != nil {
+			log.Fatalf("Failed to configure tracking reconciliation: %v", err)
+		}
+		jobs.Every("tracking-reconcile", 24*time.Hour, reconciler.Run)
+	}
+	go jobs.Run(context.Background())
+
+	resolver := &graph.Resolver{
+		DB:                 db,
+		AutoProvision:      os.Getenv("AUTH_AUTO_PROVISION") == "true",
+		Hub:                hub,
+		Storage:            files,
+		UploadPolicy:       uploadPolicy,
+		PublicURL:          publicURL(),
+		AllowLocalWebhooks: allowLocalWebhooks,
+	}
+	srv := newGraphQLServer(resolver, authClient)
 
 
 	// --- CORS Middleware ---
@@ -64,7 +156,8 @@ func main() {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*") // Change to your frontend URL in production
 			w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
 				return
@@ -75,7 +168,16 @@ func main() {
 
 	// GraphQL playground
 	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", corsMiddleware(authClient.Middleware(srv)))
+	// Request IDs and client IPs for the audit log; behind a proxy that
+	// appends X-Forwarded-For, set TRUST_PROXY=true
+	requests := audit.Middleware(os.Getenv("TRUST_PROXY") == "true")
+	http.Handle("/query", corsMiddleware(requests(authClient.Middleware(srv))))
+	if filesHandler != nil {
+		// Signed download links of the local storage backend
+		http.Handle("/files/", http.StripPrefix("/files", filesHandler))
+	}
+	// Calendar feeds, authenticated by the token in their URL
+	http.Handle("/calendar/", http.StripPrefix("/calendar", resolver.CalendarHandler()))
 
 	// Health check endpoints
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -99,3 +201,182 @@ func main() {
 	log.Printf("GraphQL playground at http://localhost:%s/", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
+
+// newGraphQLServer mirrors handler.NewDefaultServer, with a websocket
+// transport that authenticates graphql-ws connections for subscriptions.
+func newGraphQLServer(resolver *graph.Resolver, authClient *auth.AuthClient) *handler.Server {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{
+		Resolvers:  resolver,
+		Directives: resolver.Directives(),
+	}))
+
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              authClient.WebsocketInit,
+		Upgrader: websocket.Upgrader{
+			// Like the CORS policy below; websocket auth uses the init
+			// payload token, never cookies, so any origin is safe.
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		// Leave room for the operation itself; storage.Save enforces the
+		// exact limit while streaming the file
+		MaxUploadSize: resolver.UploadPolicy.Limit() + 1<<20,
+	})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
+
+	srv.AroundOperations(graph.ViewerCache)
+	srv.AroundOperations(graph.DataLoaders(resolver))
+	return srv
+}
+
+// loadEnv loads environment variables from the root .env.local
+func loadEnv() {
+	// IMPORTANT: Make sure your .env.local contains the GOOGLE_APPLICATION_CREDENTIALS variable
+	// pointing to your service account JSON file for local development.
+	// e.g., GOOGLE_APPLICATION_CREDENTIALS=../path/to/your/serviceAccountKey.json
+	rootDir := filepath.Join("../..", ".env.local")
+	if err := godotenv.Load(rootDir); err != nil {
+		log.Printf("Warning: Could not load .env.local file: %v", err)
+	}
+}
+
+// publicURL returns the absolute base URL clients reach the server at, from
+// PUBLIC_URL or else localhost on PORT
+func publicURL() string {
+	if u := os.Getenv("PUBLIC_URL"); u != "" {
+		return strings.TrimSuffix(u, "/")
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	return "http://localhost:" + port
+}
+
+// newStorage configures the document storage backend from STORAGE_BACKEND:
+// "local" (the default) keeps files in STORAGE_DIR and serves them through
+// the returned handler, "gcs" uses the STORAGE_BUCKET bucket.
+func newStorage(ctx context.Context) (storage.Storage, http.Handler, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "local":
+		dir := os.Getenv("STORAGE_DIR")
+		if dir == "" {
+			dir = "uploads"
+		}
+		secret := []byte(os.Getenv("STORAGE_SIGNING_KEY"))
+		if len(secret) == 0 {
+			// Links then stop working on restart and across replicas
+			log.Println("Warning: STORAGE_SIGNING_KEY is not set; using a random key for download links")
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return nil, nil, err
+			}
+		}
+		local, err := storage.NewLocal(dir, publicURL()+"/files", secret)
+		if err != nil {
+			return nil, nil, err
+		}
+		return local, local.Handler(), nil
+	case "gcs":
+		bucket := os.Getenv("STORAGE_BUCKET")
+		if bucket == "" {
+			return nil, nil, fmt.Errorf("STORAGE_BUCKET is required with STORAGE_BACKEND=gcs")
+		}
+		gcs, err := storage.NewGCS(ctx, bucket)
+		return gcs, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
+
+// newEmailDispatcher configures email delivery of notifications through the
+// SMTP server at SMTP_ADDR (host:port), sending from SMTP_FROM. SMTP_USERNAME
+// and SMTP_PASSWORD authenticate, SMTP_TLS=implicit connects with TLS from
+// the start, and messages link to APP_URL if it is set.
+func newEmailDispatcher(db *sqlx.DB) (*email.Dispatcher, error) {
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		return nil, fmt.Errorf("SMTP_FROM is required with SMTP_ADDR")
+	}
+	templates, err := email.LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+	return &email.Dispatcher{
+		DB: db,
+		Transport: &email.SMTP{
+			Addr:        os.Getenv("SMTP_ADDR"),
+			Username:    os.Getenv("SMTP_USERNAME"),
+			Password:    os.Getenv("SMTP_PASSWORD"),
+			ImplicitTLS: os.Getenv("SMTP_TLS") == "implicit",
+		},
+		Templates: templates,
+		From:      from,
+		AppURL:    os.Getenv("APP_URL"),
+	}, nil
+}
+
+// newTrackingReconciler configures reconciliation with the state tracking
+// system at METRC_URL. METRC_URL=mock serves the fixture in METRC_MOCK_FILE,
+// if any, from an in-process mock instead.
+func newTrackingReconciler(db *sqlx.DB) (*tracking.Reconciler, error) {
+	jurisdiction := os.Getenv("METRC_JURISDICTION")
+	if jurisdiction == "" {
+		return nil, fmt.Errorf("METRC_JURISDICTION is required with METRC_URL")
+	}
+	client := &tracking.APIClient{
+		BaseURL:   os.Getenv("METRC_URL"),
+		VendorKey: os.Getenv("METRC_VENDOR_KEY"),
+		UserKey:   os.Getenv("METRC_USER_KEY"),
+	}
+	if client.BaseURL == "mock" {
+		var data tracking.MockData
+		if path := os.Getenv("METRC_MOCK_FILE"); path != "" {
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, fmt.Errorf("invalid METRC_MOCK_FILE: %w", err)
+			}
+		}
+		mock := httptest.NewServer(tracking.NewMock(client.VendorKey, client.UserKey, data))
+		client.BaseURL = mock.URL
+		log.Printf("Serving a mock tracking system for %s at %s", jurisdiction, mock.URL)
+	}
+	return &tracking.Reconciler{DB: db, Client: client, Jurisdiction: jurisdiction}, nil
+}
+
+// connectDB opens and pings the database named by DATABASE_URL
+func connectDB() *sqlx.DB {
+	// Get database connection info
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+
+	// Connect to database
+	db, err := sqlx.Connect("postgres", dbURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Test database connection
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+	log.Println("Successfully connected to PostgreSQL database!")
+
+	return db
+}
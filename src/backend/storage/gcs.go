@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// GCS stores files in a Google Cloud Storage bucket, such as the default
+// bucket of the Firebase project. The bucket stays private (storage.rules
+// denies all client access); downloads go through V4 signed URLs.
+type GCS struct {
+	Bucket *gcs.BucketHandle
+}
+
+// NewGCS connects to bucket with the application default credentials.
+// Signing URLs needs a service account key or, on Cloud Run and other
+// Google hosted runtimes, the IAM signBlob permission.
+func NewGCS(ctx context.Context, bucket string) (*GCS, error) {
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create storage client: %w", err)
+	}
+	return &GCS{Bucket: client.Bucket(bucket)}, nil
+}
+
+// Put uploads r to key. The upload is cancelled, leaving no object, when r
+// fails.
+func (g *GCS) Put(ctx context.Context, key, contentType string, r io.Reader) error {
+	if !validKey(key) {
+		return fmt.Errorf("storage: invalid key %q", key)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := g.Bucket.Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	w.CacheControl = "private, no-store"
+	if _, err := io.Copy(w, r); err != nil {
+		cancel()
+		w.Close()
+		return fmt.Errorf("store %s: %w", key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the object stored under key
+func (g *GCS) Delete(ctx context.Context, key string) error {
+	err := g.Bucket.Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, gcs.ErrObjectNotExist) {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL returns a V4 signed GET URL for key
+func (g *GCS) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := g.Bucket.SignedURL(key, &gcs.SignedURLOptions{
+		Scheme:  gcs.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", fmt.Errorf("sign url for %s: %w", key, err)
+	}
+	return u, nil
+}
@@ -0,0 +1,212 @@
+// Package storage keeps uploaded document files outside the database.
+//
+// Files are written under random keys through a Storage backend and are
+// never publicly readable. Clients download them through short-lived signed
+// URLs that the backend issues on request.
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Limits on uploaded files
+const (
+	DefaultMaxSize = 25 << 20
+	// sniffLen is how much of a file http.DetectContentType looks at
+	sniffLen = 512
+)
+
+// DefaultAllowedTypes are the content types accepted for documents:
+// scanned paperwork, photos and plain text exports
+var DefaultAllowedTypes = []string{
+	"application/pdf",
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"text/plain",
+	"text/csv",
+}
+
+// extensions are the file extensions of the content types files are stored
+// as. A key ends in the extension of its file's content type, never in one
+// the client chose, and downloads are served as that type.
+var extensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"text/plain":      ".txt",
+	"text/csv":        ".csv",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": ".xlsx",
+}
+
+// ErrTooLarge is returned when an upload exceeds the size limit
+var ErrTooLarge = errors.New("storage: file too large")
+
+// ErrTypeNotAllowed is returned when an upload's sniffed content type is not
+// in the allowed list
+var ErrTypeNotAllowed = errors.New("storage: content type not allowed")
+
+// Storage is a backend that stores files by key
+type Storage interface {
+	// Put stores the contents of r under key. If r fails, nothing is
+	// left behind under key.
+	Put(ctx context.Context, key, contentType string, r io.Reader) error
+	// Delete removes key; deleting a missing key is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that downloads key until ttl has passed
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Policy restricts what Save accepts
+type Policy struct {
+	// MaxSize is the largest accepted file in bytes; DefaultMaxSize if zero
+	MaxSize int64
+	// AllowedTypes are the accepted sniffed content types, without
+	// parameters; DefaultAllowedTypes if empty
+	AllowedTypes []string
+}
+
+// Limit returns the effective maximum file size
+func (p Policy) Limit() int64 {
+	if p.MaxSize > 0 {
+		return p.MaxSize
+	}
+	return DefaultMaxSize
+}
+
+func (p Policy) allowed(contentType string) bool {
+	allowed := p.AllowedTypes
+	if len(allowed) == 0 {
+		allowed = DefaultAllowedTypes
+	}
+	return slices.Contains(allowed, contentType)
+}
+
+// Object describes a stored file
+type Object struct {
+	Key         string
+	ContentType string
+	Size        int64
+	// SHA256 is the hex encoded checksum of the contents
+	SHA256 string
+}
+
+// Save checks r against the policy and stores it under a new random key
+// beneath prefix. The content type is sniffed from the data rather than
+// trusted from the client; filename only decides between types that look
+// alike, such as CSV and plain text.
+func Save(ctx context.Context, s Storage, p Policy, prefix, filename string, r io.Reader) (*Object, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	contentType := sniff(head, filename)
+	if !p.allowed(contentType) {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotAllowed, contentType)
+	}
+
+	obj, err := store(ctx, s, prefix, contentType, br, p.Limit())
+	if errors.Is(err, ErrTooLarge) {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", ErrTooLarge, p.Limit())
+	}
+	return obj, err
+}
+
+// Store stores a file the server produced itself, such as a generated
+// report, under a new random key beneath prefix. Unlike Save it trusts
+// contentType and applies no policy.
+func Store(ctx context.Context, s Storage, prefix, contentType string, r io.Reader) (*Object, error) {
+	return store(ctx, s, prefix, contentType, r, math.MaxInt64)
+}
+
+// store puts r under a new key, counting and hashing it on the way
+func store(ctx context.Context, s Storage, prefix, contentType string, r io.Reader, limit int64) (*Object, error) {
+	key, err := newKey(prefix, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	counted := &limitedReader{r: io.TeeReader(r, hash), limit: limit}
+	if err := s.Put(ctx, key, contentType, counted); err != nil {
+		return nil, err
+	}
+	return &Object{
+		Key:         key,
+		ContentType: contentType,
+		Size:        counted.read,
+		SHA256:      hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+// sniff returns the parameterless content type of a file starting with head
+func sniff(head []byte, filename string) string {
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if contentType == "text/plain" && strings.EqualFold(path.Ext(filename), ".csv") {
+		return "text/csv"
+	}
+	return contentType
+}
+
+// newKey returns a fresh key under prefix, with the extension of the
+// content type so that downloads get a sensible name
+func newKey(prefix, contentType string) (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("generate storage key: %w", err)
+	}
+	return path.Join(prefix, hex.EncodeToString(id[:])+extensions[contentType]), nil
+}
+
+// keyContentType returns the content type a key was stored as, judging by
+// its extension, or application/octet-stream for keys without a known one
+func keyContentType(key string) string {
+	ext := path.Ext(key)
+	for contentType, e := range extensions {
+		if e == ext {
+			return contentType
+		}
+	}
+	return "application/octet-stream"
+}
+
+// limitedReader fails with ErrTooLarge once more than limit bytes are
+// read, so that backends abort oversized uploads mid-stream
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	read  int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// validKey rejects keys that could escape a backend's root
+func validKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, "/") && path.Clean(key) == key &&
+		!strings.HasPrefix(key, "../") && key != ".."
+}
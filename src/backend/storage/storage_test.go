@@ -0,0 +1,175 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pdf = []byte("%PDF-1.7\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+
+func newTestLocal(t *testing.T) *Local {
+	t.Helper()
+	l, err := NewLocal(t.TempDir(), "http://files.test/files/", []byte("test-secret"))
+	require.NoError(t, err)
+	return l
+}
+
+func TestSave(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+
+	obj, err := Save(ctx, l, Policy{}, "documents", "permit.PDF", bytes.NewReader(pdf))
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", obj.ContentType)
+	assert.Equal(t, int64(len(pdf)), obj.Size)
+	sum := sha256.Sum256(pdf)
+	assert.Equal(t, hex.EncodeToString(sum[:]), obj.SHA256)
+	assert.True(t, strings.HasPrefix(obj.Key, "documents/"))
+	assert.True(t, strings.HasSuffix(obj.Key, ".pdf"))
+
+	stored, err := os.ReadFile(filepath.Join(l.Dir, obj.Key))
+	require.NoError(t, err)
+	assert.Equal(t, pdf, stored)
+
+	csv, err := Save(ctx, l, Policy{}, "documents", "inventory.csv", strings.NewReader("sku,qty\nA1,3\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", csv.ContentType)
+}
+
+func TestSaveRejectsSniffedType(t *testing.T) {
+	l := newTestLocal(t)
+
+	// Named like a PDF, but an executable
+	_, err := Save(context.Background(), l, Policy{}, "documents", "permit.pdf",
+		bytes.NewReader(append([]byte("MZ\x90\x00"), make([]byte, 100)...)))
+	assert.ErrorIs(t, err, ErrTypeNotAllowed)
+
+	_, err = Save(context.Background(), l, Policy{AllowedTypes: []string{"image/png"}}, "documents", "permit.pdf", bytes.NewReader(pdf))
+	assert.ErrorIs(t, err, ErrTypeNotAllowed)
+}
+
+func TestSaveEnforcesMaxSize(t *testing.T) {
+	l := newTestLocal(t)
+
+	_, err := Save(context.Background(), l, Policy{MaxSize: 10}, "documents", "permit.pdf", bytes.NewReader(pdf))
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	// Nothing is left behind
+	var files []string
+	filepath.WalkDir(l.Dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	assert.Empty(t, files)
+
+	_, err = Save(context.Background(), l, Policy{MaxSize: int64(len(pdf))}, "documents", "permit.pdf", bytes.NewReader(pdf))
+	assert.NoError(t, err)
+}
+
+func TestStoreTrustsContentType(t *testing.T) {
+	l := newTestLocal(t)
+	xlsx := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	// A zip archive, which Save would sniff and reject
+	data := []byte("PK\x03\x04 report")
+	obj, err := Store(context.Background(), l, "reports", xlsx, bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, xlsx, obj.ContentType)
+	assert.Equal(t, int64(len(data)), obj.Size)
+	assert.True(t, strings.HasPrefix(obj.Key, "reports/"))
+	assert.True(t, strings.HasSuffix(obj.Key, ".xlsx"))
+
+	stored, err := os.ReadFile(filepath.Join(l.Dir, obj.Key))
+	require.NoError(t, err)
+	assert.Equal(t, data, stored)
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	l := newTestLocal(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	ctx := context.Background()
+	require.NoError(t, l.Put(ctx, "documents/abc.pdf", "application/pdf", bytes.NewReader(pdf)))
+
+	signed, err := l.SignedURL(ctx, "documents/abc.pdf", 5*time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(signed, "http://files.test/files/documents/abc.pdf?"))
+
+	handler := http.StripPrefix("/files", l.Handler())
+	get := func(rawURL string) *httptest.ResponseRecorder {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+		return rec
+	}
+
+	rec := get(signed)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	assert.Equal(t, pdf, body)
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+
+	// Another key with the same signature
+	assert.Equal(t, http.StatusForbidden, get(strings.Replace(signed, "abc.pdf", "xyz.pdf", 1)).Code)
+
+	// Tampered expiry
+	assert.Equal(t, http.StatusForbidden, get(strings.Replace(signed, "expires=", "expires=9", 1)).Code)
+
+	now = now.Add(6 * time.Minute)
+	assert.Equal(t, http.StatusForbidden, get(signed).Code)
+}
+
+func TestLocalServesStoredType(t *testing.T) {
+	l := newTestLocal(t)
+	ctx := context.Background()
+
+	// Plain text named like a page is stored and served as plain text
+	obj, err := Save(ctx, l, Policy{}, "documents", "x.html", strings.NewReader("hi<script>alert(1)</script>"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", obj.ContentType)
+	assert.True(t, strings.HasSuffix(obj.Key, ".txt"))
+
+	signed, err := l.SignedURL(ctx, obj.Key, time.Minute)
+	require.NoError(t, err)
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	http.StripPrefix("/files", l.Handler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Disposition"), "attachment;"))
+
+	// Keys stored before under a client's extension are served as opaque data
+	require.NoError(t, l.Put(ctx, "documents/old.html", "text/plain", strings.NewReader("hi<script>alert(1)</script>")))
+	signed, err = l.SignedURL(ctx, "documents/old.html", time.Minute)
+	require.NoError(t, err)
+	u, err = url.Parse(signed)
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	http.StripPrefix("/files", l.Handler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+	assert.Equal(t, "application/octet-stream", rec.Header().Get("Content-Type"))
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	l := newTestLocal(t)
+	for _, key := range []string{"", "../secret", "/etc/passwd", "a/../../b"} {
+		assert.Error(t, l.Put(context.Background(), key, "text/plain", strings.NewReader("x")), key)
+	}
+}
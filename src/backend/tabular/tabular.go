@@ -0,0 +1,180 @@
+// Package tabular reads spreadsheets, CSV or XLSX, as records keyed by
+// their header row.
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// MaxRows bounds the records of one file
+const MaxRows = 10000
+
+// Record is one row of a file below its header
+type Record struct {
+	// Row is the 1-based row number in the file, counting the header
+	Row int
+	// Fields maps the Key of each column header to the cell, trimmed;
+	// cells of empty header columns are dropped
+	Fields map[string]string
+}
+
+// Get returns the first non-empty field of the keys
+func (r Record) Get(keys ...string) string {
+	for _, key := range keys {
+		if v := r.Fields[key]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Key normalizes a column header, so that "License Number",
+// "license_number" and "licenseNumber" all read as "licensenumber"
+func Key(header string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, header)
+}
+
+// zipMagic starts every XLSX file
+var zipMagic = []byte("PK\x03\x04")
+
+// Read reads the records of a CSV or XLSX file. The format is told by the
+// file's contents, falling back to the name. XLSX files are read from
+// their first sheet; dates in them come as the cell's formatted text.
+func Read(name string, r io.Reader) ([]Record, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(len(zipMagic))
+	var rows []row
+	var err error
+	switch {
+	case bytes.Equal(head, zipMagic):
+		rows, err = readXLSX(br)
+	case strings.EqualFold(filepath.Ext(name), ".xlsx"):
+		return nil, errors.New("the file is not a valid XLSX workbook")
+	default:
+		rows, err = readCSV(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records(rows)
+}
+
+// row is a row of cells and its 1-based number in the file
+type row struct {
+	number int
+	cells  []string
+}
+
+func readCSV(r io.Reader) ([]row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	var rows []row
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(rows) > MaxRows {
+			return nil, fmt.Errorf("the file has more than %d rows", MaxRows)
+		}
+		// The reader skips empty lines; number rows by the line they start on
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, row{number: line, cells: cells})
+	}
+}
+
+func readXLSX(r io.Reader) ([]row, error) {
+	book, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX workbook: %w", err)
+	}
+	defer book.Close()
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("the workbook has no sheets")
+	}
+	rows, err := book.Rows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet %q: %w", sheets[0], err)
+	}
+	defer rows.Close()
+	var all []row
+	for rows.Next() {
+		if len(all) > MaxRows {
+			return nil, fmt.Errorf("the file has more than %d rows", MaxRows)
+		}
+		cells, err := rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sheet %q: %w", sheets[0], err)
+		}
+		all = append(all, row{number: len(all) + 1, cells: cells})
+	}
+	return all, rows.Error()
+}
+
+// records keys the rows below the first non-blank one by its headers,
+// skipping blank rows
+func records(rows []row) ([]Record, error) {
+	headerRow := -1
+	for i, r := range rows {
+		if !blank(r.cells) {
+			headerRow = i
+			break
+		}
+	}
+	if headerRow < 0 {
+		return nil, errors.New("the file is empty")
+	}
+	headers := rows[headerRow].cells
+	keys := make([]string, len(headers))
+	for i, header := range headers {
+		keys[i] = Key(header)
+		if keys[i] != "" && slices.Contains(keys[:i], keys[i]) {
+			return nil, fmt.Errorf("column %q appears twice", strings.TrimSpace(header))
+		}
+	}
+
+	var records []Record
+	for _, r := range rows[headerRow+1:] {
+		if blank(r.cells) {
+			continue
+		}
+		rec := Record{Row: r.number, Fields: make(map[string]string, len(keys))}
+		for j, cell := range r.cells {
+			if j < len(keys) && keys[j] != "" {
+				rec.Fields[keys[j]] = strings.TrimSpace(cell)
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func blank(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
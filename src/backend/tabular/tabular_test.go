@@ -0,0 +1,65 @@
+package tabular
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestKey(t *testing.T) {
+	for _, header := range []string{"License Number", "license_number", "licenseNumber", " LICENSE-NUMBER "} {
+		assert.Equal(t, "licensenumber", Key(header))
+	}
+}
+
+func TestRead_CSV(t *testing.T) {
+	file := "\n License Number,Type,,Notes\n" +
+		"C10-1, RETAIL,ignored\n" +
+		",,,\n" +
+		"\"C10-2\",CULTIVATION,,\"Vault, rear\"\n"
+	records, err := Read("licenses.csv", strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+
+	assert.Equal(t, 3, records[0].Row)
+	assert.Equal(t, map[string]string{"licensenumber": "C10-1", "type": "RETAIL"}, records[0].Fields)
+	assert.Equal(t, 5, records[1].Row)
+	assert.Equal(t, "Vault, rear", records[1].Get("note", "notes"))
+	assert.Equal(t, "", records[1].Get("missing"))
+}
+
+func TestRead_XLSX(t *testing.T) {
+	book := excelize.NewFile()
+	sheet := book.GetSheetName(0)
+	require.NoError(t, book.SetSheetRow(sheet, "A1", &[]any{"License Number", "Expiration Date"}))
+	require.NoError(t, book.SetSheetRow(sheet, "A2", &[]any{"C10-1", "2026-01-31"}))
+	require.NoError(t, book.SetSheetRow(sheet, "A4", &[]any{"C10-2", 42}))
+	var buf bytes.Buffer
+	require.NoError(t, book.Write(&buf))
+
+	// The contents tell the format, whatever the name
+	records, err := Read("upload", &buf)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, Record{Row: 2, Fields: map[string]string{"licensenumber": "C10-1", "expirationdate": "2026-01-31"}}, records[0])
+	assert.Equal(t, Record{Row: 4, Fields: map[string]string{"licensenumber": "C10-2", "expirationdate": "42"}}, records[1])
+}
+
+func TestRead_Errors(t *testing.T) {
+	cases := map[string]struct{ name, file string }{
+		"empty":            {"a.csv", " \n,,\n"},
+		"duplicate column": {"a.csv", "Type,type\nRETAIL,RETAIL\n"},
+		"bad quoting":      {"a.csv", "Type\n\"RETAIL\n"},
+		"not a workbook":   {"a.xlsx", "Type\nRETAIL\n"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Read(c.name, strings.NewReader(c.file))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Package tracking reconciles licenses with a state seed-to-sale
+// track-and-trace system that has a Metrc-style REST API.
+//
+// APIClient talks to such an API; Mock serves the same API from memory for
+// tests and offline development. Reconciler compares the facilities the
+// system knows with the licenses and locations of its jurisdiction and
+// notifies the businesses concerned of every mismatch.
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds each request of an APIClient without its own
+// http.Client
+const DefaultTimeout = 30 * time.Second
+
+// Client is what reconciliation needs from a tracking system
+type Client interface {
+	// Facilities are the licensed facilities the API keys have access to
+	Facilities(ctx context.Context) ([]Facility, error)
+	// ActivePackages counts the active packages of the facility with the
+	// license number
+	ActivePackages(ctx context.Context, licenseNumber string) (int, error)
+}
+
+// Facility is a licensed premises as the tracking system records it
+type Facility struct {
+	Name        string          `json:"Name"`
+	DisplayName string          `json:"DisplayName,omitempty"`
+	License     FacilityLicense `json:"License"`
+	// Address is nil when the system does not report one
+	Address *Address `json:"PhysicalAddress,omitempty"`
+}
+
+// FacilityLicense is the state license of a facility
+type FacilityLicense struct {
+	Number string `json:"Number"`
+	// StartDate and EndDate are dates, "YYYY-MM-DD"
+	StartDate   string `json:"StartDate"`
+	EndDate     string `json:"EndDate"`
+	LicenseType string `json:"LicenseType"`
+}
+
+// Address is a facility's physical address
+type Address struct {
+	Street1    string `json:"Street1"`
+	Street2    string `json:"Street2,omitempty"`
+	City       string `json:"City"`
+	State      string `json:"State"`
+	PostalCode string `json:"PostalCode"`
+}
+
+// Package is an inventory package; only its identity matters here
+type Package struct {
+	ID    int    `json:"Id"`
+	Label string `json:"Label"`
+}
+
+// APIError is a response outside the 2xx range
+type APIError struct {
+	Status int
+	Body   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("tracking API responded %d %s: %s", e.Status, http.StatusText(e.Status), e.Body)
+}
+
+// APIClient is a Client for a Metrc-style REST API. Requests authenticate
+// with HTTP basic auth, the vendor (software) key as the user name and the
+// user key as the password.
+type APIClient struct {
+	// BaseURL is the API root of the state, e.g. https://api-ca.metrc.com
+	BaseURL   string
+	VendorKey string
+	UserKey   string
+	// HTTP sends the requests; if nil, one with DefaultTimeout
+	HTTP *http.Client
+}
+
+// Facilities implements Client
+func (c *APIClient) Facilities(ctx context.Context) ([]Facility, error) {
+	var facilities []Facility
+	if err := c.get(ctx, "/facilities/v1/", nil, &facilities); err != nil {
+		return nil, fmt.Errorf("failed to list facilities: %w", err)
+	}
+	return facilities, nil
+}
+
+// ActivePackages implements Client
+func (c *APIClient) ActivePackages(ctx context.Context, licenseNumber string) (int, error) {
+	var packages []Package
+	query := url.Values{"licenseNumber": {licenseNumber}}
+	if err := c.get(ctx, "/packages/v1/active", query, &packages); err != nil {
+		return 0, fmt.Errorf("failed to list active packages of %s: %w", licenseNumber, err)
+	}
+	return len(packages), nil
+}
+
+func (c *APIClient) get(ctx context.Context, path string, query url.Values, dest any) error {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.VendorKey, c.UserKey)
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTP
+	if client == nil {
+		client = &http.Client{Timeout: DefaultTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &APIError{Status: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,108 @@
+package tracking
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// MockData is what a Mock serves. It reads from JSON, so that a fixture
+// file can stand in for a state's system during development.
+type MockData struct {
+	Facilities []Facility `json:"facilities"`
+	// Packages counts the active packages by license number
+	Packages map[string]int `json:"packages"`
+}
+
+// Mock serves the part of a Metrc-style API that APIClient uses from
+// memory. It checks the basic auth keys like the real API, and scopes
+// package requests to the facilities it knows.
+type Mock struct {
+	VendorKey string
+	UserKey   string
+
+	mu   sync.Mutex
+	data MockData
+}
+
+// NewMock returns a Mock serving data that accepts the given keys
+func NewMock(vendorKey, userKey string, data MockData) *Mock {
+	if data.Facilities == nil {
+		data.Facilities = []Facility{}
+	}
+	if data.Packages == nil {
+		data.Packages = map[string]int{}
+	}
+	return &Mock{VendorKey: vendorKey, UserKey: userKey, data: data}
+}
+
+// AddFacility adds a facility, or replaces the one with its license number
+func (m *Mock) AddFacility(f Facility) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.data.Facilities {
+		if existing.License.Number == f.License.Number {
+			m.data.Facilities[i] = f
+			return
+		}
+	}
+	m.data.Facilities = append(m.data.Facilities, f)
+}
+
+// SetPackages sets how many active packages the facility has
+func (m *Mock) SetPackages(licenseNumber string, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data.Packages[licenseNumber] = n
+}
+
+// ServeHTTP implements http.Handler
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	vendor, user, ok := r.BasicAuth()
+	if !ok || subtle.ConstantTimeCompare([]byte(vendor), []byte(m.VendorKey)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(user), []byte(m.UserKey)) != 1 {
+		http.Error(w, `{"Message":"Authorization has been denied for this request."}`, http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, `{"Message":"The requested resource does not support this method."}`, http.StatusMethodNotAllowed)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "/facilities/v1":
+		writeJSON(w, m.data.Facilities)
+	case "/packages/v1/active":
+		number := r.URL.Query().Get("licenseNumber")
+		if !m.knows(number) {
+			http.Error(w, `{"Message":"No valid license number was specified."}`, http.StatusUnauthorized)
+			return
+		}
+		packages := make([]Package, m.data.Packages[number])
+		for i := range packages {
+			packages[i] = Package{ID: i + 1, Label: fmt.Sprintf("1A4FF01000000%011d", i+1)}
+		}
+		writeJSON(w, packages)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *Mock) knows(licenseNumber string) bool {
+	for _, f := range m.data.Facilities {
+		if f.License.Number == licenseNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
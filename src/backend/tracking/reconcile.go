@@ -0,0 +1,264 @@
+package tracking
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Kinds of mismatch
+const (
+	// MissingFacility: an active license the system has no facility for
+	MissingFacility = "missing_facility"
+	// ExpirationMismatch: the license expires on another date there
+	ExpirationMismatch = "expiration"
+	// AddressMismatch: the license's location is elsewhere there
+	AddressMismatch = "address"
+	// InactiveInventory: a license that is not active still holds
+	// inventory there
+	InactiveInventory = "inactive_inventory"
+)
+
+// License is a license of the jurisdiction with its location, if any
+type License struct {
+	ID             string  `db:"id"`
+	BusinessID     string  `db:"business_id"`
+	Number         string  `db:"license_number"`
+	Status         string  `db:"status"`
+	ExpirationDate string  `db:"expiration_date"`
+	Address        *string `db:"address"`
+	City           *string `db:"city"`
+	State          *string `db:"state"`
+	ZipCode        *string `db:"zip_code"`
+}
+
+// Mismatch is a difference between a license and the tracking system
+type Mismatch struct {
+	License License
+	Kind    string
+	// Ours and Theirs are the differing values, as shown to users
+	Ours   string
+	Theirs string
+}
+
+// Message describes the mismatch to the business
+func (m Mismatch) Message() string {
+	switch m.Kind {
+	case MissingFacility:
+		return fmt.Sprintf("License %s is %s here but has no facility in the state tracking system.", m.License.Number, m.Ours)
+	case ExpirationMismatch:
+		return fmt.Sprintf("License %s expires on %s here but on %s in the state tracking system.", m.License.Number, m.Ours, m.Theirs)
+	case AddressMismatch:
+		return fmt.Sprintf("License %s is at %s here but at %s in the state tracking system.", m.License.Number, m.Ours, m.Theirs)
+	case InactiveInventory:
+		return fmt.Sprintf("License %s is %s but holds %s active package(s) in the state tracking system.", m.License.Number, m.Ours, m.Theirs)
+	}
+	return fmt.Sprintf("License %s differs from the state tracking system.", m.License.Number)
+}
+
+// active reports whether a license in the status may operate
+func active(status string) bool {
+	return status == "ACTIVE" || status == "RENEWAL_IN_PROGRESS"
+}
+
+// Compare finds the mismatches between the licenses and the facilities.
+// packages counts the active packages of the licenses that are not active,
+// by license number; licenses it lacks are not checked for inventory.
+// Facilities without a license here are left alone, as there is no
+// business to tell.
+func Compare(licenses []License, facilities []Facility, packages map[string]int) []Mismatch {
+	byNumber := make(map[string]Facility, len(facilities))
+	for _, f := range facilities {
+		byNumber[normalizeNumber(f.License.Number)] = f
+	}
+
+	var mismatches []Mismatch
+	for _, lic := range licenses {
+		f, found := byNumber[normalizeNumber(lic.Number)]
+		if !active(lic.Status) {
+			if n := packages[lic.Number]; found && n > 0 {
+				mismatches = append(mismatches, Mismatch{License: lic, Kind: InactiveInventory, Ours: lic.Status, Theirs: fmt.Sprint(n)})
+			}
+			continue
+		}
+		if !found {
+			mismatches = append(mismatches, Mismatch{License: lic, Kind: MissingFacility, Ours: lic.Status})
+			continue
+		}
+		if end := f.License.EndDate; len(end) >= 10 && end[:10] != lic.ExpirationDate {
+			mismatches = append(mismatches, Mismatch{License: lic, Kind: ExpirationMismatch, Ours: lic.ExpirationDate, Theirs: end[:10]})
+		}
+		if f.Address != nil && lic.Address != nil {
+			ours := Address{Street1: *lic.Address, City: deref(lic.City), State: deref(lic.State), PostalCode: deref(lic.ZipCode)}
+			if !sameAddress(ours, *f.Address) {
+				mismatches = append(mismatches, Mismatch{License: lic, Kind: AddressMismatch, Ours: ours.String(), Theirs: f.Address.String()})
+			}
+		}
+	}
+	return mismatches
+}
+
+// String formats the address on one line
+func (a Address) String() string {
+	street := a.Street1
+	if a.Street2 != "" {
+		street += " " + a.Street2
+	}
+	return fmt.Sprintf("%s, %s, %s %s", street, a.City, a.State, a.PostalCode)
+}
+
+func normalizeNumber(number string) string {
+	return strings.ToUpper(strings.TrimSpace(number))
+}
+
+// streetAbbreviations shorten the words addresses spell either way
+var streetAbbreviations = map[string]string{
+	"street": "st", "avenue": "ave", "boulevard": "blvd", "road": "rd", "drive": "dr", "lane": "ln",
+	"court": "ct", "place": "pl", "highway": "hwy", "parkway": "pkwy", "suite": "ste",
+	"north": "n", "south": "s", "east": "e", "west": "w",
+}
+
+// normalizeAddress reduces an address line to lowercase words without
+// punctuation, with the usual abbreviations
+func normalizeAddress(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		if short, ok := streetAbbreviations[w]; ok {
+			words[i] = short
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// sameAddress compares the street, city, state and five-digit ZIP code
+func sameAddress(a, b Address) bool {
+	zip := func(s string) string {
+		s = strings.TrimSpace(s)
+		return s[:min(len(s), 5)]
+	}
+	return normalizeAddress(a.Street1+" "+a.Street2) == normalizeAddress(b.Street1+" "+b.Street2) &&
+		normalizeAddress(a.City) == normalizeAddress(b.City) &&
+		strings.EqualFold(strings.TrimSpace(a.State), strings.TrimSpace(b.State)) &&
+		zip(a.PostalCode) == zip(b.PostalCode)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Reconciler compares the licenses of one jurisdiction with its tracking
+// system daily and sends each mismatch to the business's owners and
+// compliance managers as a COMPLIANCE_ISSUE notification about the
+// license. A mismatch is notified once for as long as it persists
+// unchanged.
+type Reconciler struct {
+	DB     *sqlx.DB
+	Client Client
+	// Jurisdiction is the name of the jurisdiction the system covers
+	Jurisdiction string
+}
+
+// Run reconciles once. It is a scheduler job.
+func (r *Reconciler) Run(ctx context.Context) error {
+	mismatches, err := r.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.DB.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	created, err := notify(ctx, tx, mismatches)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tracking reconciliation: %w", err)
+	}
+	log.Printf("tracking: %s: %d mismatch(es), created %d notification(s)", r.Jurisdiction, len(mismatches), created)
+	return nil
+}
+
+// Reconcile returns the mismatches between the jurisdiction's licenses and
+// the tracking system. Revoked and expired licenses are only checked for
+// leftover inventory, and are skipped when the system fails to count it.
+func (r *Reconciler) Reconcile(ctx context.Context) ([]Mismatch, error) {
+	var licenses []License
+	err := r.DB.SelectContext(ctx, &licenses, `
+		SELECT l.id, l.business_id, l.license_number, l.status, l.expiration_date::text,
+		       loc.address, loc.city, loc.state, loc.zip_code
+		FROM licenses l
+		JOIN jurisdictions j ON j.id = l.jurisdiction_id
+		LEFT JOIN locations loc ON loc.id = l.location_id
+		WHERE j.name = $1
+		ORDER BY l.license_number
+	`, r.Jurisdiction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load licenses: %w", err)
+	}
+	if len(licenses) == 0 {
+		return nil, nil
+	}
+
+	facilities, err := r.Client.Facilities(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byNumber := make(map[string]Facility, len(facilities))
+	for _, f := range facilities {
+		byNumber[normalizeNumber(f.License.Number)] = f
+	}
+	packages := map[string]int{}
+	for _, lic := range licenses {
+		f, found := byNumber[normalizeNumber(lic.Number)]
+		if active(lic.Status) || !found {
+			continue
+		}
+		// Ask for the number as the system spells it. A failure skips the
+		// inventory check of this license only.
+		n, err := r.Client.ActivePackages(ctx, f.License.Number)
+		if err != nil {
+			log.Printf("tracking: %s: skipped the inventory check of license %s: %v", r.Jurisdiction, lic.Number, err)
+			continue
+		}
+		packages[lic.Number] = n
+	}
+	return Compare(licenses, facilities, packages), nil
+}
+
+// notify creates the notifications of the mismatches not notified before
+// and returns how many were new
+func notify(ctx context.Context, tx *sqlx.Tx, mismatches []Mismatch) (int64, error) {
+	var created int64
+	for _, m := range mismatches {
+		dedupeKey := fmt.Sprintf("tracking:%s:%s:%s:%s", m.Kind, m.License.ID, m.Ours, m.Theirs)
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO notifications (user_id, title, message, type, related_entity_id, related_entity_type, dedupe_key)
+			SELECT user_id, $2, $3, 'COMPLIANCE_ISSUE', $4, 'License', $5
+			FROM business_members
+			WHERE business_id = $1 AND role IN ('BUSINESS_OWNER', 'COMPLIANCE_MANAGER')
+			ON CONFLICT (user_id, dedupe_key) DO NOTHING
+		`, m.License.BusinessID, "State tracking mismatch for license "+m.License.Number, m.Message(), m.License.ID, dedupeKey)
+		if err != nil {
+			return created, fmt.Errorf("failed to create tracking mismatch notification: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return created, err
+		}
+		created += n
+	}
+	return created, nil
+}
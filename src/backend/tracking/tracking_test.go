@@ -0,0 +1,113 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockServer(t *testing.T) (*Mock, *APIClient) {
+	t.Helper()
+	mock := NewMock("vendor-key", "user-key", MockData{})
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+	return mock, &APIClient{BaseURL: server.URL, VendorKey: "vendor-key", UserKey: "user-key"}
+}
+
+func TestAPIClient_AgainstMock(t *testing.T) {
+	mock, client := mockServer(t)
+	ctx := context.Background()
+
+	facilities, err := client.Facilities(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, facilities)
+
+	facility := Facility{
+		Name:    "Green Leaf",
+		License: FacilityLicense{Number: "C10-0000001-LIC", StartDate: "2024-01-01", EndDate: "2026-01-01", LicenseType: "Retailer"},
+		Address: &Address{Street1: "1 Main St", City: "Sacramento", State: "CA", PostalCode: "95814"},
+	}
+	mock.AddFacility(facility)
+	mock.SetPackages("C10-0000001-LIC", 3)
+
+	facilities, err = client.Facilities(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Facility{facility}, facilities)
+
+	n, err := client.ActivePackages(ctx, "C10-0000001-LIC")
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	_, err = client.ActivePackages(ctx, "C10-9999999-LIC")
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+}
+
+func TestAPIClient_WrongKeys(t *testing.T) {
+	_, client := mockServer(t)
+	client.UserKey = "someone-else"
+
+	_, err := client.Facilities(context.Background())
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	assert.Contains(t, apiErr.Body, "denied")
+}
+
+func ptr(s string) *string { return &s }
+
+func TestCompare(t *testing.T) {
+	facilities := []Facility{
+		{License: FacilityLicense{Number: "C10-0000001-LIC", EndDate: "2026-01-01"},
+			Address: &Address{Street1: "1 Main Street", City: "Sacramento", State: "CA", PostalCode: "95814-1234"}},
+		{License: FacilityLicense{Number: "C10-0000002-LIC", EndDate: "2026-06-30T00:00:00"}},
+		{License: FacilityLicense{Number: "C10-0000003-LIC", EndDate: "2026-01-01"},
+			Address: &Address{Street1: "9 Oak Ave", City: "Fresno", State: "CA", PostalCode: "93701"}},
+		{License: FacilityLicense{Number: "C10-0000005-LIC", EndDate: "2026-01-01"}},
+		{License: FacilityLicense{Number: "C10-0000009-LIC", EndDate: "2026-01-01"}},
+	}
+	licenses := []License{
+		// Matches, spelled differently
+		{ID: "1", Number: "c10-0000001-lic ", Status: "ACTIVE", ExpirationDate: "2026-01-01",
+			Address: ptr("1 Main St."), City: ptr("sacramento"), State: ptr("ca"), ZipCode: ptr("95814")},
+		{ID: "2", Number: "C10-0000002-LIC", Status: "RENEWAL_IN_PROGRESS", ExpirationDate: "2026-01-01"},
+		{ID: "3", Number: "C10-0000003-LIC", Status: "ACTIVE", ExpirationDate: "2026-01-01",
+			Address: ptr("12 Elm St"), City: ptr("Fresno"), State: ptr("CA"), ZipCode: ptr("93701")},
+		{ID: "4", Number: "C10-0000004-LIC", Status: "ACTIVE", ExpirationDate: "2026-01-01"},
+		{ID: "5", Number: "C10-0000005-LIC", Status: "REVOKED", ExpirationDate: "2026-01-01"},
+		// Not active and not in the system: nothing to reconcile
+		{ID: "6", Number: "C10-0000006-LIC", Status: "EXPIRED", ExpirationDate: "2024-01-01"},
+	}
+
+	mismatches := Compare(licenses, facilities, map[string]int{"C10-0000005-LIC": 12})
+	kinds := map[string]string{}
+	for _, m := range mismatches {
+		kinds[m.License.ID] = m.Kind
+	}
+	assert.Equal(t, map[string]string{
+		"2": ExpirationMismatch,
+		"3": AddressMismatch,
+		"4": MissingFacility,
+		"5": InactiveInventory,
+	}, kinds)
+
+	for _, m := range mismatches {
+		switch m.Kind {
+		case ExpirationMismatch:
+			assert.Equal(t, "2026-06-30", m.Theirs)
+		case AddressMismatch:
+			assert.Equal(t, "12 Elm St, Fresno, CA 93701", m.Ours)
+			assert.Equal(t, "9 Oak Ave, Fresno, CA 93701", m.Theirs)
+		case InactiveInventory:
+			assert.Equal(t, "License C10-0000005-LIC is REVOKED but holds 12 active package(s) in the state tracking system.", m.Message())
+		}
+	}
+
+	assert.Empty(t, Compare(licenses[:1], facilities, nil))
+}
@@ -0,0 +1,244 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Delivery statuses, matching the WebhookDeliveryStatus enum
+const (
+	StatusPending   = "PENDING"
+	StatusRetrying  = "RETRYING"
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+)
+
+// Defaults of the Dispatcher settings
+const (
+	DefaultMaxAttempts = 10
+	DefaultBatchSize   = 100
+	DefaultWorkers     = 8
+	DefaultTimeout     = 10 * time.Second
+	backoffBase        = 30 * time.Second
+	backoffMax         = 12 * time.Hour
+	// userAgent identifies the sender to receivers
+	userAgent = "BudSafe-Webhooks/1.0"
+)
+
+// Backoff is the wait after the given number of failed attempts: thirty
+// seconds after the first, doubling up to twelve hours
+func Backoff(attempts int) time.Duration {
+	wait := backoffBase
+	for i := 1; i < attempts && wait < backoffMax; i++ {
+		wait *= 2
+	}
+	return min(wait, backoffMax)
+}
+
+// Dispatcher sends the queued webhook deliveries that are due. A response
+// in the 2xx range succeeds; anything else, or no response, is retried.
+// Deliveries of inactive subscriptions wait until they are active again.
+// It relies on the scheduler to run on one replica at a time.
+type Dispatcher struct {
+	DB *sqlx.DB
+	// Client sends the requests; if nil, one with DefaultTimeout that does
+	// not follow redirects and only connects to public addresses
+	Client *http.Client
+	// AllowLocalhost lets the default client connect to loopback
+	// addresses, for development
+	AllowLocalhost bool
+	// MaxAttempts is how often a delivery is tried before it fails
+	MaxAttempts int
+	BatchSize   int
+	// Workers is how many deliveries are sent at once
+	Workers int
+	// Now defaults to time.Now
+	Now func() time.Time
+
+	clientOnce    sync.Once
+	defaultClient *http.Client
+}
+
+// dueDelivery is a queued delivery with its subscription's endpoint
+type dueDelivery struct {
+	ID        string `db:"id"`
+	EventType string `db:"event_type"`
+	Payload   []byte `db:"payload"`
+	Attempts  int    `db:"attempts"`
+	URL       string `db:"url"`
+	Secret    string `db:"secret"`
+}
+
+// result is the outcome of one attempt
+type result struct {
+	status int
+	err    error
+}
+
+// Run sends the due deliveries. It is a scheduler job.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	succeeded, failed, err := d.Send(ctx)
+	if err != nil {
+		return err
+	}
+	if succeeded+failed > 0 {
+		log.Printf("webhook: %d delivered, %d failed delivery attempt(s)", succeeded, failed)
+	}
+	return nil
+}
+
+// Send attempts the deliveries that are due and returns how many succeeded
+// and how many attempts failed
+func (d *Dispatcher) Send(ctx context.Context) (succeeded, failed int, err error) {
+	batch := d.BatchSize
+	if batch == 0 {
+		batch = DefaultBatchSize
+	}
+	var due []dueDelivery
+	err = d.DB.SelectContext(ctx, &due, `
+		SELECT d.id, d.event_type, d.payload, d.attempts, s.url, s.secret
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE s.active
+		  AND d.status IN ('PENDING', 'RETRYING')
+		  AND d.next_attempt_at <= $1
+		ORDER BY d.next_attempt_at, d.created_at
+		LIMIT $2
+	`, d.now(), batch)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load due webhook deliveries: %w", err)
+	}
+
+	workers := d.Workers
+	if workers == 0 {
+		workers = DefaultWorkers
+	}
+	results := make([]result, len(due))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, delivery := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].status, results[i].err = d.post(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	for i, delivery := range due {
+		if err := d.record(ctx, delivery, results[i]); err != nil {
+			return succeeded, failed, err
+		}
+		if results[i].err != nil {
+			failed++
+		} else {
+			succeeded++
+		}
+	}
+	return succeeded, failed, nil
+}
+
+// post sends one delivery and returns the response status, if any
+func (d *Dispatcher) post(ctx context.Context, delivery dueDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, d.now(), delivery.Payload))
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so that the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// client returns the Client, or else the default client
+func (d *Dispatcher) client() *http.Client {
+	d.clientOnce.Do(func() {
+		if d.Client != nil {
+			d.defaultClient = d.Client
+			return
+		}
+		dialer := &net.Dialer{Timeout: DefaultTimeout, Control: checkDial(d.AllowLocalhost)}
+		d.defaultClient = &http.Client{
+			Timeout: DefaultTimeout,
+			Transport: &http.Transport{
+				// No proxy: the dial check must see the receiver's address
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				TLSHandshakeTimeout: DefaultTimeout,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			// A redirect would turn the POST into a GET; it fails instead
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	})
+	return d.defaultClient
+}
+
+// record stores the outcome of an attempt: succeeded, failed once out of
+// attempts, or else retried after a backoff
+func (d *Dispatcher) record(ctx context.Context, delivery dueDelivery, res result) error {
+	maxAttempts := d.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	attempts := delivery.Attempts + 1
+	status, next, lastError := StatusSucceeded, d.now(), (*string)(nil)
+	if res.err != nil {
+		msg := res.err.Error()
+		lastError = &msg
+		if attempts >= maxAttempts {
+			status = StatusFailed
+		} else {
+			status, next = StatusRetrying, d.now().Add(Backoff(attempts))
+		}
+		log.Printf("webhook: delivery %s to %s: attempt %d: %s: %v", delivery.ID, delivery.URL, attempts, status, res.err)
+	}
+	var responseStatus *int
+	if res.status != 0 {
+		responseStatus = &res.status
+	}
+
+	_, err := d.DB.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, response_status = $5, last_error = $6,
+		    delivered_at = CASE WHEN $2 = 'SUCCEEDED' THEN $4 END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`, delivery.ID, status, attempts, next, responseStatus, lastError)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery %s: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+func (d *Dispatcher) now() time.Time {
+	if d.Now != nil {
+		return d.Now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,185 @@
+// Package webhook delivers business events to the URLs businesses
+// subscribe.
+//
+// Database triggers queue a delivery in webhook_deliveries for every event
+// and matching subscription, in the transaction that caused the event, so
+// no event is lost. Dispatcher POSTs the JSON payloads, signed with the
+// subscription's secret, and retries failures with exponential backoff.
+//
+// Receivers verify the X-BudSafe-Signature header, "t=<unix time>,v1=<hex>",
+// where the hex is the HMAC-SHA256 of "<unix time>.<body>" keyed with the
+// secret; Verify does so in Go.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Event types
+const (
+	EventLicenseStatusChanged  = "license.status_changed"
+	EventComplianceCheckFailed = "compliance_check.failed"
+	EventNotificationCreated   = "notification.created"
+)
+
+// EventTypes are the event types a subscription can choose from
+var EventTypes = []string{EventLicenseStatusChanged, EventComplianceCheckFailed, EventNotificationCreated}
+
+// Request headers of a delivery
+const (
+	HeaderEvent     = "X-BudSafe-Event"
+	HeaderDelivery  = "X-BudSafe-Delivery"
+	HeaderSignature = "X-BudSafe-Signature"
+)
+
+// NewSecret returns a random signing secret
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign returns the signature header of body sent at t
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+// Verify checks a signature header against body and rejects signatures
+// older than tolerance, so that captured requests cannot be replayed later
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return errors.New("webhook: malformed signature header")
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > tolerance || age < -tolerance {
+		return errors.New("webhook: signature timestamp out of tolerance")
+	}
+	expected := mac(secret, ts, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return errors.New("webhook: signature mismatch")
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CheckURL reports why raw cannot receive webhooks. Payloads are only sent
+// over HTTPS to public hosts; with allowLocalhost, for development, also
+// over HTTP to localhost. The dispatcher checks the address it actually
+// connects to as well, since a public name can resolve to a private address.
+func CheckURL(raw string, allowLocalhost bool) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Opaque != "" {
+		return fmt.Errorf("%q is not an absolute URL", raw)
+	}
+	if u.User != nil {
+		return errors.New("URL must not contain credentials")
+	}
+	host := u.Hostname()
+	local := host == "localhost" || strings.HasSuffix(host, ".localhost")
+	if ip := net.ParseIP(host); ip != nil {
+		local = ip.IsLoopback()
+		if !local && !publicIP(ip) {
+			return fmt.Errorf("%s is not a public address", host)
+		}
+	}
+	if local && !allowLocalhost {
+		return errors.New("URL must not point to localhost")
+	}
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if local {
+			return nil
+		}
+		return errors.New("URL must use https")
+	}
+	return fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+}
+
+// nonPublicNets are ranges that net.IP has no predicate for: "this
+// network" and carrier-grade NAT, where some clouds serve instance metadata
+var nonPublicNets = []*net.IPNet{
+	mustCIDR("0.0.0.0/8"),
+	mustCIDR("100.64.0.0/10"),
+}
+
+func mustCIDR(s string) *net.IPNet {
+	_, n, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// publicIP reports whether ip is a unicast address on the public internet,
+// so not loopback, private, link-local (such as the cloud metadata address
+// 169.254.169.254), multicast or unspecified
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDial refuses connections to addresses that are not public, and with
+// allowLocalhost lets loopback addresses through. It is a net.Dialer
+// Control hook, so it sees the address after name resolution and a name
+// that resolves differently at delivery time cannot get around it.
+func checkDial(allowLocalhost bool) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return fmt.Errorf("webhook: refusing to connect to %s", address)
+		}
+		if publicIP(ip) || (allowLocalhost && ip.IsLoopback()) {
+			return nil
+		}
+		return fmt.Errorf("webhook: refusing to connect to non-public address %s", ip)
+	}
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret, err := NewSecret()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(secret, "whsec_"))
+
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"license.status_changed"}`)
+	header := Sign(secret, now, body)
+	assert.True(t, strings.HasPrefix(header, "t=1792314000,v1="))
+
+	assert.NoError(t, Verify(secret, header, body, 5*time.Minute, now.Add(time.Minute)))
+	assert.Error(t, Verify(secret, header, []byte(`{}`), 5*time.Minute, now), "other body")
+	assert.Error(t, Verify("whsec_other", header, body, 5*time.Minute, now), "other secret")
+	assert.Error(t, Verify(secret, header, body, 5*time.Minute, now.Add(time.Hour)), "replayed")
+	assert.Error(t, Verify(secret, "v1=abc", body, 5*time.Minute, now), "malformed")
+}
+
+func TestCheckURL(t *testing.T) {
+	for _, ok := range []string{"https://erp.example.com/hooks/budsafe", "https://203.0.113.10/hook", "https://[2001:db8::1]/hook"} {
+		assert.NoError(t, CheckURL(ok, false), ok)
+	}
+	for _, bad := range []string{"", "erp.example.com/hook", "http://erp.example.com/hook", "ftp://example.com", "https://user:pw@example.com", "mailto:ops@example.com"} {
+		assert.Error(t, CheckURL(bad, false), bad)
+	}
+
+	// Internal addresses are refused, localhost only in development
+	for _, internal := range []string{
+		"https://10.0.0.5/hook", "https://172.16.0.1/hook", "https://192.168.1.1/hook",
+		"https://169.254.169.254/latest/meta-data", "https://100.100.100.200/", "https://0.0.0.0/hook",
+		"https://[fd00:ec2::254]/hook", "https://[fe80::1]/hook", "https://[::ffff:10.0.0.1]/hook",
+	} {
+		assert.Error(t, CheckURL(internal, true), internal)
+	}
+	for _, local := range []string{"http://localhost:8080/hook", "http://127.0.0.1/hook", "https://app.localhost/hook", "https://[::1]/hook"} {
+		assert.Error(t, CheckURL(local, false), local)
+		assert.NoError(t, CheckURL(local, true), local)
+	}
+}
+
+func TestCheckDial(t *testing.T) {
+	check := checkDial(false)
+	assert.NoError(t, check("tcp4", "203.0.113.10:443", nil))
+	assert.NoError(t, check("tcp6", "[2001:db8::1]:443", nil))
+	for _, refused := range []string{
+		"127.0.0.1:443", "10.1.2.3:443", "172.31.255.255:443", "192.168.0.10:80", "169.254.169.254:80",
+		"100.64.0.1:443", "0.0.0.0:443", "[::]:443", "[::1]:443", "[fc00::1]:443", "[fe80::1]:443", "[::ffff:192.168.0.1]:443",
+	} {
+		assert.Error(t, check("tcp", refused, nil), refused)
+	}
+
+	assert.NoError(t, checkDial(true)("tcp4", "127.0.0.1:8080", nil))
+	assert.Error(t, checkDial(true)("tcp4", "10.0.0.1:8080", nil))
+}
+
+func TestPostRefusesInternalAddresses(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	// A name that resolves to loopback is refused at connect time
+	_, port, _ := strings.Cut(server.URL, "127.0.0.1:")
+	delivery := dueDelivery{ID: "d1", EventType: EventNotificationCreated, Payload: []byte(`{}`), URL: "http://localhost:" + port, Secret: "whsec_test"}
+	status, err := (&Dispatcher{}).post(context.Background(), delivery)
+	assert.ErrorContains(t, err, "non-public address")
+	assert.Zero(t, status)
+	assert.False(t, reached)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 12*time.Hour, Backoff(30))
+}
+
+func TestPost(t *testing.T) {
+	now := time.Now()
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	d := &Dispatcher{AllowLocalhost: true, Now: func() time.Time { return now }}
+	delivery := dueDelivery{
+		ID:        "d1",
+		EventType: EventComplianceCheckFailed,
+		Payload:   []byte(`{"id":"e1"}`),
+		URL:       server.URL + "/ok",
+		Secret:    "whsec_test",
+	}
+	status, err := d.post(context.Background(), delivery)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, EventComplianceCheckFailed, received.Header.Get(HeaderEvent))
+	assert.Equal(t, "d1", received.Header.Get(HeaderDelivery))
+	assert.NoError(t, Verify("whsec_test", received.Header.Get(HeaderSignature), body, time.Minute, now))
+
+	delivery.URL = server.URL + "/fail"
+	status, err = d.post(context.Background(), delivery)
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}